@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,13 +9,18 @@ import (
 	"time"
 
 	"github.com/dksensei/letsnormalizeit/internal/auth"
+	"github.com/dksensei/letsnormalizeit/internal/blog"
+	"github.com/dksensei/letsnormalizeit/internal/cache"
+	"github.com/dksensei/letsnormalizeit/internal/comment"
 	"github.com/dksensei/letsnormalizeit/internal/config"
 	"github.com/dksensei/letsnormalizeit/internal/db"
-	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/dksensei/letsnormalizeit/internal/health"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/ratelimit"
+	"github.com/dksensei/letsnormalizeit/internal/server"
+	"github.com/dksensei/letsnormalizeit/internal/tracing"
 	"github.com/dksensei/letsnormalizeit/internal/user"
 	"github.com/dksensei/letsnormalizeit/internal/utils"
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
 )
 
 func main() {
@@ -33,214 +37,106 @@ func main() {
 	utils.InitWithConfig(loggerConfig)
 	defer utils.Sync()
 
+	// Manage schema migrations instead of serving when asked to
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
+
 	// Log startup message
 	utils.Info("Starting LetsNormalizeIt-V2.0 server")
 	utils.Info("Configuration loaded successfully")
 
-	// Initialize Firebase Auth service
-	authService, err := auth.NewService(&cfg.Firebase)
+	// Initialize tracing, a no-op unless enabled
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
 	if err != nil {
-		utils.Fatal("Failed to initialize Firebase Auth: %v", err)
+		utils.Fatal("Failed to initialize tracing: %v", err)
 	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			utils.Error("Failed to flush traces: %v", err)
+		}
+	}()
 
-	// Initialize MongoDB connection
-	mongodb, err := db.NewMongoDB(&cfg.MongoDB)
+	// Initialize the auth service, tracing calls to the provider
+	authService, err := newAuthService(cfg)
+	if err != nil {
+		utils.Fatal("Failed to initialize %s auth: %v", cfg.Auth.Provider, err)
+	}
+	authService = auth.NewTracedService(authService, cfg.Auth.Provider)
+
+	// Background jobs run until shutdown
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	// Initialize MongoDB connection, retrying while it starts up
+	var mongodb *db.MongoDB
+	err = db.Retry(backgroundCtx, "MongoDB", &cfg.Startup, func() (err error) {
+		mongodb, err = db.NewMongoDB(&cfg.MongoDB)
+		return err
+	})
 	if err != nil {
 		utils.Fatal("Failed to connect to MongoDB: %v", err)
 	}
 	defer mongodb.Close(context.Background())
 
-	// Initialize Redis connection
-	redis, err := db.NewRedis(&cfg.Redis)
-	if err != nil {
-		utils.Fatal("Failed to connect to Redis: %v", err)
+	// Initialize Redis connection; without it the server runs degraded
+	redis := connectRedis(backgroundCtx, cfg)
+	if redis != nil {
+		defer redis.Close()
+		redis.Watch(backgroundCtx, cfg.Redis.ReconnectInterval)
 	}
-	defer redis.Close()
+
+	// Initialize cache
+	appCache := cache.New(newCacheStore(backgroundCtx, redis), &cfg.Redis)
+
+	// Cache verified tokens so they are not verified on every request
+	authService = newTokenCache(cfg, authService, redis)
 
 	// Initialize repositories
 	userRepo := user.NewRepository(mongodb)
+	blogRepo := blog.NewRepository(mongodb)
+	revisionRepo := blog.NewRevisionRepository(mongodb)
+	slugRepo := blog.NewSlugRepository(mongodb)
+	commentRepo := comment.NewRepository(mongodb)
 
-	// Initialize services
-	userService := user.NewService(userRepo, authService)
-
-	// Initialize handlers
-	userHandler := user.NewHandler(userService)
-
-	// Initialize rate limiter
-	rateLimiter := middleware.NewRateLimiter(100, time.Minute)
-	// Cleanup old entries every 5 minutes
-	rateLimiter.Cleanup(5 * time.Minute)
-
-	// Setup Gin router
-	router := gin.New() // Use New() instead of Default() to customize middleware
-
-	// Use our custom recovery middleware
-	router.Use(middleware.Recovery())
-
-	// Use logger middleware
-	router.Use(gin.Logger())
-
-	// Configure CORS
-	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowOrigins = []string{cfg.Server.AllowOrigins}
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
-	corsConfig.AllowCredentials = true
-	router.Use(cors.New(corsConfig))
-
-	// Apply rate limiting to all routes
-	router.Use(rateLimiter.RateLimit())
-
-	// User authentication routes (requires authentication middleware)
-	userAuth := router.Group("/api/v1/user")
-	userAuth.Use(middleware.AuthMiddleware(authService))
-	{
-		userAuth.POST("/login", userHandler.Login)
-		userAuth.POST("/login-or-register", userHandler.LoginWithAutoRegister)
-		userAuth.POST("/register", userHandler.RegisterUser)
-	}
+	// Bring the indexes the repositories rely on up to date
+	migrateOnStartup(backgroundCtx, cfg, mongodb)
 
-	// Public routes
-	public := router.Group("/api/v1")
-	{
-		public.GET("/blogs", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"message": "List of blogs"})
-		})
-
-		public.GET("/blogs/:id", func(c *gin.Context) {
-			id := c.Param("id")
-			c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Blog with ID: %s", id)})
-		})
-
-		public.GET("/blogs/:id/comments", func(c *gin.Context) {
-			id := c.Param("id")
-			c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Comments for blog ID: %s", id)})
-		})
-	}
-
-	// Protected routes (require authentication)
-	protected := router.Group("/api/v1")
-	protected.Use(middleware.AuthMiddleware(authService))
-	{
-		protected.POST("/blogs", func(c *gin.Context) {
-			uid, _ := c.Get("uid")
-			c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Created blog by user: %s", uid)})
-		})
-
-		protected.POST("/blogs/:id/like", func(c *gin.Context) {
-			id := c.Param("id")
-			uid, _ := c.Get("uid")
-
-			err := userService.ToggleLike(c.Request.Context(), uid.(string), id)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"message": fmt.Sprintf("Blog %s like toggled by user %s", id, uid),
-			})
-		})
-
-		protected.POST("/blogs/:id/bookmark", func(c *gin.Context) {
-			id := c.Param("id")
-			uid, _ := c.Get("uid")
-
-			err := userService.ToggleBookmark(c.Request.Context(), uid.(string), id)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"message": fmt.Sprintf("Blog %s bookmark toggled by user %s", id, uid),
-			})
-		})
-
-		protected.POST("/comments", func(c *gin.Context) {
-			uid, _ := c.Get("uid")
-			c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Comment created by user: %s", uid)})
-		})
-
-		protected.GET("/user/bookmarks", func(c *gin.Context) {
-			uid, _ := c.Get("uid")
-
-			// Get user with bookmarks
-			userData, err := userService.GetUserByID(c.Request.Context(), uid.(string))
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"user":      userData.Email,
-				"bookmarks": userData.Bookmarks,
-			})
-		})
-
-		protected.GET("/user/profile", func(c *gin.Context) {
-			uid, _ := c.Get("uid")
-
-			userData, err := userService.GetUserByID(c.Request.Context(), uid.(string))
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"id":              userData.ID,
-				"name":            userData.Name,
-				"email":           userData.Email,
-				"photo_url":       userData.PhotoURL,
-				"created_at":      userData.CreatedAt,
-				"bookmarks_count": len(userData.Bookmarks),
-				"likes_count":     len(userData.Likes),
-			})
-		})
-
-		protected.PUT("/user/profile", func(c *gin.Context) {
-			uid, _ := c.Get("uid")
-
-			var input struct {
-				Name string `json:"name"`
-			}
-
-			if err := c.ShouldBindJSON(&input); err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
-			}
-
-			userData, err := userService.UpdateUserProfile(c.Request.Context(), uid.(string), input.Name)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-				return
-			}
-
-			c.JSON(http.StatusOK, gin.H{
-				"id":         userData.ID,
-				"name":       userData.Name,
-				"email":      userData.Email,
-				"photo_url":  userData.PhotoURL,
-				"updated_at": userData.UpdatedAt,
-			})
-		})
+	// Initialize services
+	blogService := blog.NewService(blogRepo, revisionRepo, slugRepo, appCache)
+	commentService := comment.NewService(commentRepo, blogService, appCache, &cfg.Comments)
+	userService := user.NewService(userRepo, authService, blogService, commentService)
+
+	// Drop the comments and reactions of deleted blogs
+	blogService.OnDelete(commentService.DeleteBlogComments)
+	blogService.OnDelete(userService.RemoveBlogReactions)
+
+	// Publish scheduled blogs in the background until shutdown
+	blogService.StartScheduler(backgroundCtx, cfg.Blogs.SchedulerInterval)
+
+	// Check the databases for readiness; other subsystems register their own checks
+	healthChecks := health.NewRegistry(cfg.Server.HealthCheckTimeout)
+	healthChecks.Register("mongodb", health.CheckerFunc(mongodb.Ping))
+	if redis != nil {
+		healthChecks.RegisterOptional("redis", health.CheckerFunc(redis.Ping))
 	}
 
-	// Admin routes
-	admin := router.Group("/api/v1/admin")
-	admin.Use(middleware.AuthMiddleware(authService), middleware.AdminOnly(authService))
-	{
-		admin.GET("/users", func(c *gin.Context) {
-			// In a real implementation, we would call userService.ListUsers()
-			// with pagination parameters
-			c.JSON(http.StatusOK, gin.H{"message": "List of users (admin only)"})
-		})
-
-		// Route to set admin privileges (would require additional service method)
-		admin.POST("/users/:id/set-admin", func(c *gin.Context) {
-			id := c.Param("id")
-			c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Set admin privileges for user: %s", id)})
-		})
+	// Initialize the rate limiter shared by every route group
+	rateLimiter := newRateLimiter(backgroundCtx, cfg, redis)
+
+	// Setup the router with every middleware and route
+	router, err := server.BuildRouter(cfg, &server.Dependencies{
+		AuthService:    authService,
+		UserService:    userService,
+		BlogService:    blogService,
+		CommentService: commentService,
+		RateLimiter:    rateLimiter,
+		HealthChecks:   healthChecks,
+	})
+	if err != nil {
+		utils.Fatal("Failed to build router: %v", err)
 	}
 
 	// Create a context that listens for signals to gracefully shutdown
@@ -264,6 +160,12 @@ func main() {
 	<-quit
 	utils.Info("Shutting down server...")
 
+	// Report not ready and give load balancers time to stop routing here
+	// before connections are closed
+	healthChecks.Drain()
+	utils.Info("Draining for %s", cfg.Server.ShutdownDrain)
+	time.Sleep(cfg.Server.ShutdownDrain)
+
 	// Create a deadline context for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -273,3 +175,72 @@ func main() {
 
 	utils.Info("Server exited")
 }
+
+// newAuthService creates the token verifier selected by the auth provider
+func newAuthService(cfg *config.Config) (model.AuthService, error) {
+	if cfg.Auth.Provider == config.AuthProviderLocal {
+		utils.Warn("Using local JWT auth, tokens are not verified by Firebase")
+		return auth.NewLocalService(&cfg.Auth)
+	}
+	return auth.NewService(&cfg.Firebase)
+}
+
+// connectRedis connects to Redis, retrying while it starts up. It returns
+// nil when Redis is disabled, and a client that reattaches once Watch sees
+// Redis answer when every attempt failed.
+func connectRedis(ctx context.Context, cfg *config.Config) *db.Redis {
+	if !cfg.Redis.Enabled {
+		utils.Warn("Redis disabled, caches and rate limits are kept in memory per instance")
+		return nil
+	}
+
+	var redis *db.Redis
+	err := db.Retry(ctx, "Redis", &cfg.Startup, func() (err error) {
+		redis, err = db.NewRedis(&cfg.Redis)
+		return err
+	})
+	if err != nil {
+		utils.Warn("Starting without Redis, falling back to in-memory caches and rate limits until it is reachable: %v", err)
+		return db.OpenRedis(&cfg.Redis)
+	}
+	return redis
+}
+
+// newRateLimiter creates the configured rate limiter. The Redis one falls
+// back to the in-memory one, which is cleaned up until ctx is done, while
+// Redis is unreachable.
+func newRateLimiter(ctx context.Context, cfg *config.Config, redis *db.Redis) ratelimit.Limiter {
+	memory := ratelimit.NewMemoryLimiter()
+	memory.Cleanup(ctx, 5*time.Minute)
+	if cfg.RateLimit.Backend == config.RateLimitBackendMemory || redis == nil {
+		utils.Warn("Using in-memory rate limiting, quotas are not shared between instances")
+		return memory
+	}
+	return ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(redis), memory, redis.Available)
+}
+
+// newCacheStore creates the store of the application cache: Redis while it
+// is reachable, in-memory otherwise
+func newCacheStore(ctx context.Context, redis *db.Redis) cache.Store {
+	memory := cache.NewMemoryStore()
+	memory.Cleanup(ctx, time.Minute)
+	if redis == nil {
+		return memory
+	}
+	return cache.NewFallbackStore(cache.NewRedisStore(redis), memory, redis.Available)
+}
+
+// newTokenCache wraps the auth service with the verified-token cache, sharing
+// it through Redis when configured. With caching disabled every token is
+// checked for revocation instead.
+func newTokenCache(cfg *config.Config, authService model.AuthService, redis *db.Redis) model.AuthService {
+	var store cache.Store
+	if cfg.Auth.TokenCacheRedis && redis != nil {
+		// The in-memory LRU is the fallback while Redis is unreachable
+		store = cache.NewFallbackStore(cache.NewRedisStore(redis), nil, redis.Available)
+	}
+	if cfg.Auth.TokenCacheSize <= 0 && store == nil {
+		return auth.NewRevocationCheckedService(authService)
+	}
+	return auth.NewCachedService(authService, store, &cfg.Auth)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/db"
+	"github.com/dksensei/letsnormalizeit/internal/migrate"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+)
+
+const migrateUsage = `Usage: server migrate <command> [flags]
+
+Commands:
+  status              list migrations and whether they are applied
+  up [-to N]          apply pending migrations, up to version N if given
+  down [-steps N]     revert the last N applied migrations (default 1)
+  down -to N          revert applied migrations above version N; 0 reverts all
+
+Flags:
+  -dry-run            print what up or down would do without changing anything
+`
+
+// runMigrate runs the migrate subcommand with its arguments and returns the
+// process exit code
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	command := args[0]
+	switch command {
+	case "status", "up", "down":
+	default:
+		fmt.Fprintf(os.Stderr, "migrate: unknown command %q\n\n%s", command, migrateUsage)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("migrate "+command, flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, migrateUsage) }
+	dryRun := flags.Bool("dry-run", false, "print the plan without applying it")
+	to := flags.Int("to", -1, "target version")
+	steps := flags.Int("steps", 1, "number of migrations to revert")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	mongodb, err := db.NewMongoDB(&cfg.MongoDB)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: connect to MongoDB: %v\n", err)
+		return 1
+	}
+	defer mongodb.Close(context.Background())
+
+	migrator, err := migrate.New(mongodb, migrate.Migrations())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "status":
+		err = printMigrationStatus(ctx, migrator)
+	case "up":
+		var applied []*migrate.Migration
+		applied, err = migrator.Up(ctx, max(*to, 0), *dryRun)
+		printMigrations("Applied", "Would apply", applied, *dryRun)
+	case "down":
+		target := *to
+		if target < 0 {
+			target, err = downTarget(ctx, migrator, *steps)
+			if err != nil {
+				break
+			}
+		}
+		var reverted []*migrate.Migration
+		reverted, err = migrator.Down(ctx, target, *dryRun)
+		printMigrations("Reverted", "Would revert", reverted, *dryRun)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// downTarget returns the version to revert down to so that the last steps
+// applied migrations are reverted
+func downTarget(ctx context.Context, migrator *migrate.Migrator, steps int) (int, error) {
+	if steps < 1 {
+		return 0, fmt.Errorf("-steps must be at least 1")
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var applied []int
+	for _, status := range statuses {
+		if status.Applied {
+			applied = append(applied, status.Version)
+		}
+	}
+	if steps >= len(applied) {
+		return 0, nil
+	}
+	return applied[len(applied)-steps-1], nil
+}
+
+// printMigrationStatus prints every migration with its state
+func printMigrationStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tDESCRIPTION")
+	for _, status := range statuses {
+		state, appliedAt := "pending", "-"
+		if status.Applied {
+			state, appliedAt = "applied", status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Unknown {
+			state = "unknown"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, state, appliedAt, status.Description)
+	}
+	return w.Flush()
+}
+
+// printMigrations prints the migrations applied or reverted, or those that
+// would be on a dry run
+func printMigrations(verb, dryRunVerb string, migrations []*migrate.Migration, dryRun bool) {
+	if dryRun {
+		verb = dryRunVerb
+	}
+	if len(migrations) == 0 {
+		fmt.Println("Nothing to do")
+		return
+	}
+	for _, migration := range migrations {
+		fmt.Printf("%s %d: %s\n", verb, migration.Version, migration.Description)
+	}
+}
+
+// migrateOnStartup applies pending migrations when configured to, and
+// otherwise warns about them. Failing to apply them is fatal: the unique
+// email index guarantees no two users share an email, so the server must
+// not run without it.
+func migrateOnStartup(ctx context.Context, cfg *config.Config, mongodb *db.MongoDB) {
+	migrator, err := migrate.New(mongodb, migrate.Migrations())
+	if err != nil {
+		utils.Fatal("Invalid migrations: %v", err)
+	}
+
+	if !cfg.MongoDB.AutoMigrate {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			utils.Error("Failed to check schema migrations: %v", err)
+		} else if len(pending) > 0 {
+			utils.Warn("%d schema migrations pending, apply them with `server migrate up`", len(pending))
+		}
+		return
+	}
+
+	applied, err := migrator.Up(ctx, 0, false)
+	if err != nil {
+		utils.Fatal("Failed to apply schema migrations: %v", err)
+	}
+	if len(applied) > 0 {
+		utils.Info("Applied %d schema migrations", len(applied))
+	}
+}
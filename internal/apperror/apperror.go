@@ -0,0 +1,172 @@
+// Package apperror defines typed domain errors. Every error carries a Kind,
+// which decides the HTTP status, and a stable Code clients can match on.
+// Services declare their sentinel errors with the constructors below and wrap
+// them with fmt.Errorf("%w: ...") to add details.
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind classifies an error by how a client should react to it
+type Kind string
+
+const (
+	// KindValidation means the request is malformed or invalid
+	KindValidation Kind = "validation"
+	// KindUnauthorized means the request lacks valid credentials
+	KindUnauthorized Kind = "unauthorized"
+	// KindForbidden means the caller may not perform the action
+	KindForbidden Kind = "forbidden"
+	// KindNotFound means the resource does not exist
+	KindNotFound Kind = "not_found"
+	// KindConflict means the request conflicts with the resource's state
+	KindConflict Kind = "conflict"
+	// KindUnprocessable means the request is valid but cannot be carried out
+	KindUnprocessable Kind = "unprocessable"
+	// KindRateLimited means the caller has exhausted its quota
+	KindRateLimited Kind = "rate_limited"
+	// KindUnavailable means a dependency is temporarily unavailable
+	KindUnavailable Kind = "unavailable"
+	// KindInternal means anything else went wrong
+	KindInternal Kind = "internal"
+)
+
+// kindStatus maps each kind to its HTTP status code
+var kindStatus = map[Kind]int{
+	KindValidation:    http.StatusBadRequest,
+	KindUnauthorized:  http.StatusUnauthorized,
+	KindForbidden:     http.StatusForbidden,
+	KindNotFound:      http.StatusNotFound,
+	KindConflict:      http.StatusConflict,
+	KindUnprocessable: http.StatusUnprocessableEntity,
+	KindRateLimited:   http.StatusTooManyRequests,
+	KindUnavailable:   http.StatusServiceUnavailable,
+	KindInternal:      http.StatusInternalServerError,
+}
+
+// CodeInternal is the code of errors that are not typed
+const CodeInternal = "internal_error"
+
+// Errors shared by every part of the API
+var (
+	// ErrUnauthenticated is returned when a request needs a signed in user
+	ErrUnauthenticated = Unauthorized("unauthenticated", "authentication required")
+
+	// ErrPermissionDenied is returned when the user's roles lack a permission
+	ErrPermissionDenied = Forbidden("permission_denied", "permission denied")
+
+	// ErrRateLimited is returned when the caller has exhausted its quota
+	ErrRateLimited = RateLimited("rate_limited", "rate limit exceeded, try again later")
+)
+
+// Error is a typed domain error
+type Error struct {
+	Kind Kind
+	// Code is a stable, machine-readable identifier such as blog_not_found
+	Code string
+	// Message is a human-readable description safe to show to clients
+	Message string
+	// Err is the underlying cause, if any
+	Err error
+}
+
+// New creates a typed error
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap creates a typed error caused by err
+func Wrap(err error, kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, Err: err}
+}
+
+// Validation creates a validation error
+func Validation(code, message string) *Error {
+	return New(KindValidation, code, message)
+}
+
+// Unauthorized creates an authentication error
+func Unauthorized(code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+// Forbidden creates an authorization error
+func Forbidden(code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+// NotFound creates a not found error
+func NotFound(code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+// Conflict creates a conflict error
+func Conflict(code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+// Unprocessable creates an error for a valid request that cannot be carried out
+func Unprocessable(code, message string) *Error {
+	return New(KindUnprocessable, code, message)
+}
+
+// RateLimited creates a rate limit error
+func RateLimited(code, message string) *Error {
+	return New(KindRateLimited, code, message)
+}
+
+// Unavailable creates an error for a temporarily unavailable dependency
+func Unavailable(code, message string) *Error {
+	return New(KindUnavailable, code, message)
+}
+
+// InvalidRequest wraps a request binding or parsing error
+func InvalidRequest(err error) *Error {
+	return Wrap(err, KindValidation, "invalid_request", "invalid request")
+}
+
+// Error returns the message, followed by the cause when there is one
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the cause of the error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Status returns the HTTP status code of the error's kind
+func (e *Error) Status() int {
+	if status, ok := kindStatus[e.Kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// As finds the first typed error in err's chain
+func As(err error) (*Error, bool) {
+	var typed *Error
+	if errors.As(err, &typed) {
+		return typed, true
+	}
+	return nil, false
+}
+
+// KindOf returns the kind of the first typed error in err's chain, or
+// KindInternal when there is none
+func KindOf(err error) Kind {
+	if typed, ok := As(err); ok {
+		return typed.Kind
+	}
+	return KindInternal
+}
+
+// IsKind reports whether err's kind is kind
+func IsKind(err error, kind Kind) bool {
+	return err != nil && KindOf(err) == kind
+}
@@ -0,0 +1,60 @@
+package apperror
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of problem details (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. Code is an extension
+// member holding the error's stable code.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// NewProblem describes err as problem details. Client errors are detailed
+// with the full error chain; server errors only with the typed message, and
+// untyped errors not at all, since they may leak internals.
+func NewProblem(err error, instance string) *Problem {
+	typed, ok := As(err)
+	if !ok {
+		return &Problem{
+			Type:     "about:blank",
+			Title:    http.StatusText(http.StatusInternalServerError),
+			Status:   http.StatusInternalServerError,
+			Detail:   "An unexpected error occurred",
+			Instance: instance,
+			Code:     CodeInternal,
+		}
+	}
+
+	status := typed.Status()
+	detail := err.Error()
+	if status >= http.StatusInternalServerError {
+		detail = typed.Message
+	}
+	return &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     typed.Code,
+	}
+}
+
+// Respond writes err as an application/problem+json response and aborts the
+// handler chain
+func Respond(c *gin.Context, err error) {
+	problem := NewProblem(err, c.Request.URL.Path)
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
@@ -0,0 +1,136 @@
+package apperror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+var errNotFound = apperror.NotFound("blog_not_found", "blog not found")
+
+func TestNewProblem(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want apperror.Problem
+	}{
+		{
+			name: "Typed",
+			err:  errNotFound,
+			want: apperror.Problem{Title: "Not Found", Status: http.StatusNotFound, Detail: "blog not found", Code: "blog_not_found"},
+		},
+		{
+			name: "WrappedClientError",
+			err:  fmt.Errorf("%w: id 42", errNotFound),
+			want: apperror.Problem{Title: "Not Found", Status: http.StatusNotFound, Detail: "blog not found: id 42", Code: "blog_not_found"},
+		},
+		{
+			name: "InvalidRequest",
+			err:  apperror.InvalidRequest(errors.New("unexpected EOF")),
+			want: apperror.Problem{Title: "Bad Request", Status: http.StatusBadRequest, Detail: "invalid request: unexpected EOF", Code: "invalid_request"},
+		},
+		{
+			name: "Unprocessable",
+			err:  apperror.Unprocessable("diff_too_large", "revisions differ too much"),
+			want: apperror.Problem{Title: "Unprocessable Entity", Status: http.StatusUnprocessableEntity, Detail: "revisions differ too much", Code: "diff_too_large"},
+		},
+		{
+			name: "RateLimited",
+			err:  apperror.ErrRateLimited,
+			want: apperror.Problem{Title: "Too Many Requests", Status: http.StatusTooManyRequests, Detail: "rate limit exceeded, try again later", Code: "rate_limited"},
+		},
+		{
+			name: "ServerErrorHidesCause",
+			err:  apperror.Wrap(errors.New("connection refused"), apperror.KindUnavailable, "db_unavailable", "database unavailable"),
+			want: apperror.Problem{Title: "Service Unavailable", Status: http.StatusServiceUnavailable, Detail: "database unavailable", Code: "db_unavailable"},
+		},
+		{
+			name: "UnknownKind",
+			err:  apperror.New("bogus", "bogus", "bogus error"),
+			want: apperror.Problem{Title: "Internal Server Error", Status: http.StatusInternalServerError, Detail: "bogus error", Code: "bogus"},
+		},
+		{
+			name: "Untyped",
+			err:  errors.New("dial tcp 10.0.0.1:27017: connection refused"),
+			want: apperror.Problem{Title: "Internal Server Error", Status: http.StatusInternalServerError, Detail: "An unexpected error occurred", Code: apperror.CodeInternal},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.want.Type = "about:blank"
+			tc.want.Instance = "/api/v1/blogs/42"
+
+			got := apperror.NewProblem(tc.err, "/api/v1/blogs/42")
+			if *got != tc.want {
+				t.Errorf("NewProblem = %+v, want %+v", *got, tc.want)
+			}
+		})
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want apperror.Kind
+	}{
+		{name: "Typed", err: errNotFound, want: apperror.KindNotFound},
+		{name: "Wrapped", err: fmt.Errorf("loading: %w", apperror.ErrPermissionDenied), want: apperror.KindForbidden},
+		{name: "Untyped", err: errors.New("boom"), want: apperror.KindInternal},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := apperror.KindOf(tc.err); got != tc.want {
+				t.Errorf("KindOf = %s, want %s", got, tc.want)
+			}
+			if !apperror.IsKind(tc.err, tc.want) {
+				t.Errorf("IsKind(%s) = false", tc.want)
+			}
+		})
+	}
+
+	if apperror.IsKind(nil, apperror.KindInternal) {
+		t.Error("IsKind(nil) = true")
+	}
+}
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	reached := false
+	router.GET("/blogs/:id", func(c *gin.Context) {
+		apperror.Respond(c, fmt.Errorf("%w: id %s", errNotFound, c.Param("id")))
+	}, func(c *gin.Context) {
+		reached = true
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/blogs/42", nil))
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != apperror.ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", got, apperror.ProblemContentType)
+	}
+	if reached {
+		t.Error("handler chain was not aborted")
+	}
+
+	var problem apperror.Problem
+	if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	want := apperror.Problem{Type: "about:blank", Title: "Not Found", Status: http.StatusNotFound, Detail: "blog not found: id 42", Instance: "/blogs/42", Code: "blog_not_found"}
+	if problem != want {
+		t.Errorf("problem = %+v, want %+v", problem, want)
+	}
+}
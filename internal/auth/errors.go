@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"fmt"
+
+	"firebase.google.com/go/v4/auth"
+
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+)
+
+var (
+	// ErrInvalidToken is returned for missing, malformed or badly signed tokens
+	ErrInvalidToken = apperror.Unauthorized("invalid_token", "invalid token")
+
+	// ErrTokenExpired is returned for tokens past their exp claim
+	ErrTokenExpired = apperror.Unauthorized("token_expired", "token expired")
+
+	// ErrTokenRevoked is returned for tokens issued before the user's tokens were revoked
+	ErrTokenRevoked = apperror.Unauthorized("token_revoked", "token revoked")
+
+	// ErrUserDisabled is returned for tokens of disabled accounts
+	ErrUserDisabled = apperror.Forbidden("account_disabled", "user account is disabled")
+
+	// ErrUserNotFound is returned when the auth provider does not know a user
+	ErrUserNotFound = apperror.NotFound("user_not_found", "user not found")
+
+	// ErrEmailExists is returned when another account already uses an email
+	ErrEmailExists = apperror.Conflict("email_exists", "email already exists")
+
+	// ErrInvalidEmail is returned for malformed email addresses
+	ErrInvalidEmail = apperror.Validation("invalid_email", "invalid email format")
+
+	// ErrProviderUnavailable is returned when the auth provider cannot be reached
+	ErrProviderUnavailable = apperror.Unavailable("auth_unavailable", "authentication provider unavailable")
+)
+
+// firebaseError maps a Firebase Auth error to the matching typed error,
+// keeping the original as detail. Unknown errors are returned unchanged.
+func firebaseError(err error) error {
+	var typed error
+	switch {
+	case auth.IsIDTokenExpired(err):
+		typed = ErrTokenExpired
+	case auth.IsIDTokenRevoked(err):
+		typed = ErrTokenRevoked
+	case auth.IsIDTokenInvalid(err):
+		typed = ErrInvalidToken
+	case auth.IsUserDisabled(err):
+		typed = ErrUserDisabled
+	case auth.IsUserNotFound(err):
+		typed = ErrUserNotFound
+	case auth.IsEmailAlreadyExists(err):
+		typed = ErrEmailExists
+	case auth.IsInvalidEmail(err):
+		typed = ErrInvalidEmail
+	case auth.IsCertificateFetchFailed(err):
+		typed = ErrProviderUnavailable
+	default:
+		return err
+	}
+	return fmt.Errorf("%w: %v", typed, err)
+}
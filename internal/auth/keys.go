@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwk is a single key of a JSON Web Key Set (RFC 7517). Only RSA public keys
+// (for RS256) and symmetric keys (for HS256) are supported.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	K   string `json:"k,omitempty"`
+}
+
+// jwks is a JSON Web Key Set
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// KeySet holds the keys local tokens are verified against, indexed by key ID
+type KeySet struct {
+	rsaKeys  map[string]*rsa.PublicKey
+	hmacKeys map[string][]byte
+}
+
+// NewKeySet creates an empty key set
+func NewKeySet() *KeySet {
+	return &KeySet{
+		rsaKeys:  make(map[string]*rsa.PublicKey),
+		hmacKeys: make(map[string][]byte),
+	}
+}
+
+// LoadKeySet reads a JSON Web Key Set file
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse key set %s: %w", path, err)
+	}
+
+	keys := NewKeySet()
+	for _, key := range set.Keys {
+		switch key.Kty {
+		case "RSA":
+			publicKey, err := parseRSAJWK(key)
+			if err != nil {
+				return nil, fmt.Errorf("parse key %q: %w", key.Kid, err)
+			}
+			keys.AddRSA(key.Kid, publicKey)
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil {
+				return nil, fmt.Errorf("parse key %q: %w", key.Kid, err)
+			}
+			keys.AddHMAC(key.Kid, secret)
+		default:
+			return nil, fmt.Errorf("key %q has unsupported type %q", key.Kid, key.Kty)
+		}
+	}
+
+	return keys, nil
+}
+
+// AddRSA adds an RS256 verification key
+func (k *KeySet) AddRSA(kid string, key *rsa.PublicKey) {
+	k.rsaKeys[kid] = key
+}
+
+// AddHMAC adds an HS256 shared secret
+func (k *KeySet) AddHMAC(kid string, secret []byte) {
+	k.hmacKeys[kid] = secret
+}
+
+// Empty reports whether the key set holds no keys
+func (k *KeySet) Empty() bool {
+	return len(k.rsaKeys) == 0 && len(k.hmacKeys) == 0
+}
+
+// keyFunc selects the verification key for a token by its alg and kid header
+func (k *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case jwt.SigningMethodRS256.Alg():
+		if key, ok := k.rsaKeys[kid]; ok {
+			return key, nil
+		}
+	case jwt.SigningMethodHS256.Alg():
+		if key, ok := k.hmacKeys[kid]; ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s key with id %q", token.Method.Alg(), kid)
+}
+
+// PublicJWKS returns the JSON Web Key Set publishing an RSA public key
+func PublicJWKS(kid string, key *rsa.PublicKey) ([]byte, error) {
+	return json.MarshalIndent(jwks{Keys: []jwk{{
+		Kty: "RSA",
+		Kid: kid,
+		Alg: jwt.SigningMethodRS256.Alg(),
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}, "", "  ")
+}
+
+// ParseRSAPrivateKey parses a PEM encoded PKCS#1 or PKCS#8 RSA private key
+func ParseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("PEM data is not an RSA private key")
+	}
+	return key, nil
+}
+
+// parseRSAJWK builds an RSA public key from its JWK modulus and exponent
+func parseRSAJWK(key jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, err
+	}
+	if len(n) == 0 || len(e) == 0 {
+		return nil, errors.New("missing modulus or exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestLoadKeySet(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	published, err := PublicJWKS("rsa", &rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		data    string
+		wantErr bool
+		// check verifies the loaded key set
+		check func(t *testing.T, keys *KeySet)
+	}{
+		{
+			name: "PublishedRSAKey",
+			data: string(published),
+			check: func(t *testing.T, keys *KeySet) {
+				if !keys.rsaKeys["rsa"].Equal(&rsaKey.PublicKey) {
+					t.Error("loaded RSA key differs from the published one")
+				}
+			},
+		},
+		{
+			name: "SymmetricKey",
+			data: `{"keys": [{"kty": "oct", "kid": "hs", "k": "dGVzdC1zZWNyZXQ"}]}`,
+			check: func(t *testing.T, keys *KeySet) {
+				if string(keys.hmacKeys["hs"]) != "test-secret" {
+					t.Errorf("HMAC key = %q", keys.hmacKeys["hs"])
+				}
+			},
+		},
+		{
+			name: "NoKeys",
+			data: `{"keys": []}`,
+			check: func(t *testing.T, keys *KeySet) {
+				if !keys.Empty() {
+					t.Error("key set not empty")
+				}
+			},
+		},
+		{name: "InvalidJSON", data: `{"keys": [`, wantErr: true},
+		{name: "UnsupportedType", data: `{"keys": [{"kty": "EC", "kid": "ec"}]}`, wantErr: true},
+		{name: "MissingModulus", data: `{"keys": [{"kty": "RSA", "kid": "rsa", "e": "AQAB"}]}`, wantErr: true},
+		{name: "InvalidSecret", data: `{"keys": [{"kty": "oct", "kid": "hs", "k": "not base64!"}]}`, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "jwks.json")
+			if err := os.WriteFile(path, []byte(tc.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			keys, err := LoadKeySet(path)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("LoadKeySet succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadKeySet: %v", err)
+			}
+			tc.check(t, keys)
+		})
+	}
+
+	if _, err := LoadKeySet(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadKeySet of a missing file succeeded")
+	}
+}
+
+func TestKeySetKeyFunc(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := NewKeySet()
+	keys.AddHMAC("", testSecret)
+	keys.AddRSA("rsa", &rsaKey.PublicKey)
+
+	cases := []struct {
+		name    string
+		method  jwt.SigningMethod
+		kid     string
+		wantErr bool
+	}{
+		{name: "HMAC", method: jwt.SigningMethodHS256},
+		{name: "RSA", method: jwt.SigningMethodRS256, kid: "rsa"},
+		{name: "UnknownKid", method: jwt.SigningMethodRS256, kid: "other", wantErr: true},
+		{name: "RSAKidAsHMAC", method: jwt.SigningMethodHS256, kid: "rsa", wantErr: true},
+		{name: "HMACKidAsRSA", method: jwt.SigningMethodRS256, wantErr: true},
+		{name: "UnsupportedAlg", method: jwt.SigningMethodHS512, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			token := jwt.New(tc.method)
+			if tc.kid != "" {
+				token.Header["kid"] = tc.kid
+			}
+			key, err := keys.keyFunc(token)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("keyFunc = %v, %v", key, err)
+			}
+		})
+	}
+}
+
+func TestParseRSAPrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPKCS8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(blockType string, der []byte) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	}
+
+	cases := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{name: "PKCS1", data: encode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))},
+		{name: "PKCS8", data: encode("PRIVATE KEY", pkcs8)},
+		{name: "NotRSA", data: encode("PRIVATE KEY", ecPKCS8), wantErr: true},
+		{name: "NotPEM", data: []byte("not a key"), wantErr: true},
+		{name: "Garbage", data: encode("PRIVATE KEY", []byte("garbage")), wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ParseRSAPrivateKey(tc.data)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("ParseRSAPrivateKey succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRSAPrivateKey: %v", err)
+			}
+			if !key.Equal(rsaKey) {
+				t.Error("parsed key differs")
+			}
+		})
+	}
+}
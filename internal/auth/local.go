@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"firebase.google.com/go/v4/auth"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+)
+
+// registeredClaims are the JWT claims exposed as Token fields rather than
+// custom claims, matching what the Firebase verifier does
+var registeredClaims = []string{"iss", "aud", "exp", "iat", "nbf", "sub", "uid", "auth_time", "firebase"}
+
+// nonCustomClaims are the claims besides the registered ones that are not
+// custom claims: those Firebase reserves and the profile claims kept on the
+// user record itself
+var nonCustomClaims = []string{"acr", "amr", "at_hash", "azp", "cnf", "c_hash", "jti", "nonce", "email", "email_verified", "name", "picture"}
+
+// LocalService verifies RS256 and HS256 JWTs against a local key set. It lets
+// the server run without Firebase, e.g. for development and tests, with
+// tokens minted by cmd/mint-token.
+type LocalService struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+
+	// Users are only known from the tokens they presented
+	mu         sync.RWMutex
+	users      map[string]*auth.UserRecord
+	disabled   map[string]bool
+	validAfter map[string]int64
+}
+
+// Ensure LocalService implements model.AuthService
+var _ model.AuthService = (*LocalService)(nil)
+
+// NewLocalService creates a local JWT auth service from the configured
+// shared secret and/or JSON Web Key Set file
+func NewLocalService(config *config.AuthConfig) (*LocalService, error) {
+	keys := NewKeySet()
+	if config.JWTKeysFile != "" {
+		loaded, err := LoadKeySet(config.JWTKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = loaded
+	}
+	if config.JWTSecret != "" {
+		keys.AddHMAC("", []byte(config.JWTSecret))
+	}
+	if keys.Empty() {
+		return nil, errors.New("local auth requires a JWT secret or key set file")
+	}
+
+	return NewLocalServiceWithKeys(keys, config.JWTIssuer, config.JWTAudience), nil
+}
+
+// NewLocalServiceWithKeys creates a local JWT auth service from a key set.
+// Empty issuer or audience disable the respective check.
+func NewLocalServiceWithKeys(keys *KeySet, issuer, audience string) *LocalService {
+	return &LocalService{
+		keys:       keys,
+		issuer:     issuer,
+		audience:   audience,
+		users:      make(map[string]*auth.UserRecord),
+		disabled:   make(map[string]bool),
+		validAfter: make(map[string]int64),
+	}
+}
+
+// VerifyToken verifies a locally signed JWT and returns its claims. The
+// subject is the user ID.
+func (s *LocalService) VerifyToken(ctx context.Context, idToken string) (*auth.Token, error) {
+	if idToken == "" {
+		return nil, fmt.Errorf("%w: id token is empty", ErrInvalidToken)
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if s.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(s.issuer))
+	}
+	if s.audience != "" {
+		opts = append(opts, jwt.WithAudience(s.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(idToken, claims, s.keys.keyFunc, opts...); err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, fmt.Errorf("%w: %v", ErrTokenExpired, err)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", ErrInvalidToken)
+	}
+
+	token := &auth.Token{
+		Subject: subject,
+		UID:     subject,
+		Claims:  make(map[string]interface{}),
+	}
+	token.Issuer, _ = claims.GetIssuer()
+	if audience, _ := claims.GetAudience(); len(audience) > 0 {
+		token.Audience = audience[0]
+	}
+	if expires, _ := claims.GetExpirationTime(); expires != nil {
+		token.Expires = expires.Unix()
+	}
+	if issuedAt, _ := claims.GetIssuedAt(); issuedAt != nil {
+		token.IssuedAt = issuedAt.Unix()
+	}
+	if authTime, ok := claims["auth_time"].(float64); ok {
+		token.AuthTime = int64(authTime)
+	}
+	if err := s.checkRevoked(token); err != nil {
+		return nil, err
+	}
+
+	for name, value := range claims {
+		token.Claims[name] = value
+	}
+	for _, name := range registeredClaims {
+		delete(token.Claims, name)
+	}
+
+	s.remember(token)
+	return token, nil
+}
+
+// VerifyTokenAndCheckRevoked verifies a locally signed JWT. Revocations are
+// held in memory, so VerifyToken already checks them.
+func (s *LocalService) VerifyTokenAndCheckRevoked(ctx context.Context, idToken string) (*auth.Token, error) {
+	return s.VerifyToken(ctx, idToken)
+}
+
+// GetUser gets a user that has presented a valid token since startup
+func (s *LocalService) GetUser(ctx context.Context, uid string) (*auth.UserRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[uid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, uid)
+	}
+	return user, nil
+}
+
+// SetCustomUserClaims replaces the custom claims of a known user. Locally
+// minted tokens are not reissued, so new claims only show up in GetUser.
+func (s *LocalService) SetCustomUserClaims(ctx context.Context, uid string, claims map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[uid]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUserNotFound, uid)
+	}
+	user.CustomClaims = claims
+	return nil
+}
+
+// SetUserDisabled disables or enables a user; tokens of disabled users are rejected
+func (s *LocalService) SetUserDisabled(ctx context.Context, uid string, disabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.disabled[uid] = disabled
+	if user, ok := s.users[uid]; ok {
+		user.Disabled = disabled
+	}
+	return nil
+}
+
+// RevokeRefreshTokens rejects every token issued to a user before now
+func (s *LocalService) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.validAfter[uid] = time.Now().Unix()
+	if user, ok := s.users[uid]; ok {
+		user.TokensValidAfterMillis = s.validAfter[uid] * 1000
+	}
+	return nil
+}
+
+// checkRevoked rejects tokens of disabled users and tokens issued before the
+// user's tokens were revoked
+func (s *LocalService) checkRevoked(token *auth.Token) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.disabled[token.UID] {
+		return ErrUserDisabled
+	}
+	issuedAt := token.AuthTime
+	if issuedAt == 0 {
+		issuedAt = token.IssuedAt
+	}
+	if validAfter, ok := s.validAfter[token.UID]; ok && issuedAt < validAfter {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// remember records the profile carried by a token's standard claims. A known
+// user keeps the custom claims set through SetCustomUserClaims; a new one
+// starts with the token's custom claims.
+func (s *LocalService) remember(token *auth.Token) {
+	email, _ := token.Claims["email"].(string)
+	name, _ := token.Claims["name"].(string)
+	picture, _ := token.Claims["picture"].(string)
+	emailVerified, _ := token.Claims["email_verified"].(bool)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	customClaims := customClaimsOf(token)
+	if known, ok := s.users[token.UID]; ok {
+		customClaims = known.CustomClaims
+	}
+
+	s.users[token.UID] = &auth.UserRecord{
+		UserInfo: &auth.UserInfo{
+			UID:         token.UID,
+			Email:       email,
+			DisplayName: name,
+			PhotoURL:    picture,
+			ProviderID:  "local",
+		},
+		CustomClaims:           customClaims,
+		EmailVerified:          emailVerified,
+		Disabled:               s.disabled[token.UID],
+		TokensValidAfterMillis: s.validAfter[token.UID] * 1000,
+		UserMetadata: &auth.UserMetadata{
+			LastLogInTimestamp: time.Now().UnixMilli(),
+		},
+	}
+}
+
+// customClaimsOf copies the custom claims of a token, leaving out every claim
+// Firebase would refuse in SetCustomUserClaims
+func customClaimsOf(token *auth.Token) map[string]interface{} {
+	claims := make(map[string]interface{}, len(token.Claims))
+	for name, value := range token.Claims {
+		claims[name] = value
+	}
+	for _, name := range registeredClaims {
+		delete(claims, name)
+	}
+	for _, name := range nonCustomClaims {
+		delete(claims, name)
+	}
+	return claims
+}
+
+// SignToken signs claims into a JWT. RS256 takes an *rsa.PrivateKey and
+// HS256 a []byte secret; kid, when set, selects the verification key.
+func SignToken(method jwt.SigningMethod, key interface{}, kid string, claims map[string]interface{}) (string, error) {
+	token := jwt.NewWithClaims(method, jwt.MapClaims(claims))
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	return token.SignedString(key)
+}
@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testSecret = []byte("test-secret")
+
+// newTestLocalService trusts testSecret for HS256 and rsaKey under kid "rsa"
+func newTestLocalService(t *testing.T) (*LocalService, *rsa.PrivateKey) {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := NewKeySet()
+	keys.AddHMAC("", testSecret)
+	keys.AddRSA("rsa", &rsaKey.PublicKey)
+	return NewLocalServiceWithKeys(keys, "https://issuer.example.com", "letsnormalizeit"), rsaKey
+}
+
+// testClaims are valid claims for alice, adjusted by the given overrides;
+// nil overrides remove a claim
+func testClaims(overrides map[string]interface{}) map[string]interface{} {
+	now := time.Now()
+	claims := map[string]interface{}{
+		"sub":   "alice",
+		"iss":   "https://issuer.example.com",
+		"aud":   "letsnormalizeit",
+		"iat":   now.Add(-time.Minute).Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"email": "alice@example.com",
+		"name":  "Alice",
+		"team":  "blue",
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+	}
+	return claims
+}
+
+func TestLocalServiceVerifyToken(t *testing.T) {
+	ctx := context.Background()
+	s, rsaKey := newTestLocalService(t)
+
+	sign := func(method jwt.SigningMethod, key interface{}, kid string, claims map[string]interface{}) string {
+		token, err := SignToken(method, key, kid, claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		token string
+		// setup changes the user's state before verifying
+		setup   func()
+		wantErr error
+	}{
+		{
+			name:  "HS256",
+			token: sign(jwt.SigningMethodHS256, testSecret, "", testClaims(nil)),
+		},
+		{
+			name:  "RS256",
+			token: sign(jwt.SigningMethodRS256, rsaKey, "rsa", testClaims(nil)),
+		},
+		{
+			name:    "Empty",
+			token:   "",
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "AlgNone",
+			token:   sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", testClaims(nil)),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "UnsupportedAlg",
+			token:   sign(jwt.SigningMethodHS384, testSecret, "", testClaims(nil)),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			// An HS256 token keyed with the RSA public key must not be
+			// verified against that key
+			name:    "AlgConfusion",
+			token:   sign(jwt.SigningMethodHS256, publicKey, "rsa", testClaims(nil)),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "WrongSecret",
+			token:   sign(jwt.SigningMethodHS256, []byte("other-secret"), "", testClaims(nil)),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "UnknownKid",
+			token:   sign(jwt.SigningMethodRS256, rsaKey, "rotated", testClaims(nil)),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Expired",
+			token:   sign(jwt.SigningMethodHS256, testSecret, "", testClaims(map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})),
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "NoExpiry",
+			token:   sign(jwt.SigningMethodHS256, testSecret, "", testClaims(map[string]interface{}{"exp": nil})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "WrongIssuer",
+			token:   sign(jwt.SigningMethodHS256, testSecret, "", testClaims(map[string]interface{}{"iss": "https://evil.example.com"})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "WrongAudience",
+			token:   sign(jwt.SigningMethodHS256, testSecret, "", testClaims(map[string]interface{}{"aud": "other-app"})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "NoSubject",
+			token:   sign(jwt.SigningMethodHS256, testSecret, "", testClaims(map[string]interface{}{"sub": nil})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:  "RevokedBefore",
+			token: sign(jwt.SigningMethodHS256, testSecret, "", testClaims(nil)),
+			setup: func() {
+				s.RevokeRefreshTokens(ctx, "alice")
+			},
+			wantErr: ErrTokenRevoked,
+		},
+		{
+			name:  "IssuedAfterRevocation",
+			token: sign(jwt.SigningMethodHS256, testSecret, "", testClaims(map[string]interface{}{"iat": time.Now().Add(time.Second).Unix()})),
+			setup: func() {
+				s.RevokeRefreshTokens(ctx, "alice")
+			},
+		},
+		{
+			name:  "Disabled",
+			token: sign(jwt.SigningMethodHS256, testSecret, "", testClaims(nil)),
+			setup: func() {
+				s.SetUserDisabled(ctx, "alice", true)
+			},
+			wantErr: ErrUserDisabled,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s.disabled = make(map[string]bool)
+			s.validAfter = make(map[string]int64)
+			if tc.setup != nil {
+				tc.setup()
+			}
+
+			token, err := s.VerifyToken(ctx, tc.token)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("VerifyToken = %v, want %v", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyToken: %v", err)
+			}
+			if token.UID != "alice" || token.Issuer != "https://issuer.example.com" || token.Audience != "letsnormalizeit" {
+				t.Errorf("token = %+v", token)
+			}
+			if _, ok := token.Claims["exp"]; ok {
+				t.Errorf("registered claim exp in token claims: %v", token.Claims)
+			}
+			if token.Claims["email"] != "alice@example.com" || token.Claims["team"] != "blue" {
+				t.Errorf("token claims = %v", token.Claims)
+			}
+		})
+	}
+}
+
+func TestLocalServiceUserRecord(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestLocalService(t)
+
+	verify := func(overrides map[string]interface{}) {
+		t.Helper()
+		token, err := SignToken(jwt.SigningMethodHS256, testSecret, "", testClaims(overrides))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.VerifyToken(ctx, token); err != nil {
+			t.Fatalf("VerifyToken: %v", err)
+		}
+	}
+
+	if _, err := s.GetUser(ctx, "alice"); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("GetUser before any token = %v, want ErrUserNotFound", err)
+	}
+
+	// Only custom claims are recorded, so they can be written back as is
+	verify(map[string]interface{}{"jti": "abc", "nbf": time.Now().Add(-time.Minute).Unix()})
+	user, err := s.GetUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]interface{}{"team": "blue"}; !maps.Equal(user.CustomClaims, want) {
+		t.Errorf("CustomClaims = %v, want %v", user.CustomClaims, want)
+	}
+	if user.Email != "alice@example.com" || user.DisplayName != "Alice" {
+		t.Errorf("profile = %+v", user.UserInfo)
+	}
+
+	// Claims set on the record survive the next token
+	if err := s.SetCustomUserClaims(ctx, "alice", map[string]interface{}{"admin": false}); err != nil {
+		t.Fatal(err)
+	}
+	verify(map[string]interface{}{"name": "Alice Smith"})
+	user, _ = s.GetUser(ctx, "alice")
+	if want := map[string]interface{}{"admin": false}; !maps.Equal(user.CustomClaims, want) {
+		t.Errorf("CustomClaims after reverifying = %v, want %v", user.CustomClaims, want)
+	}
+	if user.DisplayName != "Alice Smith" {
+		t.Errorf("DisplayName = %q, want the latest token's name", user.DisplayName)
+	}
+
+	// Revocations and disabling show on the record
+	before := time.Now().Unix()
+	s.RevokeRefreshTokens(ctx, "alice")
+	s.SetUserDisabled(ctx, "alice", true)
+	user, _ = s.GetUser(ctx, "alice")
+	if user.TokensValidAfterMillis < before*1000 || !user.Disabled {
+		t.Errorf("TokensValidAfterMillis = %d, Disabled = %t", user.TokensValidAfterMillis, user.Disabled)
+	}
+}
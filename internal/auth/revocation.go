@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+
+	"firebase.google.com/go/v4/auth"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+)
+
+// RevocationCheckedService checks every token for revocation and disabled
+// accounts. It stands in for CachedService when token caching is disabled.
+type RevocationCheckedService struct {
+	model.AuthService
+}
+
+// Ensure RevocationCheckedService implements model.AuthService
+var _ model.AuthService = RevocationCheckedService{}
+
+// NewRevocationCheckedService wraps an auth service so that VerifyToken
+// always checks for revocation
+func NewRevocationCheckedService(service model.AuthService) RevocationCheckedService {
+	return RevocationCheckedService{AuthService: service}
+}
+
+// VerifyToken verifies a token and checks it for revocation with the wrapped
+// service's VerifyTokenAndCheckRevoked
+func (s RevocationCheckedService) VerifyToken(ctx context.Context, idToken string) (*auth.Token, error) {
+	return s.AuthService.VerifyTokenAndCheckRevoked(ctx, idToken)
+}
@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRevocationCheckedServiceVerifyToken(t *testing.T) {
+	ctx := context.Background()
+	upstream := newStubAuthService()
+	s := NewRevocationCheckedService(upstream)
+
+	if _, err := s.VerifyToken(ctx, "alice-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RevokeRefreshTokens(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.VerifyToken(ctx, "alice-1"); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("VerifyToken after revocation error = %v, want %v", err, ErrTokenRevoked)
+	}
+	if got := upstream.calls["alice"]; got != 2 {
+		t.Errorf("upstream calls = %d, want 2", got)
+	}
+}
@@ -2,14 +2,14 @@ package auth
 
 import (
 	"context"
-	"errors"
-	"log"
+	"fmt"
 
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/auth"
 
 	"github.com/dksensei/letsnormalizeit/internal/config"
 	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
 	"google.golang.org/api/option"
 )
 
@@ -41,16 +41,35 @@ func NewService(config *config.FirebaseConfig) (*Service, error) {
 	}, nil
 }
 
-// VerifyToken verifies the Firebase ID token and returns the token claims
+// VerifyToken verifies the Firebase ID token and returns the token claims.
+// Only the signature and claims are checked, against cached public keys, so
+// revoked tokens and tokens of disabled users still pass; CachedService
+// checks those periodically through VerifyTokenAndCheckRevoked.
 func (s *Service) VerifyToken(ctx context.Context, idToken string) (*auth.Token, error) {
 	if idToken == "" {
-		return nil, errors.New("id token is empty")
+		return nil, fmt.Errorf("%w: id token is empty", ErrInvalidToken)
 	}
 
 	token, err := s.client.VerifyIDToken(ctx, idToken)
 	if err != nil {
-		log.Printf("Error verifying ID token: %v\n", err)
-		return nil, err
+		utils.FromContext(ctx).Debug("Error verifying ID token: %v", err)
+		return nil, firebaseError(err)
+	}
+
+	return token, nil
+}
+
+// VerifyTokenAndCheckRevoked verifies the Firebase ID token and asks Firebase
+// whether the user is disabled or their tokens were revoked since it was issued
+func (s *Service) VerifyTokenAndCheckRevoked(ctx context.Context, idToken string) (*auth.Token, error) {
+	if idToken == "" {
+		return nil, fmt.Errorf("%w: id token is empty", ErrInvalidToken)
+	}
+
+	token, err := s.client.VerifyIDTokenAndCheckRevoked(ctx, idToken)
+	if err != nil {
+		utils.FromContext(ctx).Debug("Error verifying ID token: %v", err)
+		return nil, firebaseError(err)
 	}
 
 	return token, nil
@@ -58,5 +77,33 @@ func (s *Service) VerifyToken(ctx context.Context, idToken string) (*auth.Token,
 
 // GetUser gets a user by their UID
 func (s *Service) GetUser(ctx context.Context, uid string) (*auth.UserRecord, error) {
-	return s.client.GetUser(ctx, uid)
+	user, err := s.client.GetUser(ctx, uid)
+	if err != nil {
+		return nil, firebaseError(err)
+	}
+	return user, nil
+}
+
+// SetCustomUserClaims replaces the custom claims of a user
+func (s *Service) SetCustomUserClaims(ctx context.Context, uid string, claims map[string]interface{}) error {
+	if err := s.client.SetCustomUserClaims(ctx, uid, claims); err != nil {
+		return firebaseError(err)
+	}
+	return nil
+}
+
+// SetUserDisabled disables or enables a user's account
+func (s *Service) SetUserDisabled(ctx context.Context, uid string, disabled bool) error {
+	if _, err := s.client.UpdateUser(ctx, uid, (&auth.UserToUpdate{}).Disabled(disabled)); err != nil {
+		return firebaseError(err)
+	}
+	return nil
+}
+
+// RevokeRefreshTokens revokes all tokens issued to a user so far
+func (s *Service) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	if err := s.client.RevokeRefreshTokens(ctx, uid); err != nil {
+		return firebaseError(err)
+	}
+	return nil
 }
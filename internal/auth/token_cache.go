@@ -0,0 +1,303 @@
+package auth
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"firebase.google.com/go/v4/auth"
+
+	"github.com/dksensei/letsnormalizeit/internal/cache"
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/metrics"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+)
+
+// Token cache keys in the shared store
+const (
+	tokenKeyPrefix   = "auth:token:"
+	revokedKeyPrefix = "auth:revoked:"
+)
+
+// CachedService remembers verified tokens so that a token is only verified
+// again, with the wrapped service's VerifyTokenAndCheckRevoked, once per
+// revocation check interval.
+// Entries are keyed by the SHA-256 of the token and never outlive its exp.
+// An in-memory LRU is consulted first, then the optional shared store.
+//
+// Revoking or disabling a user through CachedService takes effect at once on
+// this instance; elsewhere within the revocation check interval.
+type CachedService struct {
+	model.AuthService
+
+	store         cache.Store
+	size          int
+	ttl           time.Duration
+	checkInterval time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// Ensure CachedService implements model.AuthService
+var _ model.AuthService = (*CachedService)(nil)
+
+// cachedToken is a verified token and when it was last verified
+type cachedToken struct {
+	Key       string                 `json:"-"`
+	Token     *auth.Token            `json:"token"`
+	Claims    map[string]interface{} `json:"claims"`
+	CheckedAt time.Time              `json:"checked_at"`
+	ExpiresAt time.Time              `json:"expires_at"`
+}
+
+// NewCachedService wraps an auth service with a verified-token cache. store
+// is the shared tier and may be nil to only cache in memory.
+func NewCachedService(service model.AuthService, store cache.Store, cfg *config.AuthConfig) *CachedService {
+	return &CachedService{
+		AuthService:   service,
+		store:         store,
+		size:          cfg.TokenCacheSize,
+		ttl:           cfg.TokenCacheTTL,
+		checkInterval: cfg.RevocationCheckInterval,
+		entries:       make(map[string]*list.Element),
+		lru:           list.New(),
+	}
+}
+
+// VerifyToken returns the cached claims of a token verified within the
+// revocation check interval, or verifies and checks it for revocation with
+// the wrapped service
+func (s *CachedService) VerifyToken(ctx context.Context, idToken string) (*auth.Token, error) {
+	if idToken == "" {
+		return s.AuthService.VerifyTokenAndCheckRevoked(ctx, idToken)
+	}
+
+	now := time.Now()
+	key := tokenHash(idToken)
+	if entry, ok := s.lookup(ctx, key, now); ok && now.Sub(entry.CheckedAt) < s.checkInterval {
+		metrics.ObserveCache("token", metrics.CacheHit)
+		return entry.token(), nil
+	}
+	// Entries due for a revocation check count as misses
+	metrics.ObserveCache("token", metrics.CacheMiss)
+
+	return s.verifyAndCheckRevoked(ctx, key, idToken, now)
+}
+
+// VerifyTokenAndCheckRevoked verifies a token and checks it for revocation
+// with the wrapped service, whatever the cache holds, and caches the result
+func (s *CachedService) VerifyTokenAndCheckRevoked(ctx context.Context, idToken string) (*auth.Token, error) {
+	if idToken == "" {
+		return s.AuthService.VerifyTokenAndCheckRevoked(ctx, idToken)
+	}
+	return s.verifyAndCheckRevoked(ctx, tokenHash(idToken), idToken, time.Now())
+}
+
+// verifyAndCheckRevoked verifies a token with the revocation check and
+// caches it as checked at now, or drops it from the cache if it failed
+func (s *CachedService) verifyAndCheckRevoked(ctx context.Context, key, idToken string, now time.Time) (*auth.Token, error) {
+	token, err := s.AuthService.VerifyTokenAndCheckRevoked(ctx, idToken)
+	if err != nil {
+		s.forget(ctx, key)
+		return nil, err
+	}
+
+	s.save(ctx, &cachedToken{
+		Key:       key,
+		Token:     token,
+		Claims:    token.Claims,
+		CheckedAt: now,
+		ExpiresAt: s.expiry(token, now),
+	})
+	return token, nil
+}
+
+// SetUserDisabled disables or enables a user and drops their cached tokens
+func (s *CachedService) SetUserDisabled(ctx context.Context, uid string, disabled bool) error {
+	if err := s.AuthService.SetUserDisabled(ctx, uid, disabled); err != nil {
+		return err
+	}
+	if disabled {
+		s.forgetUser(ctx, uid)
+	}
+	return nil
+}
+
+// RevokeRefreshTokens revokes a user's tokens and drops them from the cache
+func (s *CachedService) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	if err := s.AuthService.RevokeRefreshTokens(ctx, uid); err != nil {
+		return err
+	}
+	s.forgetUser(ctx, uid)
+	return nil
+}
+
+// lookup finds an unexpired entry in memory, then in the shared store
+func (s *CachedService) lookup(ctx context.Context, key string, now time.Time) (*cachedToken, bool) {
+	s.mu.Lock()
+	if element, ok := s.entries[key]; ok {
+		entry := element.Value.(*cachedToken)
+		if now.Before(entry.ExpiresAt) {
+			s.lru.MoveToFront(element)
+			s.mu.Unlock()
+			return entry, true
+		}
+		s.remove(element)
+	}
+	s.mu.Unlock()
+
+	if s.store == nil {
+		return nil, false
+	}
+
+	data, err := s.store.Get(ctx, tokenKeyPrefix+key)
+	if err != nil {
+		if !errors.Is(err, cache.ErrMiss) {
+			utils.FromContext(ctx).Warn("Token cache read failed: %v", err)
+		}
+		return nil, false
+	}
+	var entry cachedToken
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Token == nil {
+		utils.FromContext(ctx).Warn("Token cache entry could not be decoded: %v", err)
+		return nil, false
+	}
+	entry.Key = key
+	if !now.Before(entry.ExpiresAt) || s.revokedSince(ctx, entry.Token.UID, entry.CheckedAt) {
+		return nil, false
+	}
+
+	s.remember(&entry)
+	return &entry, true
+}
+
+// save caches a verified token in memory and in the shared store
+func (s *CachedService) save(ctx context.Context, entry *cachedToken) {
+	ttl := entry.ExpiresAt.Sub(entry.CheckedAt)
+	if ttl <= 0 {
+		return
+	}
+	s.remember(entry)
+
+	if s.store == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		utils.FromContext(ctx).Warn("Token cache entry could not be encoded: %v", err)
+		return
+	}
+	if err := s.store.Set(ctx, tokenKeyPrefix+entry.Key, data, ttl); err != nil {
+		utils.FromContext(ctx).Warn("Token cache write failed: %v", err)
+	}
+}
+
+// remember adds an entry to the in-memory LRU, evicting the least recently
+// used entries beyond the configured size
+func (s *CachedService) remember(entry *cachedToken) {
+	if s.size <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[entry.Key]; ok {
+		element.Value = entry
+		s.lru.MoveToFront(element)
+		return
+	}
+	s.entries[entry.Key] = s.lru.PushFront(entry)
+	for s.lru.Len() > s.size {
+		s.remove(s.lru.Back())
+	}
+}
+
+// forget drops a token that failed verification
+func (s *CachedService) forget(ctx context.Context, key string) {
+	s.mu.Lock()
+	if element, ok := s.entries[key]; ok {
+		s.remove(element)
+	}
+	s.mu.Unlock()
+
+	if s.store != nil {
+		if err := s.store.Delete(ctx, tokenKeyPrefix+key); err != nil {
+			utils.FromContext(ctx).Warn("Token cache invalidation failed: %v", err)
+		}
+	}
+}
+
+// forgetUser drops every token of a user from memory and marks the user as
+// revoked in the shared store, where tokens are not indexed by user. The
+// marker outlives every entry cached before it.
+func (s *CachedService) forgetUser(ctx context.Context, uid string) {
+	s.mu.Lock()
+	for element := s.lru.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*cachedToken).Token.UID == uid {
+			s.remove(element)
+		}
+		element = next
+	}
+	s.mu.Unlock()
+
+	if s.store != nil {
+		now := strconv.FormatInt(time.Now().UnixNano(), 10)
+		if err := s.store.Set(ctx, revokedKeyPrefix+uid, []byte(now), s.ttl); err != nil {
+			utils.FromContext(ctx).Warn("Token cache revocation failed for %s: %v", uid, err)
+		}
+	}
+}
+
+// revokedSince reports whether the user's tokens were revoked through a
+// CachedService after a shared entry was last verified
+func (s *CachedService) revokedSince(ctx context.Context, uid string, checkedAt time.Time) bool {
+	data, err := s.store.Get(ctx, revokedKeyPrefix+uid)
+	if err != nil {
+		if !errors.Is(err, cache.ErrMiss) {
+			utils.FromContext(ctx).Warn("Token cache read failed: %v", err)
+			return true
+		}
+		return false
+	}
+	revokedAt, err := strconv.ParseInt(string(data), 10, 64)
+	return err != nil || checkedAt.UnixNano() <= revokedAt
+}
+
+// expiry is when a token verified at now leaves the cache
+func (s *CachedService) expiry(token *auth.Token, now time.Time) time.Time {
+	expiresAt := now.Add(s.ttl)
+	if exp := time.Unix(token.Expires, 0); exp.Before(expiresAt) {
+		expiresAt = exp
+	}
+	return expiresAt
+}
+
+// remove drops an element from the LRU; s.mu must be held
+func (s *CachedService) remove(element *list.Element) {
+	s.lru.Remove(element)
+	delete(s.entries, element.Value.(*cachedToken).Key)
+}
+
+// token returns a copy of the cached token for a caller
+func (e *cachedToken) token() *auth.Token {
+	token := *e.Token
+	token.Claims = e.Claims
+	return &token
+}
+
+// tokenHash is the cache key of a token, so raw tokens are never stored
+func tokenHash(idToken string) string {
+	sum := sha256.Sum256([]byte(idToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"firebase.google.com/go/v4/auth"
+
+	"github.com/dksensei/letsnormalizeit/internal/cache"
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+)
+
+// stubAuthService verifies tokens named after their user, e.g. "alice-1",
+// and counts how often each user's tokens reach it. Only the revocation
+// checking verification is implemented, as CachedService must not use the
+// other one.
+type stubAuthService struct {
+	model.AuthService
+
+	expires time.Time
+	revoked map[string]bool
+	calls   map[string]int
+}
+
+func newStubAuthService() *stubAuthService {
+	return &stubAuthService{
+		expires: time.Now().Add(time.Hour),
+		revoked: make(map[string]bool),
+		calls:   make(map[string]int),
+	}
+}
+
+func (s *stubAuthService) VerifyTokenAndCheckRevoked(ctx context.Context, idToken string) (*auth.Token, error) {
+	uid := idToken[:len(idToken)-2]
+	s.calls[uid]++
+	if s.revoked[uid] {
+		return nil, ErrTokenRevoked
+	}
+	return &auth.Token{UID: uid, Expires: s.expires.Unix(), Claims: map[string]interface{}{"email": uid + "@example.com"}}, nil
+}
+
+func (s *stubAuthService) SetUserDisabled(ctx context.Context, uid string, disabled bool) error {
+	return nil
+}
+
+func (s *stubAuthService) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	s.revoked[uid] = true
+	return nil
+}
+
+func TestCachedServiceVerifyToken(t *testing.T) {
+	cfg := config.AuthConfig{TokenCacheSize: 10, TokenCacheTTL: time.Hour, RevocationCheckInterval: time.Hour}
+
+	cases := []struct {
+		name string
+		// configure adjusts the cache and the stub before any token is verified
+		configure func(cfg *config.AuthConfig, upstream *stubAuthService)
+		// run makes the calls on top of verifying alice-1 once
+		run       func(t *testing.T, s *CachedService)
+		wantCalls map[string]int
+	}{
+		{
+			name:      "Cached",
+			run:       func(t *testing.T, s *CachedService) { verify(t, s, "alice-1") },
+			wantCalls: map[string]int{"alice": 1},
+		},
+		{
+			name:      "TokensCachedSeparately",
+			run:       func(t *testing.T, s *CachedService) { verify(t, s, "alice-2") },
+			wantCalls: map[string]int{"alice": 2},
+		},
+		{
+			name: "RevocationCheckDue",
+			configure: func(cfg *config.AuthConfig, upstream *stubAuthService) {
+				cfg.RevocationCheckInterval = 0
+			},
+			run:       func(t *testing.T, s *CachedService) { verify(t, s, "alice-1") },
+			wantCalls: map[string]int{"alice": 2},
+		},
+		{
+			name: "CacheTTL",
+			configure: func(cfg *config.AuthConfig, upstream *stubAuthService) {
+				cfg.TokenCacheTTL = time.Millisecond
+			},
+			run: func(t *testing.T, s *CachedService) {
+				time.Sleep(2 * time.Millisecond)
+				verify(t, s, "alice-1")
+			},
+			wantCalls: map[string]int{"alice": 2},
+		},
+		{
+			name: "NeverPastExp",
+			configure: func(cfg *config.AuthConfig, upstream *stubAuthService) {
+				upstream.expires = time.Now().Add(-time.Second)
+			},
+			run:       func(t *testing.T, s *CachedService) { verify(t, s, "alice-1") },
+			wantCalls: map[string]int{"alice": 2},
+		},
+		{
+			name: "Disabled",
+			configure: func(cfg *config.AuthConfig, upstream *stubAuthService) {
+				cfg.TokenCacheSize = 0
+			},
+			run:       func(t *testing.T, s *CachedService) { verify(t, s, "alice-1") },
+			wantCalls: map[string]int{"alice": 2},
+		},
+		{
+			name: "LeastRecentlyUsedEvicted",
+			configure: func(cfg *config.AuthConfig, upstream *stubAuthService) {
+				cfg.TokenCacheSize = 2
+			},
+			run: func(t *testing.T, s *CachedService) {
+				verify(t, s, "bob-1")
+				verify(t, s, "alice-1")
+				verify(t, s, "carol-1")
+				verify(t, s, "alice-1")
+				verify(t, s, "bob-1")
+			},
+			wantCalls: map[string]int{"alice": 1, "bob": 2, "carol": 1},
+		},
+		{
+			name: "ExplicitCheck",
+			run: func(t *testing.T, s *CachedService) {
+				if _, err := s.VerifyTokenAndCheckRevoked(context.Background(), "alice-1"); err != nil {
+					t.Fatalf("VerifyTokenAndCheckRevoked: %v", err)
+				}
+				verify(t, s, "alice-1")
+			},
+			wantCalls: map[string]int{"alice": 2},
+		},
+		{
+			name: "Revoked",
+			run: func(t *testing.T, s *CachedService) {
+				verify(t, s, "bob-1")
+				if err := s.RevokeRefreshTokens(context.Background(), "alice"); err != nil {
+					t.Fatalf("RevokeRefreshTokens: %v", err)
+				}
+				if _, err := s.VerifyToken(context.Background(), "alice-1"); !errors.Is(err, ErrTokenRevoked) {
+					t.Fatalf("VerifyToken after revocation: %v, want ErrTokenRevoked", err)
+				}
+				verify(t, s, "bob-1")
+			},
+			wantCalls: map[string]int{"alice": 2, "bob": 1},
+		},
+		{
+			name: "UserDisabled",
+			run: func(t *testing.T, s *CachedService) {
+				if err := s.SetUserDisabled(context.Background(), "alice", true); err != nil {
+					t.Fatalf("SetUserDisabled: %v", err)
+				}
+				verify(t, s, "alice-1")
+			},
+			wantCalls: map[string]int{"alice": 2},
+		},
+		{
+			name: "UserEnabled",
+			run: func(t *testing.T, s *CachedService) {
+				if err := s.SetUserDisabled(context.Background(), "alice", false); err != nil {
+					t.Fatalf("SetUserDisabled: %v", err)
+				}
+				verify(t, s, "alice-1")
+			},
+			wantCalls: map[string]int{"alice": 1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := cfg
+			upstream := newStubAuthService()
+			if tc.configure != nil {
+				tc.configure(&cfg, upstream)
+			}
+			s := NewCachedService(upstream, nil, &cfg)
+
+			verify(t, s, "alice-1")
+			tc.run(t, s)
+
+			for uid, want := range tc.wantCalls {
+				if got := upstream.calls[uid]; got != want {
+					t.Errorf("verifications of %s = %d, want %d", uid, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCachedServiceSharedStore(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.AuthConfig{TokenCacheSize: 10, TokenCacheTTL: time.Hour, RevocationCheckInterval: time.Hour}
+	store := cache.NewMemoryStore()
+	upstream := newStubAuthService()
+	first := NewCachedService(upstream, store, cfg)
+
+	verify(t, first, "alice-1")
+
+	// Other instances reuse the verification through the shared store
+	token := verify(t, NewCachedService(upstream, store, cfg), "alice-1")
+	if token.UID != "alice" || token.Claims["email"] != "alice@example.com" {
+		t.Errorf("shared token = %+v", token)
+	}
+	if upstream.calls["alice"] != 1 {
+		t.Fatalf("verifications = %d, want 1", upstream.calls["alice"])
+	}
+
+	// Revoking through one instance hides the shared entries from the others
+	if err := first.RevokeRefreshTokens(ctx, "alice"); err != nil {
+		t.Fatalf("RevokeRefreshTokens: %v", err)
+	}
+	if _, err := NewCachedService(upstream, store, cfg).VerifyToken(ctx, "alice-1"); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("VerifyToken after revocation: %v, want ErrTokenRevoked", err)
+	}
+
+	// Tokens verified after the revocation are shared again
+	upstream.revoked["alice"] = false
+	time.Sleep(time.Millisecond)
+	verify(t, first, "alice-2")
+	verify(t, NewCachedService(upstream, store, cfg), "alice-2")
+	if upstream.calls["alice"] != 3 {
+		t.Errorf("verifications = %d, want 3", upstream.calls["alice"])
+	}
+}
+
+// verify verifies a token that must be valid
+func verify(t *testing.T, s *CachedService, idToken string) *auth.Token {
+	t.Helper()
+
+	token, err := s.VerifyToken(context.Background(), idToken)
+	if err != nil {
+		t.Fatalf("VerifyToken(%s): %v", idToken, err)
+	}
+	return token
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+
+	"firebase.google.com/go/v4/auth"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/tracing"
+)
+
+// TracedService records a span for every call to an auth service. It should
+// wrap the provider's service directly, so calls answered by the token cache
+// do not show up as provider calls.
+type TracedService struct {
+	service  model.AuthService
+	provider string
+}
+
+// Ensure TracedService implements model.AuthService
+var _ model.AuthService = (*TracedService)(nil)
+
+// NewTracedService wraps the auth service of a provider with tracing
+func NewTracedService(service model.AuthService, provider string) *TracedService {
+	return &TracedService{
+		service:  service,
+		provider: provider,
+	}
+}
+
+// VerifyToken verifies a token within a span tagged with the token's user
+func (s *TracedService) VerifyToken(ctx context.Context, idToken string) (_ *auth.Token, err error) {
+	ctx, span := s.start(ctx, "VerifyToken", "")
+	defer func() { tracing.End(span, err) }()
+
+	token, err := s.service.VerifyToken(ctx, idToken)
+	if err == nil {
+		span.SetAttributes(attribute.String("enduser.id", token.UID))
+	}
+	return token, err
+}
+
+// VerifyTokenAndCheckRevoked verifies a token and checks for its revocation
+// within a span tagged with the token's user
+func (s *TracedService) VerifyTokenAndCheckRevoked(ctx context.Context, idToken string) (_ *auth.Token, err error) {
+	ctx, span := s.start(ctx, "VerifyTokenAndCheckRevoked", "")
+	defer func() { tracing.End(span, err) }()
+
+	token, err := s.service.VerifyTokenAndCheckRevoked(ctx, idToken)
+	if err == nil {
+		span.SetAttributes(attribute.String("enduser.id", token.UID))
+	}
+	return token, err
+}
+
+// GetUser gets a user within a span
+func (s *TracedService) GetUser(ctx context.Context, uid string) (_ *auth.UserRecord, err error) {
+	ctx, span := s.start(ctx, "GetUser", uid)
+	defer func() { tracing.End(span, err) }()
+
+	return s.service.GetUser(ctx, uid)
+}
+
+// SetCustomUserClaims sets a user's custom claims within a span
+func (s *TracedService) SetCustomUserClaims(ctx context.Context, uid string, claims map[string]interface{}) (err error) {
+	ctx, span := s.start(ctx, "SetCustomUserClaims", uid)
+	defer func() { tracing.End(span, err) }()
+
+	return s.service.SetCustomUserClaims(ctx, uid, claims)
+}
+
+// SetUserDisabled disables or enables a user within a span
+func (s *TracedService) SetUserDisabled(ctx context.Context, uid string, disabled bool) (err error) {
+	ctx, span := s.start(ctx, "SetUserDisabled", uid)
+	defer func() { tracing.End(span, err) }()
+
+	return s.service.SetUserDisabled(ctx, uid, disabled)
+}
+
+// RevokeRefreshTokens revokes a user's tokens within a span
+func (s *TracedService) RevokeRefreshTokens(ctx context.Context, uid string) (err error) {
+	ctx, span := s.start(ctx, "RevokeRefreshTokens", uid)
+	defer func() { tracing.End(span, err) }()
+
+	return s.service.RevokeRefreshTokens(ctx, uid)
+}
+
+// start starts the span of a call, tagged with the user it concerns if known
+func (s *TracedService) start(ctx context.Context, operation, uid string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{attribute.String("auth.provider", s.provider)}
+	if uid != "" {
+		attrs = append(attrs, attribute.String("enduser.id", uid))
+	}
+	return tracing.Start(ctx, "auth.Service."+operation, trace.WithAttributes(attrs...))
+}
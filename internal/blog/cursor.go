@@ -0,0 +1,96 @@
+package blog
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// listCursor marks the position after the last blog of a page. Value holds the
+// sort key of that blog (created_at in Unix milliseconds, or a counter) and ID
+// breaks ties so that pages stay stable while new blogs are inserted.
+type listCursor struct {
+	Sort  model.BlogSort     `json:"s"`
+	Value int64              `json:"v"`
+	ID    primitive.ObjectID `json:"id"`
+}
+
+// sortField returns the document field a listing is ordered by
+func sortField(sort model.BlogSort) string {
+	switch sort {
+	case model.BlogSortMostLiked:
+		return "like_count"
+	case model.BlogSortMostCommented:
+		return "comment_count"
+	default:
+		return "created_at"
+	}
+}
+
+// sortValue returns the sort key of a blog as stored in a cursor
+func sortValue(sort model.BlogSort, blog *model.Blog) int64 {
+	switch sort {
+	case model.BlogSortMostLiked:
+		return blog.LikeCount
+	case model.BlogSortMostCommented:
+		return blog.CommentCount
+	default:
+		return blog.CreatedAt.UnixMilli()
+	}
+}
+
+// newListCursor builds the cursor pointing after the given blog
+func newListCursor(sort model.BlogSort, blog *model.Blog) listCursor {
+	return listCursor{Sort: sort, Value: sortValue(sort, blog), ID: blog.ID}
+}
+
+// encode serializes the cursor into an opaque URL-safe token
+func (c listCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeListCursor parses a token produced by encode for the given sort
+func decodeListCursor(token string, sort model.BlogSort) (listCursor, error) {
+	var cursor listCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID.IsZero() {
+		return cursor, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+	if cursor.Sort != sort {
+		return cursor, fmt.Errorf("%w: cursor was issued for a different sort", ErrInvalidInput)
+	}
+	return cursor, nil
+}
+
+// filter returns the condition selecting documents strictly after the cursor
+// in descending (sort key, _id) order
+func (c listCursor) filter() bson.M {
+	field := sortField(c.Sort)
+
+	var value interface{} = c.Value
+	if field == "created_at" {
+		value = primitive.DateTime(c.Value)
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{"$lt": value}},
+		bson.M{field: value, "_id": bson.M{"$lt": c.ID}},
+	}}
+}
+
+// after reports whether a blog comes strictly after the cursor in descending
+// (sort key, _id) order, matching the condition built by filter
+func (c listCursor) after(blog *model.Blog) bool {
+	value := sortValue(c.Sort, blog)
+	return value < c.Value || (value == c.Value && bytes.Compare(blog.ID[:], c.ID[:]) < 0)
+}
@@ -0,0 +1,443 @@
+package blog
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests related to blogs
+type Handler struct {
+	blogService *Service
+}
+
+// NewHandler creates a new blog handler
+func NewHandler(blogService *Service) *Handler {
+	return &Handler{
+		blogService: blogService,
+	}
+}
+
+// BlogResponse represents the response for blog operations
+type BlogResponse struct {
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Slug          string   `json:"slug"`
+	Content       string   `json:"content"`
+	AuthorID      string   `json:"author_id"`
+	Tags          []string `json:"tags"`
+	ImageURL      string   `json:"image_url,omitempty"`
+	LikeCount     int64    `json:"like_count"`
+	BookmarkCount int64    `json:"bookmark_count"`
+	CommentCount  int64    `json:"comment_count"`
+	Status        string   `json:"status"`
+	PublishAt     string   `json:"publish_at,omitempty"`
+	Version       int      `json:"version"`
+	CreatedAt     string   `json:"created_at"`
+	UpdatedAt     string   `json:"updated_at"`
+
+	// Rendering of Content, only included when fetching a single blog
+	ContentHTML        string           `json:"content_html,omitempty"`
+	TOC                []model.TOCEntry `json:"toc,omitempty"`
+	ReadingTimeMinutes int              `json:"reading_time_minutes,omitempty"`
+}
+
+// newBlogResponse converts a blog model into its API representation
+func newBlogResponse(blog *model.Blog) BlogResponse {
+	response := BlogResponse{
+		ID:            blog.ID.Hex(),
+		Title:         blog.Title,
+		Slug:          blog.Slug,
+		Content:       blog.Content,
+		AuthorID:      blog.AuthorID,
+		Tags:          blog.Tags,
+		ImageURL:      blog.ImageURL,
+		LikeCount:     blog.LikeCount,
+		BookmarkCount: blog.BookmarkCount,
+		CommentCount:  blog.CommentCount,
+		Status:        string(blog.Status),
+		Version:       blog.Version,
+		CreatedAt:     blog.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:     blog.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if blog.PublishAt != nil {
+		response.PublishAt = blog.PublishAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return response
+}
+
+// ListBlogs handles listing blogs with filters and cursor pagination.
+// Query parameters: tag, author, status (a blog status or all), from and to
+// (RFC 3339 or YYYY-MM-DD), sort (newest, most_liked, most_commented), limit
+// and cursor (the next_cursor of the previous page).
+func (h *Handler) ListBlogs(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "ListBlogs")
+
+	filter := model.BlogListFilter{
+		Tag:      c.Query("tag"),
+		AuthorID: c.Query("author"),
+		Sort:     model.BlogSort(c.Query("sort")),
+		Cursor:   c.Query("cursor"),
+	}
+
+	if status := c.DefaultQuery("status", string(model.BlogStatusPublished)); status != "all" {
+		filter.Status = model.BlogStatus(status)
+	}
+
+	var err error
+	if filter.Limit, err = utils.ParseQueryInt(c, "limit"); err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "limit must be an integer"))
+		return
+	}
+	if filter.From, err = parseQueryTime(c, "from"); err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "from must be an RFC 3339 timestamp or YYYY-MM-DD date"))
+		return
+	}
+	if filter.To, err = parseQueryTime(c, "to"); err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "to must be an RFC 3339 timestamp or YYYY-MM-DD date"))
+		return
+	}
+
+	// The viewer is only known when OptionalAuth accepted a token
+	page, err := h.blogService.ListBlogs(c.Request.Context(), middleware.GetViewer(c), &filter)
+	if err != nil {
+		logger.Warn("Failed to list blogs: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	response := make([]BlogResponse, 0, len(page.Blogs))
+	for _, blog := range page.Blogs {
+		response = append(response, newBlogResponse(blog))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"blogs":       response,
+		"next_cursor": page.NextCursor,
+	})
+}
+
+// ListPopularBlogs handles listing the most liked published blogs
+func (h *Handler) ListPopularBlogs(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "ListPopularBlogs")
+
+	blogs, err := h.blogService.ListPopularBlogs(c.Request.Context())
+	if err != nil {
+		logger.Error("Failed to list popular blogs: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	response := make([]BlogResponse, 0, len(blogs))
+	for _, blog := range blogs {
+		response = append(response, newBlogResponse(blog))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blogs": response})
+}
+
+// GetBlog handles fetching a single blog
+func (h *Handler) GetBlog(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "GetBlog")
+
+	blogID := c.Param("id")
+	blog, err := h.blogService.GetVisibleBlog(c.Request.Context(), blogID, middleware.GetViewer(c))
+	if err != nil {
+		logger.With("blogID", blogID).Warn("Failed to get blog: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	response, err := h.renderedBlogResponse(c, blog)
+	if err != nil {
+		logger.With("blogID", blogID).Error("Failed to render blog content: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	setETag(c, blog)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBlogBySlug handles fetching a single blog by slug. Former slugs of a
+// renamed blog are answered with a permanent redirect to the current one.
+func (h *Handler) GetBlogBySlug(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "GetBlogBySlug")
+
+	slug := c.Param("slug")
+	blog, err := h.blogService.GetBlogBySlug(c.Request.Context(), slug, middleware.GetViewer(c))
+	if err != nil {
+		logger.With("slug", slug).Warn("Failed to get blog: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	if blog.Slug != slug {
+		location := url.URL{
+			Path:     path.Join(path.Dir(c.Request.URL.Path), blog.Slug),
+			RawQuery: c.Request.URL.RawQuery,
+		}
+		c.Redirect(http.StatusMovedPermanently, location.String())
+		return
+	}
+
+	response, err := h.renderedBlogResponse(c, blog)
+	if err != nil {
+		logger.With("slug", slug).Error("Failed to render blog content: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	setETag(c, blog)
+	c.JSON(http.StatusOK, response)
+}
+
+// renderedBlogResponse builds the response for a single blog including its
+// rendered content
+func (h *Handler) renderedBlogResponse(c *gin.Context, blog *model.Blog) (BlogResponse, error) {
+	rendered, err := h.blogService.RenderBlog(c.Request.Context(), blog)
+	if err != nil {
+		return BlogResponse{}, err
+	}
+
+	response := newBlogResponse(blog)
+	response.ContentHTML = rendered.HTML
+	response.TOC = rendered.TOC
+	response.ReadingTimeMinutes = rendered.ReadingTimeMinutes
+	return response, nil
+}
+
+// CreateBlog handles creating a blog for the authenticated user
+func (h *Handler) CreateBlog(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "CreateBlog")
+
+	var input model.BlogInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		apperror.Respond(c, apperror.InvalidRequest(err))
+		return
+	}
+
+	// Get the user ID from the context (set by auth middleware)
+	uid, exists := c.Get("uid")
+	if !exists {
+		logger.Error("User ID not found in context - authentication middleware may have failed")
+		apperror.Respond(c, apperror.ErrUnauthenticated)
+		return
+	}
+
+	userID := uid.(string)
+	blog, err := h.blogService.CreateBlog(c.Request.Context(), userID, &input)
+	if err != nil {
+		logger.With("userID", userID).Warn("Failed to create blog: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newBlogResponse(blog))
+}
+
+// UpdateBlog handles updating a blog the authenticated user may edit
+func (h *Handler) UpdateBlog(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "UpdateBlog")
+
+	var input model.BlogUpdateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		apperror.Respond(c, apperror.InvalidRequest(err))
+		return
+	}
+	if input.Version == nil {
+		version, err := ifMatchVersion(c)
+		if err != nil {
+			apperror.Respond(c, err)
+			return
+		}
+		input.Version = version
+	}
+
+	uid, exists := c.Get("uid")
+	if !exists {
+		logger.Error("User ID not found in context - authentication middleware may have failed")
+		apperror.Respond(c, apperror.ErrUnauthenticated)
+		return
+	}
+
+	userID := uid.(string)
+	blogID := c.Param("id")
+	blog, err := h.blogService.UpdateBlog(c.Request.Context(), blogID, middleware.GetViewer(c), &input)
+	if err != nil {
+		logger.With("userID", userID, "blogID", blogID).Warn("Failed to update blog: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	setETag(c, blog)
+	c.JSON(http.StatusOK, newBlogResponse(blog))
+}
+
+// DeleteBlog handles deleting a blog the authenticated user may delete
+func (h *Handler) DeleteBlog(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "DeleteBlog")
+
+	uid, exists := c.Get("uid")
+	if !exists {
+		logger.Error("User ID not found in context - authentication middleware may have failed")
+		apperror.Respond(c, apperror.ErrUnauthenticated)
+		return
+	}
+
+	userID := uid.(string)
+	blogID := c.Param("id")
+	if err := h.blogService.DeleteBlog(c.Request.Context(), blogID, middleware.GetViewer(c)); err != nil {
+		logger.With("userID", userID, "blogID", blogID).Warn("Failed to delete blog: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevisionResponse represents a blog revision in the revision history
+type RevisionResponse struct {
+	Version   int      `json:"version"`
+	Title     string   `json:"title"`
+	Tags      []string `json:"tags"`
+	ImageURL  string   `json:"image_url,omitempty"`
+	EditorID  string   `json:"editor_id"`
+	Summary   string   `json:"summary"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// ListRevisions handles listing the revision history of a blog
+func (h *Handler) ListRevisions(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "ListRevisions")
+
+	blogID := c.Param("id")
+	revisions, err := h.blogService.ListRevisions(c.Request.Context(), blogID, middleware.GetViewer(c))
+	if err != nil {
+		logger.With("blogID", blogID).Warn("Failed to list blog revisions: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	response := make([]RevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		response = append(response, RevisionResponse{
+			Version:   revision.Version,
+			Title:     revision.Title,
+			Tags:      revision.Tags,
+			ImageURL:  revision.ImageURL,
+			EditorID:  revision.EditorID,
+			Summary:   revision.Summary,
+			CreatedAt: revision.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": response})
+}
+
+// DiffRevisions handles diffing two revisions of a blog given by the from
+// and to query parameters
+func (h *Handler) DiffRevisions(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "DiffRevisions")
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "from must be a revision version"))
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "to must be a revision version"))
+		return
+	}
+
+	blogID := c.Param("id")
+	diff, err := h.blogService.DiffRevisions(c.Request.Context(), blogID, middleware.GetViewer(c), from, to)
+	if err != nil {
+		logger.With("blogID", blogID).Warn("Failed to diff blog revisions: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreRevision handles restoring an older revision of a blog the
+// authenticated user may edit
+func (h *Handler) RestoreRevision(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "RestoreRevision")
+
+	uid, exists := c.Get("uid")
+	if !exists {
+		logger.Error("User ID not found in context - authentication middleware may have failed")
+		apperror.Respond(c, apperror.ErrUnauthenticated)
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "version must be an integer"))
+		return
+	}
+	expectedVersion, err := ifMatchVersion(c)
+	if err != nil {
+		apperror.Respond(c, err)
+		return
+	}
+
+	userID := uid.(string)
+	blogID := c.Param("id")
+	blog, err := h.blogService.RestoreRevision(c.Request.Context(), blogID, middleware.GetViewer(c), version, expectedVersion)
+	if err != nil {
+		logger.With("userID", userID, "blogID", blogID).Warn("Failed to restore blog revision: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	setETag(c, blog)
+	c.JSON(http.StatusOK, newBlogResponse(blog))
+}
+
+// setETag sets the ETag header to the blog's version, which clients send
+// back in If-Match to edit that version
+func setETag(c *gin.Context, blog *model.Blog) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(blog.Version)))
+}
+
+// ifMatchVersion reads the blog version from the If-Match header, returning
+// nil when absent. Both "3" and 3 are accepted.
+func ifMatchVersion(c *gin.Context) (*int, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" {
+		return nil, nil
+	}
+	version, err := strconv.Atoi(strings.Trim(value, `"`))
+	if err != nil {
+		return nil, apperror.Validation("invalid_if_match", "If-Match must be a blog version")
+	}
+	return &version, nil
+}
+
+// parseQueryTime reads an optional RFC 3339 timestamp or YYYY-MM-DD date,
+// returning the zero time when absent
+func parseQueryTime(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -0,0 +1,315 @@
+package blog
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository is a thread-safe in-memory implementation of
+// model.BlogRepository, used by tests and local development without MongoDB.
+// Blogs are copied on the way in and out, so callers never share state with
+// the store.
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	blogs map[primitive.ObjectID]*model.Blog
+}
+
+// Ensure MemoryRepository implements model.BlogRepository
+var _ model.BlogRepository = (*MemoryRepository)(nil)
+
+// NewMemoryRepository creates an empty in-memory blog repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		blogs: make(map[primitive.ObjectID]*model.Blog),
+	}
+}
+
+// FindByID finds a blog by ID
+func (r *MemoryRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Blog, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	blog, ok := r.blogs[id]
+	if !ok {
+		return nil, ErrBlogNotFound
+	}
+	return cloneBlog(blog), nil
+}
+
+// List lists a page of blogs matching the filter, in the same order and with
+// the same cursors as Repository.List
+func (r *MemoryRepository) List(ctx context.Context, filter *model.BlogListFilter) (*model.BlogPage, error) {
+	var after *listCursor
+	if filter.Cursor != "" {
+		cursor, err := decodeListCursor(filter.Cursor, filter.Sort)
+		if err != nil {
+			return nil, err
+		}
+		after = &cursor
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []*model.Blog
+	for _, blog := range r.blogs {
+		if matchesListFilter(blog, filter) && (after == nil || after.after(blog)) {
+			matches = append(matches, blog)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		vi, vj := sortValue(filter.Sort, matches[i]), sortValue(filter.Sort, matches[j])
+		if vi != vj {
+			return vi > vj
+		}
+		return bytes.Compare(matches[i].ID[:], matches[j].ID[:]) > 0
+	})
+
+	page := &model.BlogPage{Blogs: cloneBlogs(matches)}
+	if int64(len(matches)) > filter.Limit {
+		page.Blogs = page.Blogs[:filter.Limit]
+		page.NextCursor = newListCursor(filter.Sort, page.Blogs[len(page.Blogs)-1]).encode()
+	}
+	return page, nil
+}
+
+// ListPopular lists the most liked published blogs
+func (r *MemoryRepository) ListPopular(ctx context.Context, limit int64) ([]*model.Blog, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var published []*model.Blog
+	for _, blog := range r.blogs {
+		if blog.Status == model.BlogStatusPublished {
+			published = append(published, blog)
+		}
+	}
+	sort.Slice(published, func(i, j int) bool {
+		if published[i].LikeCount != published[j].LikeCount {
+			return published[i].LikeCount > published[j].LikeCount
+		}
+		return published[i].CreatedAt.After(published[j].CreatedAt)
+	})
+
+	if limit > 0 && int64(len(published)) > limit {
+		published = published[:limit]
+	}
+	return cloneBlogs(published), nil
+}
+
+// Create creates a new blog and sets its generated ID
+func (r *MemoryRepository) Create(ctx context.Context, blog *model.Blog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if blog.ID.IsZero() {
+		blog.ID = primitive.NewObjectID()
+	}
+	r.blogs[blog.ID] = cloneBlog(blog)
+	return nil
+}
+
+// Update updates the editable fields of an existing blog if its stored
+// version still equals expectedVersion
+func (r *MemoryRepository) Update(ctx context.Context, blog *model.Blog, expectedVersion int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	blog.UpdatedAt = time.Now()
+
+	stored, ok := r.blogs[blog.ID]
+	if !ok {
+		return ErrBlogNotFound
+	}
+	if stored.Version != expectedVersion {
+		return ErrVersionConflict
+	}
+
+	edited := cloneBlog(blog)
+	stored.Title = edited.Title
+	stored.Slug = edited.Slug
+	stored.Content = edited.Content
+	stored.Tags = edited.Tags
+	stored.ImageURL = edited.ImageURL
+	stored.Status = edited.Status
+	stored.PublishAt = edited.PublishAt
+	stored.Version = edited.Version
+	stored.UpdatedAt = edited.UpdatedAt
+	return nil
+}
+
+// PublishDue publishes every scheduled blog whose publish time has passed and
+// returns the IDs it published
+func (r *MemoryRepository) PublishDue(ctx context.Context, now time.Time) ([]primitive.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	published := []primitive.ObjectID{}
+	for id, blog := range r.blogs {
+		if blog.Status == model.BlogStatusScheduled && blog.PublishAt != nil && !blog.PublishAt.After(now) {
+			blog.Status = model.BlogStatusPublished
+			blog.UpdatedAt = now
+			published = append(published, id)
+		}
+	}
+	slices.SortFunc(published, func(a, b primitive.ObjectID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return published, nil
+}
+
+// CountByAuthor counts the blogs written by an author in any status
+func (r *MemoryRepository) CountByAuthor(ctx context.Context, authorID string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, blog := range r.blogs {
+		if blog.AuthorID == authorID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Delete deletes a blog by ID
+func (r *MemoryRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.blogs[id]; !ok {
+		return ErrBlogNotFound
+	}
+	delete(r.blogs, id)
+	return nil
+}
+
+// IncrementCommentCount adjusts a blog's comment count by delta
+func (r *MemoryRepository) IncrementCommentCount(ctx context.Context, id primitive.ObjectID, delta int64) error {
+	_, err := r.modify(id, func(blog *model.Blog) {
+		blog.CommentCount += delta
+	})
+	return err
+}
+
+// ToggleLike adds or removes the user's like
+func (r *MemoryRepository) ToggleLike(ctx context.Context, id primitive.ObjectID, userID string) (*model.Blog, error) {
+	return r.modify(id, func(blog *model.Blog) {
+		blog.Likes = toggle(blog.Likes, userID)
+		blog.LikeCount = int64(len(blog.Likes))
+	})
+}
+
+// ToggleBookmark adds or removes the user's bookmark
+func (r *MemoryRepository) ToggleBookmark(ctx context.Context, id primitive.ObjectID, userID string) (*model.Blog, error) {
+	return r.modify(id, func(blog *model.Blog) {
+		blog.BookmarkedBy = toggle(blog.BookmarkedBy, userID)
+		blog.BookmarkCount = int64(len(blog.BookmarkedBy))
+	})
+}
+
+// SetLike adds or removes the user's like
+func (r *MemoryRepository) SetLike(ctx context.Context, id primitive.ObjectID, userID string, liked bool) (*model.Blog, error) {
+	return r.modify(id, func(blog *model.Blog) {
+		blog.Likes = set(blog.Likes, userID, liked)
+		blog.LikeCount = int64(len(blog.Likes))
+	})
+}
+
+// SetBookmark adds or removes the user's bookmark
+func (r *MemoryRepository) SetBookmark(ctx context.Context, id primitive.ObjectID, userID string, bookmarked bool) (*model.Blog, error) {
+	return r.modify(id, func(blog *model.Blog) {
+		blog.BookmarkedBy = set(blog.BookmarkedBy, userID, bookmarked)
+		blog.BookmarkCount = int64(len(blog.BookmarkedBy))
+	})
+}
+
+// modify applies change to a stored blog under the write lock and returns a
+// copy of the result
+func (r *MemoryRepository) modify(id primitive.ObjectID, change func(blog *model.Blog)) (*model.Blog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	blog, ok := r.blogs[id]
+	if !ok {
+		return nil, ErrBlogNotFound
+	}
+	change(blog)
+	return cloneBlog(blog), nil
+}
+
+// matchesListFilter mirrors the MongoDB query built by Repository.List,
+// without the cursor condition
+func matchesListFilter(blog *model.Blog, filter *model.BlogListFilter) bool {
+	if filter.Status != "" && blog.Status != filter.Status {
+		return false
+	}
+	if filter.Tag != "" && !slices.Contains(blog.Tags, filter.Tag) {
+		return false
+	}
+	if filter.AuthorID != "" && blog.AuthorID != filter.AuthorID {
+		return false
+	}
+	if !filter.From.IsZero() && blog.CreatedAt.Before(filter.From) {
+		return false
+	}
+	if !filter.To.IsZero() && !blog.CreatedAt.Before(filter.To) {
+		return false
+	}
+	return true
+}
+
+// toggle removes userID from ids if present and appends it otherwise
+func toggle(ids []string, userID string) []string {
+	if slices.Contains(ids, userID) {
+		return remove(ids, userID)
+	}
+	return append(ids, userID)
+}
+
+// set adds userID to ids if present is true, once, and removes it otherwise
+func set(ids []string, userID string, present bool) []string {
+	if !present {
+		return remove(ids, userID)
+	}
+	if slices.Contains(ids, userID) {
+		return ids
+	}
+	return append(ids, userID)
+}
+
+// remove removes every occurrence of userID from ids
+func remove(ids []string, userID string) []string {
+	return slices.DeleteFunc(ids, func(id string) bool {
+		return id == userID
+	})
+}
+
+// cloneBlogs returns deep copies of blogs, never nil
+func cloneBlogs(blogs []*model.Blog) []*model.Blog {
+	clones := make([]*model.Blog, 0, len(blogs))
+	for _, blog := range blogs {
+		clones = append(clones, cloneBlog(blog))
+	}
+	return clones
+}
+
+// cloneBlog returns a deep copy of a blog
+func cloneBlog(blog *model.Blog) *model.Blog {
+	clone := *blog
+	clone.Tags = slices.Clone(blog.Tags)
+	clone.Likes = slices.Clone(blog.Likes)
+	clone.BookmarkedBy = slices.Clone(blog.BookmarkedBy)
+	if blog.PublishAt != nil {
+		publishAt := *blog.PublishAt
+		clone.PublishAt = &publishAt
+	}
+	return &clone
+}
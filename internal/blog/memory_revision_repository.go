@@ -0,0 +1,96 @@
+package blog
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRevisionRepository is a thread-safe in-memory implementation of
+// model.BlogRevisionRepository
+type MemoryRevisionRepository struct {
+	mu        sync.RWMutex
+	revisions []*model.BlogRevision
+}
+
+// Ensure MemoryRevisionRepository implements model.BlogRevisionRepository
+var _ model.BlogRevisionRepository = (*MemoryRevisionRepository)(nil)
+
+// NewMemoryRevisionRepository creates an empty in-memory blog revision repository
+func NewMemoryRevisionRepository() *MemoryRevisionRepository {
+	return &MemoryRevisionRepository{}
+}
+
+// Create stores a revision and sets its generated ID. Saving a version the
+// blog already has returns ErrVersionConflict, like the unique index of
+// RevisionRepository.
+func (r *MemoryRevisionRepository) Create(ctx context.Context, revision *model.BlogRevision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.revisions {
+		if existing.BlogID == revision.BlogID && existing.Version == revision.Version {
+			return ErrVersionConflict
+		}
+	}
+
+	if revision.ID.IsZero() {
+		revision.ID = primitive.NewObjectID()
+	}
+	r.revisions = append(r.revisions, cloneRevision(revision))
+	return nil
+}
+
+// FindByVersion finds a single revision of a blog
+func (r *MemoryRevisionRepository) FindByVersion(ctx context.Context, blogID primitive.ObjectID, version int) (*model.BlogRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, revision := range r.revisions {
+		if revision.BlogID == blogID && revision.Version == version {
+			return cloneRevision(revision), nil
+		}
+	}
+	return nil, ErrRevisionNotFound
+}
+
+// ListByBlog lists a blog's revisions, newest first, without their content
+func (r *MemoryRevisionRepository) ListByBlog(ctx context.Context, blogID primitive.ObjectID) ([]*model.BlogRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := []*model.BlogRevision{}
+	for _, revision := range r.revisions {
+		if revision.BlogID == blogID {
+			clone := cloneRevision(revision)
+			clone.Content = ""
+			revisions = append(revisions, clone)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Version > revisions[j].Version
+	})
+	return revisions, nil
+}
+
+// DeleteByBlog deletes every revision of a blog
+func (r *MemoryRevisionRepository) DeleteByBlog(ctx context.Context, blogID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.revisions = slices.DeleteFunc(r.revisions, func(revision *model.BlogRevision) bool {
+		return revision.BlogID == blogID
+	})
+	return nil
+}
+
+// cloneRevision returns a deep copy of a revision
+func cloneRevision(revision *model.BlogRevision) *model.BlogRevision {
+	clone := *revision
+	clone.Tags = slices.Clone(revision.Tags)
+	return &clone
+}
@@ -0,0 +1,64 @@
+package blog
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemorySlugRepository is a thread-safe in-memory implementation of
+// model.BlogSlugRepository
+type MemorySlugRepository struct {
+	mu    sync.RWMutex
+	slugs map[string]primitive.ObjectID
+}
+
+// Ensure MemorySlugRepository implements model.BlogSlugRepository
+var _ model.BlogSlugRepository = (*MemorySlugRepository)(nil)
+
+// NewMemorySlugRepository creates an empty in-memory blog slug repository
+func NewMemorySlugRepository() *MemorySlugRepository {
+	return &MemorySlugRepository{
+		slugs: make(map[string]primitive.ObjectID),
+	}
+}
+
+// Reserve claims a slug for a blog. A slug the blog already owned can be
+// claimed again; a slug owned by any other blog returns ErrSlugTaken.
+func (r *MemorySlugRepository) Reserve(ctx context.Context, slug string, blogID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if owner, ok := r.slugs[slug]; ok && owner != blogID {
+		return ErrSlugTaken
+	}
+	r.slugs[slug] = blogID
+	return nil
+}
+
+// FindBlogID finds the blog a slug belongs to
+func (r *MemorySlugRepository) FindBlogID(ctx context.Context, slug string) (primitive.ObjectID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	blogID, ok := r.slugs[slug]
+	if !ok {
+		return primitive.NilObjectID, ErrBlogNotFound
+	}
+	return blogID, nil
+}
+
+// DeleteByBlog releases every slug of a blog
+func (r *MemorySlugRepository) DeleteByBlog(ctx context.Context, blogID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for slug, owner := range r.slugs {
+		if owner == blogID {
+			delete(r.slugs, slug)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package blog
+
+import (
+	"context"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/db"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const collectionName = "blogs"
+
+// Repository handles blog data operations
+type Repository struct {
+	db         *db.MongoDB
+	collection string
+}
+
+// Ensure Repository implements model.BlogRepository
+var _ model.BlogRepository = (*Repository)(nil)
+
+// NewRepository creates a new blog repository
+func NewRepository(mongodb *db.MongoDB) *Repository {
+	return &Repository{
+		db:         mongodb,
+		collection: collectionName,
+	}
+}
+
+// FindByID finds a blog by ID
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Blog, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	var blog model.Blog
+	err := coll.FindOne(ctx, bson.M{"_id": id}).Decode(&blog)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrBlogNotFound
+		}
+		return nil, err
+	}
+
+	return &blog, nil
+}
+
+// List lists a page of blogs matching the filter. One extra blog is fetched
+// to tell whether another page follows.
+func (r *Repository) List(ctx context.Context, filter *model.BlogListFilter) (*model.BlogPage, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Tag != "" {
+		query["tags"] = filter.Tag
+	}
+	if filter.AuthorID != "" {
+		query["author_id"] = filter.AuthorID
+	}
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		createdAt := bson.M{}
+		if !filter.From.IsZero() {
+			createdAt["$gte"] = filter.From
+		}
+		if !filter.To.IsZero() {
+			createdAt["$lt"] = filter.To
+		}
+		query["created_at"] = createdAt
+	}
+
+	conditions := bson.A{query}
+	if filter.Cursor != "" {
+		cursor, err := decodeListCursor(filter.Cursor, filter.Sort)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cursor.filter())
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: sortField(filter.Sort), Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(filter.Limit + 1)
+
+	cursor, err := coll.Find(ctx, bson.M{"$and": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	blogs := []*model.Blog{}
+	if err := cursor.All(ctx, &blogs); err != nil {
+		return nil, err
+	}
+
+	page := &model.BlogPage{Blogs: blogs}
+	if int64(len(blogs)) > filter.Limit {
+		page.Blogs = blogs[:filter.Limit]
+		page.NextCursor = newListCursor(filter.Sort, page.Blogs[len(page.Blogs)-1]).encode()
+	}
+	return page, nil
+}
+
+// ListPopular lists the most liked published blogs
+func (r *Repository) ListPopular(ctx context.Context, limit int64) ([]*model.Blog, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "like_count", Value: -1}, {Key: "created_at", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := coll.Find(ctx, bson.M{"status": model.BlogStatusPublished}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	blogs := []*model.Blog{}
+	if err := cursor.All(ctx, &blogs); err != nil {
+		return nil, err
+	}
+
+	return blogs, nil
+}
+
+// Create creates a new blog and sets its generated ID
+func (r *Repository) Create(ctx context.Context, blog *model.Blog) error {
+	coll := r.db.GetCollection(r.collection)
+
+	result, err := coll.InsertOne(ctx, blog)
+	if err != nil {
+		return err
+	}
+
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		blog.ID = id
+	}
+	return nil
+}
+
+// Update updates the editable fields of an existing blog. Reaction sets and
+// counters are left alone so concurrent likes and bookmarks are not lost. The
+// write only applies if the stored version still equals expectedVersion, so
+// two concurrent edits cannot silently overwrite each other.
+func (r *Repository) Update(ctx context.Context, blog *model.Blog, expectedVersion int) error {
+	coll := r.db.GetCollection(r.collection)
+
+	blog.UpdatedAt = time.Now()
+
+	// Blogs saved before versioning have no version field, which $in null matches
+	versionFilter := interface{}(expectedVersion)
+	if expectedVersion == 0 {
+		versionFilter = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": blog.ID, "version": versionFilter}, bson.M{
+		"$set": bson.M{
+			"title":      blog.Title,
+			"slug":       blog.Slug,
+			"content":    blog.Content,
+			"tags":       blog.Tags,
+			"image_url":  blog.ImageURL,
+			"status":     blog.Status,
+			"publish_at": blog.PublishAt,
+			"version":    blog.Version,
+			"updated_at": blog.UpdatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		if _, err := r.FindByID(ctx, blog.ID); err != nil {
+			return err
+		}
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// PublishDue publishes every scheduled blog whose publish_at has passed and
+// returns the IDs it published. Each blog is flipped with a status-guarded
+// update so concurrent schedulers never publish the same blog twice.
+func (r *Repository) PublishDue(ctx context.Context, now time.Time) ([]primitive.ObjectID, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	filter := bson.M{"status": model.BlogStatusScheduled, "publish_at": bson.M{"$lte": now}}
+	cursor, err := coll.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var due []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &due); err != nil {
+		return nil, err
+	}
+
+	published := []primitive.ObjectID{}
+	for _, blog := range due {
+		result, err := coll.UpdateOne(ctx,
+			bson.M{"_id": blog.ID, "status": model.BlogStatusScheduled},
+			bson.M{"$set": bson.M{"status": model.BlogStatusPublished, "updated_at": now}},
+		)
+		if err != nil {
+			return published, err
+		}
+		if result.ModifiedCount > 0 {
+			published = append(published, blog.ID)
+		}
+	}
+	return published, nil
+}
+
+// CountByAuthor counts the blogs written by an author in any status
+func (r *Repository) CountByAuthor(ctx context.Context, authorID string) (int64, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	return coll.CountDocuments(ctx, bson.M{"author_id": authorID})
+}
+
+// Delete deletes a blog by ID
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	coll := r.db.GetCollection(r.collection)
+
+	result, err := coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrBlogNotFound
+	}
+	return nil
+}
+
+// IncrementCommentCount adjusts a blog's comment_count by delta
+func (r *Repository) IncrementCommentCount(ctx context.Context, id primitive.ObjectID, delta int64) error {
+	coll := r.db.GetCollection(r.collection)
+
+	result, err := coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"comment_count": delta}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrBlogNotFound
+	}
+	return nil
+}
+
+// ToggleLike atomically adds or removes the user's like and recomputes like_count
+func (r *Repository) ToggleLike(ctx context.Context, id primitive.ObjectID, userID string) (*model.Blog, error) {
+	return r.toggleReaction(ctx, id, "likes", "like_count", userID)
+}
+
+// ToggleBookmark atomically adds or removes the user's bookmark and recomputes bookmark_count
+func (r *Repository) ToggleBookmark(ctx context.Context, id primitive.ObjectID, userID string) (*model.Blog, error) {
+	return r.toggleReaction(ctx, id, "bookmarked_by", "bookmark_count", userID)
+}
+
+// SetLike adds or removes the user's like and recomputes like_count
+func (r *Repository) SetLike(ctx context.Context, id primitive.ObjectID, userID string, liked bool) (*model.Blog, error) {
+	return r.setReaction(ctx, id, "likes", "like_count", userID, liked)
+}
+
+// SetBookmark adds or removes the user's bookmark and recomputes bookmark_count
+func (r *Repository) SetBookmark(ctx context.Context, id primitive.ObjectID, userID string, bookmarked bool) (*model.Blog, error) {
+	return r.setReaction(ctx, id, "bookmarked_by", "bookmark_count", userID, bookmarked)
+}
+
+// toggleReaction flips the user's membership of a reaction set in a single
+// pipeline update, so concurrent toggles cannot observe a half-applied state
+// and the counter always equals the size of the set.
+func (r *Repository) toggleReaction(ctx context.Context, id primitive.ObjectID, field, counter, userID string) (*model.Blog, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	current := bson.M{"$ifNull": bson.A{"$" + field, bson.A{}}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{field: bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{userID, current}},
+			bson.M{"$setDifference": bson.A{current, bson.A{userID}}},
+			bson.M{"$concatArrays": bson.A{current, bson.A{userID}}},
+		}}}}},
+		{{Key: "$set", Value: bson.M{counter: bson.M{"$size": "$" + field}}}},
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var blog model.Blog
+	err := coll.FindOneAndUpdate(ctx, bson.M{"_id": id}, pipeline, opts).Decode(&blog)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrBlogNotFound
+		}
+		return nil, err
+	}
+	return &blog, nil
+}
+
+// setReaction puts the user in or out of a reaction set in a single pipeline
+// update. Unlike toggleReaction it is idempotent, so it can safely undo a
+// toggle even if the user toggled again in the meantime.
+func (r *Repository) setReaction(ctx context.Context, id primitive.ObjectID, field, counter, userID string, present bool) (*model.Blog, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	current := bson.M{"$ifNull": bson.A{"$" + field, bson.A{}}}
+	value := bson.M{"$setDifference": bson.A{current, bson.A{userID}}}
+	if present {
+		value = bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{userID, current}},
+			current,
+			bson.M{"$concatArrays": bson.A{current, bson.A{userID}}},
+		}}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{field: value}}},
+		{{Key: "$set", Value: bson.M{counter: bson.M{"$size": "$" + field}}}},
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var blog model.Blog
+	err := coll.FindOneAndUpdate(ctx, bson.M{"_id": id}, pipeline, opts).Decode(&blog)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrBlogNotFound
+		}
+		return nil, err
+	}
+	return &blog, nil
+}
@@ -0,0 +1,46 @@
+package blog_test
+
+import (
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/blog"
+	"github.com/dksensei/letsnormalizeit/internal/db/dbtest"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/repotest"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.TestBlogRepository(t, func(t *testing.T) model.BlogRepository {
+		return blog.NewMemoryRepository()
+	})
+}
+
+func TestRepository(t *testing.T) {
+	repotest.TestBlogRepository(t, func(t *testing.T) model.BlogRepository {
+		return blog.NewRepository(dbtest.NewMongoDB(t))
+	})
+}
+
+func TestMemoryRevisionRepository(t *testing.T) {
+	repotest.TestBlogRevisionRepository(t, func(t *testing.T) model.BlogRevisionRepository {
+		return blog.NewMemoryRevisionRepository()
+	})
+}
+
+func TestRevisionRepository(t *testing.T) {
+	repotest.TestBlogRevisionRepository(t, func(t *testing.T) model.BlogRevisionRepository {
+		return blog.NewRevisionRepository(dbtest.NewMongoDB(t))
+	})
+}
+
+func TestMemorySlugRepository(t *testing.T) {
+	repotest.TestBlogSlugRepository(t, func(t *testing.T) model.BlogSlugRepository {
+		return blog.NewMemorySlugRepository()
+	})
+}
+
+func TestSlugRepository(t *testing.T) {
+	repotest.TestBlogSlugRepository(t, func(t *testing.T) model.BlogSlugRepository {
+		return blog.NewSlugRepository(dbtest.NewMongoDB(t))
+	})
+}
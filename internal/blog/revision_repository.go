@@ -0,0 +1,92 @@
+package blog
+
+import (
+	"context"
+
+	"github.com/dksensei/letsnormalizeit/internal/db"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const revisionCollectionName = "blog_revisions"
+
+// RevisionRepository handles blog revision data operations
+type RevisionRepository struct {
+	db         *db.MongoDB
+	collection string
+}
+
+// Ensure RevisionRepository implements model.BlogRevisionRepository
+var _ model.BlogRevisionRepository = (*RevisionRepository)(nil)
+
+// NewRevisionRepository creates a new blog revision repository
+func NewRevisionRepository(mongodb *db.MongoDB) *RevisionRepository {
+	return &RevisionRepository{
+		db:         mongodb,
+		collection: revisionCollectionName,
+	}
+}
+
+// Create stores a revision and sets its generated ID
+func (r *RevisionRepository) Create(ctx context.Context, revision *model.BlogRevision) error {
+	coll := r.db.GetCollection(r.collection)
+
+	result, err := coll.InsertOne(ctx, revision)
+	if err != nil {
+		return err
+	}
+
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		revision.ID = id
+	}
+	return nil
+}
+
+// FindByVersion finds a single revision of a blog
+func (r *RevisionRepository) FindByVersion(ctx context.Context, blogID primitive.ObjectID, version int) (*model.BlogRevision, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	var revision model.BlogRevision
+	err := coll.FindOne(ctx, bson.M{"blog_id": blogID, "version": version}).Decode(&revision)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	return &revision, nil
+}
+
+// ListByBlog lists a blog's revisions, newest first, without their content
+func (r *RevisionRepository) ListByBlog(ctx context.Context, blogID primitive.ObjectID) ([]*model.BlogRevision, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"content": 0})
+
+	cursor, err := coll.Find(ctx, bson.M{"blog_id": blogID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []*model.BlogRevision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// DeleteByBlog deletes every revision of a blog
+func (r *RevisionRepository) DeleteByBlog(ctx context.Context, blogID primitive.ObjectID) error {
+	coll := r.db.GetCollection(r.collection)
+
+	_, err := coll.DeleteMany(ctx, bson.M{"blog_id": blogID})
+	return err
+}
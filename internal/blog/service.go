@@ -0,0 +1,828 @@
+package blog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/cache"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	minTitleLength   = 3
+	maxTitleLength   = 200
+	maxContentLength = 100000
+	maxTags          = 10
+	maxTagLength     = 30
+	popularLimit     = 10
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+	maxSlugAttempts  = 20
+)
+
+var (
+	// ErrBlogNotFound is returned when a blog does not exist
+	ErrBlogNotFound = apperror.NotFound("blog_not_found", "blog not found")
+
+	// ErrInvalidBlogID is returned when a blog ID is not a valid ObjectID
+	ErrInvalidBlogID = apperror.Validation("invalid_blog_id", "invalid blog ID format")
+
+	// ErrNotAuthor is returned when a user tries to modify a blog they did not write
+	ErrNotAuthor = apperror.Forbidden("not_author", "only the author or an editor can modify this blog")
+
+	// ErrUnpublishedAccess is returned when a user lists unpublished blogs of another author
+	ErrUnpublishedAccess = apperror.Forbidden("unpublished_access", "only the author or an admin can list unpublished blogs")
+
+	// ErrRevisionNotFound is returned when a blog revision does not exist
+	ErrRevisionNotFound = apperror.NotFound("revision_not_found", "revision not found")
+
+	// ErrVersionConflict is returned when a blog was changed by someone else during an edit
+	ErrVersionConflict = apperror.Conflict("version_conflict", "blog was modified concurrently, reload and retry")
+
+	// ErrDiffTooLarge is returned when two revisions differ in too many lines to diff
+	ErrDiffTooLarge = apperror.Unprocessable("diff_too_large", fmt.Sprintf("revisions differ in more than %d lines", utils.MaxDiffLines))
+
+	// ErrSlugTaken is returned when a slug already belongs to another blog
+	ErrSlugTaken = apperror.Conflict("slug_taken", "slug is already taken")
+
+	// ErrInvalidInput is wrapped by all blog validation errors
+	ErrInvalidInput = apperror.Validation("invalid_blog_input", "invalid blog input")
+
+	tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+)
+
+// DeleteHook cleans up data another service holds about a deleted blog
+type DeleteHook func(ctx context.Context, blogID primitive.ObjectID) error
+
+// Service handles blog-related business logic
+type Service struct {
+	repo        model.BlogRepository
+	revisions   model.BlogRevisionRepository
+	slugs       model.BlogSlugRepository
+	cache       *cache.Cache
+	deleteHooks []DeleteHook
+}
+
+// Ensure Service implements model.BlogService
+var _ model.BlogService = (*Service)(nil)
+
+// NewService creates a new blog service
+func NewService(repo model.BlogRepository, revisions model.BlogRevisionRepository, slugs model.BlogSlugRepository, cache *cache.Cache) *Service {
+	return &Service{
+		repo:      repo,
+		revisions: revisions,
+		slugs:     slugs,
+		cache:     cache,
+	}
+}
+
+// OnDelete registers a hook run after every blog deletion. Services that
+// depend on the blog service, such as comments and users, clean up through
+// hooks instead of the blog service depending on them. Hooks must be
+// registered before the service is used.
+func (s *Service) OnDelete(hook DeleteHook) {
+	s.deleteHooks = append(s.deleteHooks, hook)
+}
+
+// GetBlogByID gets a blog by ID, reading through the cache
+func (s *Service) GetBlogByID(ctx context.Context, id string) (*model.Blog, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidBlogID
+	}
+
+	if blog, ok := s.cache.GetBlog(ctx, objID.Hex()); ok {
+		return blog, nil
+	}
+
+	blog, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.SetBlog(ctx, blog)
+	return blog, nil
+}
+
+// ListBlogs lists a page of blogs matching the filter, reading through the
+// cache. Anything other than published blogs can only be listed by their
+// author or viewers allowed to see unpublished blogs.
+func (s *Service) ListBlogs(ctx context.Context, viewer model.Viewer, filter *model.BlogListFilter) (*model.BlogPage, error) {
+	if err := normalizeListFilter(filter); err != nil {
+		return nil, err
+	}
+	if filter.Status != model.BlogStatusPublished && !viewer.Can(model.PermissionViewUnpublished) && !viewer.IsAuthor(filter.AuthorID) {
+		return nil, ErrUnpublishedAccess
+	}
+
+	query := listQueryKey(filter)
+	if page, ok := s.cache.GetBlogList(ctx, query); ok {
+		return page, nil
+	}
+
+	page, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.SetBlogList(ctx, query, page)
+	return page, nil
+}
+
+// loadBlog gets a blog from the repository, bypassing the cache, so edits
+// start from the stored version rather than a possibly stale cached one
+func (s *Service) loadBlog(ctx context.Context, id string) (*model.Blog, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidBlogID
+	}
+	return s.repo.FindByID(ctx, objID)
+}
+
+// checkVersion rejects an edit based on another version than the stored one
+func checkVersion(blog *model.Blog, expected *int) error {
+	if expected != nil && *expected != blog.Version {
+		return fmt.Errorf("%w: blog is at version %d, not %d", ErrVersionConflict, blog.Version, *expected)
+	}
+	return nil
+}
+
+// GetVisibleBlog gets a blog if the viewer may read it. Drafts, scheduled
+// and archived blogs are reported as not found to everyone but their author
+// and viewers allowed to see unpublished blogs.
+func (s *Service) GetVisibleBlog(ctx context.Context, id string, viewer model.Viewer) (*model.Blog, error) {
+	blog, err := s.GetBlogByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !blog.Status.IsPublic() && !viewer.CanView(blog.AuthorID) {
+		return nil, ErrBlogNotFound
+	}
+	return blog, nil
+}
+
+// GetBlogBySlug gets a blog the viewer may read by its slug. Slugs a blog had
+// before its title was changed still resolve to it; callers compare the
+// requested slug with blog.Slug to redirect to the canonical one.
+func (s *Service) GetBlogBySlug(ctx context.Context, slug string, viewer model.Viewer) (*model.Blog, error) {
+	blogID, err := s.slugs.FindBlogID(ctx, utils.NormalizeSlug(slug))
+	if err != nil {
+		return nil, err
+	}
+	return s.GetVisibleBlog(ctx, blogID.Hex(), viewer)
+}
+
+// CountBlogsByAuthor counts the blogs written by an author in any status
+func (s *Service) CountBlogsByAuthor(ctx context.Context, authorID string) (int64, error) {
+	return s.repo.CountByAuthor(ctx, authorID)
+}
+
+// ListPopularBlogs lists the most liked published blogs, reading through the cache
+func (s *Service) ListPopularBlogs(ctx context.Context) ([]*model.Blog, error) {
+	if blogs, ok := s.cache.GetPopularBlogs(ctx); ok {
+		return blogs, nil
+	}
+
+	blogs, err := s.repo.ListPopular(ctx, popularLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.SetPopularBlogs(ctx, blogs)
+	return blogs, nil
+}
+
+// RenderBlog renders a blog's markdown to sanitized HTML, reusing any
+// rendering cached for identical content
+func (s *Service) RenderBlog(ctx context.Context, blog *model.Blog) (*model.RenderedContent, error) {
+	sum := sha256.Sum256([]byte(blog.Content))
+	hash := hex.EncodeToString(sum[:])
+
+	if rendered, ok := s.cache.GetRendered(ctx, hash); ok {
+		return rendered, nil
+	}
+
+	rendered, err := utils.RenderMarkdown(blog.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.SetRendered(ctx, hash, rendered)
+	return rendered, nil
+}
+
+// CreateBlog creates a new blog authored by the given user
+func (s *Service) CreateBlog(ctx context.Context, authorID string, input *model.BlogInput) (*model.Blog, error) {
+	logger := utils.FromContext(ctx).With("userID", authorID, "operation", "CreateBlog")
+
+	title, err := validateTitle(input.Title)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateContent(input.Content); err != nil {
+		return nil, err
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	imageURL, err := validateImageURL(input.ImageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	blog := model.NewBlog(title, input.Content, authorID, tags, imageURL)
+	status := input.Status
+	if status == "" {
+		status = model.BlogStatusPublished
+	}
+	if err := applyStatus(blog, status, input.PublishAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// The ID is needed up front to reserve the slug before the blog is visible
+	blog.ID = primitive.NewObjectID()
+	if err := s.assignSlug(ctx, blog); err != nil {
+		logger.Error("Failed to reserve blog slug: %v", err)
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, blog); err != nil {
+		logger.Error("Failed to create blog in database: %v", err)
+		if err := s.slugs.DeleteByBlog(ctx, blog.ID); err != nil {
+			logger.Error("Failed to release blog slug: %v", err)
+		}
+		return nil, err
+	}
+
+	// A blog without its first revision could never be restored to it
+	if err := s.revisions.Create(ctx, model.NewBlogRevision(blog, authorID, "Initial version")); err != nil {
+		logger.Error("Failed to save initial blog revision: %v", err)
+		if err := s.repo.Delete(ctx, blog.ID); err != nil {
+			logger.Error("Failed to delete blog without revision: %v", err)
+		}
+		if err := s.slugs.DeleteByBlog(ctx, blog.ID); err != nil {
+			logger.Error("Failed to release blog slug: %v", err)
+		}
+		return nil, err
+	}
+	s.cache.InvalidateBlogLists(ctx)
+
+	logger.With("blogID", blog.ID.Hex()).Info("Blog created successfully")
+	return blog, nil
+}
+
+// UpdateBlog updates a blog on behalf of its author, or of an editor allowed
+// to edit other authors' blogs. Changing the status of someone else's blog
+// additionally requires the publish permission.
+func (s *Service) UpdateBlog(ctx context.Context, id string, viewer model.Viewer, input *model.BlogUpdateInput) (*model.Blog, error) {
+	logger := utils.FromContext(ctx).With("userID", viewer.UserID, "blogID", id, "operation", "UpdateBlog")
+
+	blog, err := s.loadBlog(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.CanManage(blog.AuthorID) {
+		logger.Warn("User attempted to update a blog they may not edit")
+		return nil, ErrNotAuthor
+	}
+	if (input.Status != nil || input.PublishAt != nil) &&
+		!viewer.IsAuthor(blog.AuthorID) && !viewer.Can(model.PermissionPublishAnyBlog) {
+		logger.Warn("User attempted to change the status of a blog they may not publish")
+		return nil, ErrNotAuthor
+	}
+	if err := checkVersion(blog, input.Version); err != nil {
+		return nil, err
+	}
+
+	before := *blog
+	if input.Title != nil {
+		title, err := validateTitle(*input.Title)
+		if err != nil {
+			return nil, err
+		}
+		blog.Title = title
+	}
+	if input.Content != nil {
+		if err := validateContent(*input.Content); err != nil {
+			return nil, err
+		}
+		blog.Content = *input.Content
+	}
+	if input.Tags != nil {
+		tags, err := normalizeTags(*input.Tags)
+		if err != nil {
+			return nil, err
+		}
+		blog.Tags = tags
+	}
+	if input.ImageURL != nil {
+		imageURL, err := validateImageURL(*input.ImageURL)
+		if err != nil {
+			return nil, err
+		}
+		blog.ImageURL = imageURL
+	}
+	if input.Status != nil || input.PublishAt != nil {
+		status := blog.Status
+		if input.Status != nil {
+			status = *input.Status
+		}
+		if err := applyStatus(blog, status, input.PublishAt, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.refreshSlug(ctx, blog, before.Title); err != nil {
+		logger.Error("Failed to reserve blog slug: %v", err)
+		return nil, err
+	}
+
+	summary := strings.TrimSpace(input.ChangeSummary)
+	if summary == "" {
+		summary = describeChanges(&before, blog)
+	}
+	if err := s.saveVersion(ctx, blog, &before, viewer.UserID, summary); err != nil {
+		logger.Error("Failed to update blog in database: %v", err)
+		return nil, err
+	}
+
+	logger.Info("Blog updated successfully")
+	return blog, nil
+}
+
+// ListRevisions lists a blog's revisions, newest first. Only viewers who may
+// edit the blog can see the history.
+func (s *Service) ListRevisions(ctx context.Context, id string, viewer model.Viewer) ([]*model.BlogRevision, error) {
+	blog, err := s.GetBlogByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.CanManage(blog.AuthorID) {
+		return nil, ErrNotAuthor
+	}
+
+	return s.revisions.ListByBlog(ctx, blog.ID)
+}
+
+// DiffRevisions computes a line-level diff of the content of two revisions
+func (s *Service) DiffRevisions(ctx context.Context, id string, viewer model.Viewer, fromVersion, toVersion int) (*model.RevisionDiff, error) {
+	blog, err := s.GetBlogByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.CanManage(blog.AuthorID) {
+		return nil, ErrNotAuthor
+	}
+
+	from, err := s.revisions.FindByVersion(ctx, blog.ID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.revisions.FindByVersion(ctx, blog.ID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := utils.DiffLines(from.Content, to.Content)
+	if errors.Is(err, utils.ErrDiffTooLarge) {
+		return nil, ErrDiffTooLarge
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.RevisionDiff{
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		OldTitle:    from.Title,
+		NewTitle:    to.Title,
+		Lines:       lines,
+	}, nil
+}
+
+// RestoreRevision restores an older revision of a blog the viewer may edit,
+// provided the blog is still at expectedVersion when that is set. The
+// restored content is saved as a new version so history is never rewritten.
+func (s *Service) RestoreRevision(ctx context.Context, id string, viewer model.Viewer, version int, expectedVersion *int) (*model.Blog, error) {
+	logger := utils.FromContext(ctx).With("userID", viewer.UserID, "blogID", id, "operation", "RestoreRevision")
+
+	blog, err := s.loadBlog(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !viewer.CanManage(blog.AuthorID) {
+		logger.Warn("User attempted to restore a blog they may not edit")
+		return nil, ErrNotAuthor
+	}
+	if err := checkVersion(blog, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.revisions.FindByVersion(ctx, blog.ID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *blog
+	blog.Title = revision.Title
+	blog.Content = revision.Content
+	blog.Tags = revision.Tags
+	blog.ImageURL = revision.ImageURL
+
+	if err := s.refreshSlug(ctx, blog, before.Title); err != nil {
+		logger.Error("Failed to reserve blog slug: %v", err)
+		return nil, err
+	}
+
+	if err := s.saveVersion(ctx, blog, &before, viewer.UserID, fmt.Sprintf("Restored version %d", version)); err != nil {
+		logger.Error("Failed to restore blog revision: %v", err)
+		return nil, err
+	}
+
+	logger.With("version", version).Info("Blog revision restored successfully")
+	return blog, nil
+}
+
+// refreshSlug gives a blog a new slug when its title changed enough to change
+// the slug. The previous slug stays reserved for the blog so old links keep
+// resolving.
+func (s *Service) refreshSlug(ctx context.Context, blog *model.Blog, oldTitle string) error {
+	if blog.Slug != "" && utils.Slugify(blog.Title) == utils.Slugify(oldTitle) {
+		return nil
+	}
+	return s.assignSlug(ctx, blog)
+}
+
+// assignSlug reserves the first free slug derived from the blog's title,
+// appending -2, -3, ... when taken and finally the blog's own ID, which can
+// never collide
+func (s *Service) assignSlug(ctx context.Context, blog *model.Blog) error {
+	base := utils.Slugify(blog.Title)
+
+	for attempt := 1; attempt <= maxSlugAttempts; attempt++ {
+		candidate := base
+		if attempt > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, attempt)
+		}
+
+		err := s.slugs.Reserve(ctx, candidate, blog.ID)
+		if err == nil {
+			blog.Slug = candidate
+			return nil
+		}
+		if !errors.Is(err, ErrSlugTaken) {
+			return err
+		}
+	}
+
+	candidate := base + "-" + blog.ID.Hex()
+	if err := s.slugs.Reserve(ctx, candidate, blog.ID); err != nil {
+		return err
+	}
+	blog.Slug = candidate
+	return nil
+}
+
+// saveVersion writes an edited blog as the next version and records it in
+// the revision history. On a version conflict the cached blog is dropped too,
+// so clients reloading after the conflict see the version that won. If the
+// revision cannot be recorded, the blog is put back as it was before the
+// edit, unless another edit got in first, so the history never has gaps.
+func (s *Service) saveVersion(ctx context.Context, blog, before *model.Blog, editorID, summary string) error {
+	expected := blog.Version
+	blog.Version = expected + 1
+	if expected == 0 {
+		// Blogs created before versioning start their history at version 1
+		blog.Version = 1
+	}
+
+	if err := s.repo.Update(ctx, blog, expected); err != nil {
+		if errors.Is(err, ErrVersionConflict) {
+			s.cache.InvalidateBlog(ctx, blog.ID.Hex())
+		}
+		return err
+	}
+	s.cache.InvalidateBlog(ctx, blog.ID.Hex())
+
+	if err := s.revisions.Create(ctx, model.NewBlogRevision(blog, editorID, summary)); err != nil {
+		logger := utils.FromContext(ctx).With("blogID", blog.ID.Hex(), "version", blog.Version)
+		logger.Error("Failed to save blog revision: %v", err)
+		if err := s.repo.Update(ctx, before, blog.Version); err != nil {
+			logger.Error("Failed to undo blog update without revision: %v", err)
+		}
+		s.cache.InvalidateBlog(ctx, blog.ID.Hex())
+		return err
+	}
+	return nil
+}
+
+// DeleteBlog deletes a blog on behalf of its author, or of a viewer allowed
+// to delete any blog
+func (s *Service) DeleteBlog(ctx context.Context, id string, viewer model.Viewer) error {
+	logger := utils.FromContext(ctx).With("userID", viewer.UserID, "blogID", id, "operation", "DeleteBlog")
+
+	blog, err := s.loadBlog(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !viewer.IsAuthor(blog.AuthorID) && !viewer.Can(model.PermissionDeleteAnyBlog) {
+		logger.Warn("User attempted to delete a blog they may not delete")
+		return ErrNotAuthor
+	}
+
+	if err := s.repo.Delete(ctx, blog.ID); err != nil {
+		logger.Error("Failed to delete blog from database: %v", err)
+		return err
+	}
+	s.cache.InvalidateBlog(ctx, blog.ID.Hex())
+	s.cache.InvalidateComments(ctx, blog.ID.Hex())
+
+	if err := s.revisions.DeleteByBlog(ctx, blog.ID); err != nil {
+		logger.Error("Failed to delete blog revisions: %v", err)
+	}
+	if err := s.slugs.DeleteByBlog(ctx, blog.ID); err != nil {
+		logger.Error("Failed to release blog slugs: %v", err)
+	}
+	for _, hook := range s.deleteHooks {
+		if err := hook(ctx, blog.ID); err != nil {
+			logger.Error("Failed to clean up after blog deletion: %v", err)
+		}
+	}
+
+	logger.Info("Blog deleted successfully")
+	return nil
+}
+
+// ToggleLike atomically flips the user's like on a blog
+func (s *Service) ToggleLike(ctx context.Context, id, userID string) (*model.Blog, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidBlogID
+	}
+
+	blog, err := s.repo.ToggleLike(ctx, objID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.InvalidateBlogEntry(ctx, objID.Hex())
+	return blog, nil
+}
+
+// ToggleBookmark atomically flips the user's bookmark on a blog
+func (s *Service) ToggleBookmark(ctx context.Context, id, userID string) (*model.Blog, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidBlogID
+	}
+
+	blog, err := s.repo.ToggleBookmark(ctx, objID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.InvalidateBlogEntry(ctx, objID.Hex())
+	return blog, nil
+}
+
+// SetLike adds or removes the user's like on a blog
+func (s *Service) SetLike(ctx context.Context, id, userID string, liked bool) (*model.Blog, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidBlogID
+	}
+
+	blog, err := s.repo.SetLike(ctx, objID, userID, liked)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.InvalidateBlogEntry(ctx, objID.Hex())
+	return blog, nil
+}
+
+// SetBookmark adds or removes the user's bookmark on a blog
+func (s *Service) SetBookmark(ctx context.Context, id, userID string, bookmarked bool) (*model.Blog, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidBlogID
+	}
+
+	blog, err := s.repo.SetBookmark(ctx, objID, userID, bookmarked)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.InvalidateBlogEntry(ctx, objID.Hex())
+	return blog, nil
+}
+
+// IncrementCommentCount adjusts a blog's comment_count by delta
+func (s *Service) IncrementCommentCount(ctx context.Context, id string, delta int64) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidBlogID
+	}
+
+	if err := s.repo.IncrementCommentCount(ctx, objID, delta); err != nil {
+		return err
+	}
+
+	s.cache.InvalidateBlogEntry(ctx, objID.Hex())
+	return nil
+}
+
+// PublishDueBlogs publishes scheduled blogs whose publish time has passed
+func (s *Service) PublishDueBlogs(ctx context.Context) (int, error) {
+	published, err := s.repo.PublishDue(ctx, time.Now())
+	for _, id := range published {
+		s.cache.InvalidateBlog(ctx, id.Hex())
+	}
+	return len(published), err
+}
+
+// StartScheduler periodically publishes due scheduled blogs until ctx is done
+func (s *Service) StartScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				count, err := s.PublishDueBlogs(ctx)
+				if err != nil {
+					utils.FromContext(ctx).Error("Blog scheduler failed to publish due blogs: %v", err)
+				}
+				if count > 0 {
+					utils.FromContext(ctx).Info("Blog scheduler published %d scheduled blogs", count)
+				}
+			}
+		}
+	}()
+}
+
+// describeChanges summarizes which fields an edit touched
+func describeChanges(before, after *model.Blog) string {
+	var changed []string
+	if before.Title != after.Title {
+		changed = append(changed, "title")
+	}
+	if before.Content != after.Content {
+		changed = append(changed, "content")
+	}
+	if strings.Join(before.Tags, ",") != strings.Join(after.Tags, ",") {
+		changed = append(changed, "tags")
+	}
+	if before.ImageURL != after.ImageURL {
+		changed = append(changed, "image")
+	}
+	if before.Status != after.Status {
+		changed = append(changed, "status")
+	}
+	if len(changed) == 0 {
+		return "No changes"
+	}
+	return "Updated " + strings.Join(changed, ", ")
+}
+
+// applyStatus moves a blog to a new lifecycle status. Scheduling requires a
+// future publish time, publishing stamps the first publication time and
+// returning to draft clears it.
+func applyStatus(blog *model.Blog, status model.BlogStatus, publishAt *time.Time, now time.Time) error {
+	if !status.Valid() {
+		return fmt.Errorf("%w: status must be one of draft, scheduled, published, unlisted or archived", ErrInvalidInput)
+	}
+
+	switch status {
+	case model.BlogStatusScheduled:
+		if publishAt == nil {
+			publishAt = blog.PublishAt
+		}
+		if publishAt == nil || !publishAt.After(now) {
+			return fmt.Errorf("%w: scheduled blogs need a publish_at in the future", ErrInvalidInput)
+		}
+		at := publishAt.UTC()
+		blog.PublishAt = &at
+	case model.BlogStatusPublished:
+		if blog.Status != model.BlogStatusPublished || blog.PublishAt == nil {
+			at := now.UTC()
+			blog.PublishAt = &at
+		}
+	case model.BlogStatusDraft:
+		blog.PublishAt = nil
+	default:
+		if publishAt != nil {
+			return fmt.Errorf("%w: publish_at can only be set on scheduled blogs", ErrInvalidInput)
+		}
+	}
+
+	blog.Status = status
+	return nil
+}
+
+// normalizeListFilter applies defaults to a listing filter and validates it
+func normalizeListFilter(filter *model.BlogListFilter) error {
+	switch filter.Sort {
+	case "":
+		filter.Sort = model.BlogSortNewest
+	case model.BlogSortNewest, model.BlogSortMostLiked, model.BlogSortMostCommented:
+	default:
+		return fmt.Errorf("%w: sort must be one of newest, most_liked or most_commented", ErrInvalidInput)
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxPageLimit {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, maxPageLimit)
+	}
+
+	if filter.Status != "" && !filter.Status.Valid() {
+		return fmt.Errorf("%w: status must be one of draft, scheduled, published, unlisted or archived", ErrInvalidInput)
+	}
+
+	filter.Tag = strings.ToLower(strings.TrimSpace(filter.Tag))
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidInput)
+	}
+	return nil
+}
+
+// listQueryKey identifies a listing in the cache
+func listQueryKey(filter *model.BlogListFilter) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%d|%d|%d|%s",
+		filter.Sort, filter.Tag, filter.AuthorID, filter.Status,
+		filter.From.UnixMilli(), filter.To.UnixMilli(), filter.Limit, filter.Cursor)
+}
+
+// validateTitle trims the title and checks its length
+func validateTitle(title string) (string, error) {
+	title = strings.TrimSpace(title)
+	length := utf8.RuneCountInString(title)
+	if length < minTitleLength || length > maxTitleLength {
+		return "", fmt.Errorf("%w: title must be between %d and %d characters", ErrInvalidInput, minTitleLength, maxTitleLength)
+	}
+	return title, nil
+}
+
+// validateContent checks that the markdown content is present and not too large
+func validateContent(content string) error {
+	if strings.TrimSpace(content) == "" {
+		return fmt.Errorf("%w: content is required", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(content) > maxContentLength {
+		return fmt.Errorf("%w: content must be at most %d characters", ErrInvalidInput, maxContentLength)
+	}
+	return nil
+}
+
+// normalizeTags lowercases, trims and de-duplicates tags, rejecting malformed ones
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool)
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength || !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("%w: tag %q must be lowercase letters, digits or dashes and at most %d characters", ErrInvalidInput, tag, maxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > maxTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidInput, maxTags)
+	}
+	return normalized, nil
+}
+
+// validateImageURL checks that a non-empty image URL is an absolute http(s) URL
+func validateImageURL(imageURL string) (string, error) {
+	imageURL = strings.TrimSpace(imageURL)
+	if imageURL == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(imageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%w: image_url must be an absolute http(s) URL", ErrInvalidInput)
+	}
+	return imageURL, nil
+}
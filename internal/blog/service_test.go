@@ -0,0 +1,79 @@
+package blog_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/blog"
+	"github.com/dksensei/letsnormalizeit/internal/cache"
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+)
+
+var errWriteFailed = errors.New("write failed")
+
+// failingRevisionRepository is a revision repository whose writes fail
+// while failing is set
+type failingRevisionRepository struct {
+	*blog.MemoryRevisionRepository
+	failing bool
+}
+
+func (r *failingRevisionRepository) Create(ctx context.Context, revision *model.BlogRevision) error {
+	if r.failing {
+		return errWriteFailed
+	}
+	return r.MemoryRevisionRepository.Create(ctx, revision)
+}
+
+func TestRevisionFailureLeavesNoVersionGap(t *testing.T) {
+	ctx := context.Background()
+	blogs := blog.NewMemoryRepository()
+	revisions := &failingRevisionRepository{MemoryRevisionRepository: blog.NewMemoryRevisionRepository()}
+	appCache := cache.New(cache.NewMemoryStore(), &config.RedisConfig{})
+	service := blog.NewService(blogs, revisions, blog.NewMemorySlugRepository(), appCache)
+	author := model.Viewer{UserID: "author", Roles: []model.Role{model.RoleAuthor}}
+
+	// A blog whose first revision cannot be saved is not created at all
+	revisions.failing = true
+	if _, err := service.CreateBlog(ctx, "author", &model.BlogInput{Title: "Lost", Content: "Content"}); !errors.Is(err, errWriteFailed) {
+		t.Fatalf("CreateBlog error = %v, want %v", err, errWriteFailed)
+	}
+	if count, err := blogs.CountByAuthor(ctx, "author"); err != nil || count != 0 {
+		t.Fatalf("stored blogs = %d, %v, want none", count, err)
+	}
+
+	revisions.failing = false
+	created, err := service.CreateBlog(ctx, "author", &model.BlogInput{Title: "Kept", Content: "Content"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An edit whose revision cannot be saved is undone
+	revisions.failing = true
+	content := "Unrecorded"
+	if _, err := service.UpdateBlog(ctx, created.ID.Hex(), author, &model.BlogUpdateInput{Content: &content}); !errors.Is(err, errWriteFailed) {
+		t.Fatalf("UpdateBlog error = %v, want %v", err, errWriteFailed)
+	}
+	stored, err := service.GetBlogByID(ctx, created.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Version != 1 || stored.Content != "Content" {
+		t.Errorf("blog = version %d %q, want version 1 %q", stored.Version, stored.Content, "Content")
+	}
+
+	// The next edit carries on from the last recorded version
+	revisions.failing = false
+	if _, err := service.UpdateBlog(ctx, created.ID.Hex(), author, &model.BlogUpdateInput{Content: &content}); err != nil {
+		t.Fatal(err)
+	}
+	history, err := service.ListRevisions(ctx, created.ID.Hex(), author)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Version != 2 {
+		t.Errorf("revisions = %d, newest version %d, want 2 revisions up to version 2", len(history), history[0].Version)
+	}
+}
@@ -0,0 +1,87 @@
+package blog
+
+import (
+	"context"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/db"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const slugCollectionName = "blog_slugs"
+
+// slugRecord maps a slug, current or historical, to the blog it belongs to.
+// The slug is the document ID so uniqueness is enforced by MongoDB itself.
+type slugRecord struct {
+	Slug      string             `bson:"_id"`
+	BlogID    primitive.ObjectID `bson:"blog_id"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+// SlugRepository handles the registry of blog slugs
+type SlugRepository struct {
+	db         *db.MongoDB
+	collection string
+}
+
+// Ensure SlugRepository implements model.BlogSlugRepository
+var _ model.BlogSlugRepository = (*SlugRepository)(nil)
+
+// NewSlugRepository creates a new blog slug repository
+func NewSlugRepository(mongodb *db.MongoDB) *SlugRepository {
+	return &SlugRepository{
+		db:         mongodb,
+		collection: slugCollectionName,
+	}
+}
+
+// Reserve claims a slug for a blog. A slug the blog already owned, e.g. from
+// before a rename, can be claimed again; a slug owned by any other blog
+// returns ErrSlugTaken.
+func (r *SlugRepository) Reserve(ctx context.Context, slug string, blogID primitive.ObjectID) error {
+	coll := r.db.GetCollection(r.collection)
+
+	_, err := coll.InsertOne(ctx, slugRecord{Slug: slug, BlogID: blogID, CreatedAt: time.Now()})
+	if err == nil {
+		return nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	owner, err := r.FindBlogID(ctx, slug)
+	if err != nil {
+		return err
+	}
+	if owner != blogID {
+		return ErrSlugTaken
+	}
+	return nil
+}
+
+// FindBlogID finds the blog a slug belongs to
+func (r *SlugRepository) FindBlogID(ctx context.Context, slug string) (primitive.ObjectID, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	var record slugRecord
+	err := coll.FindOne(ctx, bson.M{"_id": slug}).Decode(&record)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return primitive.NilObjectID, ErrBlogNotFound
+		}
+		return primitive.NilObjectID, err
+	}
+
+	return record.BlogID, nil
+}
+
+// DeleteByBlog releases every slug of a blog
+func (r *SlugRepository) DeleteByBlog(ctx context.Context, blogID primitive.ObjectID) error {
+	coll := r.db.GetCollection(r.collection)
+
+	_, err := coll.DeleteMany(ctx, bson.M{"blog_id": blogID})
+	return err
+}
@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/metrics"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+)
+
+// Cache keys, following the scheme in arch.md
+const (
+	blogKeyPrefix    = "blog:"
+	commentKeyPrefix = "comment:"
+	renderKeyPrefix  = "markdown:"
+	blogListKey      = "blogs:list"
+	popularBlogsKey  = "blogs:popular"
+)
+
+// ErrMiss is returned by a Store when a key or hash field is absent
+var ErrMiss = errors.New("cache miss")
+
+// Store is a key-value backend for the cache
+type Store interface {
+	// Get gets the value of a key
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set sets the value of a key with a TTL
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// HGet gets a field of a hash
+	HGet(ctx context.Context, key, field string) ([]byte, error)
+
+	// HSet sets a field of a hash. The TTL only applies when the hash is
+	// created, so the whole hash expires that long after its first field was
+	// set.
+	HSet(ctx context.Context, key, field string, value []byte, ttl time.Duration) error
+
+	// Delete deletes keys
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Cache is a read-through cache for blogs, blog lists and comment threads.
+// Backend failures are logged and treated as misses so that a cache outage
+// never fails a request.
+type Cache struct {
+	store  Store
+	config *config.RedisConfig
+}
+
+// New creates a new cache on top of a store
+func New(store Store, cfg *config.RedisConfig) *Cache {
+	return &Cache{
+		store:  store,
+		config: cfg,
+	}
+}
+
+// BlogKey returns the cache key of a single blog
+func BlogKey(id string) string {
+	return blogKeyPrefix + id
+}
+
+// CommentKey returns the cache key of a blog's comment threads
+func CommentKey(blogID string) string {
+	return commentKeyPrefix + blogID
+}
+
+// GetBlog gets a cached blog
+func (c *Cache) GetBlog(ctx context.Context, id string) (*model.Blog, bool) {
+	var blog model.Blog
+	if !c.get(ctx, BlogKey(id), &blog) {
+		return nil, false
+	}
+	return &blog, true
+}
+
+// SetBlog caches a blog
+func (c *Cache) SetBlog(ctx context.Context, blog *model.Blog) {
+	c.set(ctx, BlogKey(blog.ID.Hex()), blog, c.config.BlogTTL)
+}
+
+// GetBlogList gets a cached page of blogs identified by its query
+func (c *Cache) GetBlogList(ctx context.Context, query string) (*model.BlogPage, bool) {
+	var page model.BlogPage
+	if !c.hget(ctx, blogListKey, query, &page) {
+		return nil, false
+	}
+	return &page, true
+}
+
+// SetBlogList caches a page of blogs identified by its query
+func (c *Cache) SetBlogList(ctx context.Context, query string, page *model.BlogPage) {
+	c.hset(ctx, blogListKey, query, page, c.config.ListTTL)
+}
+
+// GetPopularBlogs gets the cached popular blogs
+func (c *Cache) GetPopularBlogs(ctx context.Context) ([]*model.Blog, bool) {
+	var blogs []*model.Blog
+	if !c.get(ctx, popularBlogsKey, &blogs) {
+		return nil, false
+	}
+	return blogs, true
+}
+
+// SetPopularBlogs caches the popular blogs
+func (c *Cache) SetPopularBlogs(ctx context.Context, blogs []*model.Blog) {
+	c.set(ctx, popularBlogsKey, blogs, c.config.PopularTTL)
+}
+
+// GetComments gets a cached comment tree identified by its query
+func (c *Cache) GetComments(ctx context.Context, blogID, query string) (*model.CommentTree, bool) {
+	var tree model.CommentTree
+	if !c.hget(ctx, CommentKey(blogID), query, &tree) {
+		return nil, false
+	}
+	return &tree, true
+}
+
+// SetComments caches a comment tree identified by its query
+func (c *Cache) SetComments(ctx context.Context, blogID, query string, tree *model.CommentTree) {
+	c.hset(ctx, CommentKey(blogID), query, tree, c.config.CommentTTL)
+}
+
+// GetRendered gets cached rendered markdown by content hash
+func (c *Cache) GetRendered(ctx context.Context, hash string) (*model.RenderedContent, bool) {
+	var rendered model.RenderedContent
+	if !c.get(ctx, renderKeyPrefix+hash, &rendered) {
+		return nil, false
+	}
+	return &rendered, true
+}
+
+// SetRendered caches rendered markdown by content hash. Entries never need
+// invalidation since changed content hashes to a different key.
+func (c *Cache) SetRendered(ctx context.Context, hash string, rendered *model.RenderedContent) {
+	c.set(ctx, renderKeyPrefix+hash, rendered, c.config.RenderTTL)
+}
+
+// InvalidateBlog drops everything that may contain a blog: the blog itself,
+// every cached listing and the popular posts. It is meant for changes that
+// decide which listings the blog appears in or how, such as edits, status
+// changes and deletion.
+func (c *Cache) InvalidateBlog(ctx context.Context, id string) {
+	c.delete(ctx, BlogKey(id), blogListKey, popularBlogsKey)
+}
+
+// InvalidateBlogEntry drops only the cached blog itself. Likes, bookmarks and
+// comments use it, so that busy blogs do not keep emptying the shared
+// listings; the counts in listings catch up when those expire.
+func (c *Cache) InvalidateBlogEntry(ctx context.Context, id string) {
+	c.delete(ctx, BlogKey(id))
+}
+
+// InvalidateComments drops a blog's cached comment threads
+func (c *Cache) InvalidateComments(ctx context.Context, blogID string) {
+	c.delete(ctx, CommentKey(blogID))
+}
+
+// InvalidateBlogLists drops every cached listing
+func (c *Cache) InvalidateBlogLists(ctx context.Context) {
+	c.delete(ctx, blogListKey)
+}
+
+func (c *Cache) get(ctx context.Context, key string, dest interface{}) bool {
+	data, err := c.store.Get(ctx, key)
+	return c.decode(ctx, key, data, err, dest)
+}
+
+func (c *Cache) hget(ctx context.Context, key, field string, dest interface{}) bool {
+	data, err := c.store.HGet(ctx, key, field)
+	return c.decode(ctx, key, data, err, dest)
+}
+
+func (c *Cache) decode(ctx context.Context, key string, data []byte, err error, dest interface{}) bool {
+	name := metricName(key)
+	if err != nil {
+		if !errors.Is(err, ErrMiss) {
+			utils.FromContext(ctx).Warn("Cache read failed for %s: %v", key, err)
+			metrics.ObserveCache(name, metrics.CacheError)
+			return false
+		}
+		metrics.ObserveCache(name, metrics.CacheMiss)
+		return false
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		utils.FromContext(ctx).Warn("Cache entry %s could not be decoded: %v", key, err)
+		metrics.ObserveCache(name, metrics.CacheError)
+		return false
+	}
+	metrics.ObserveCache(name, metrics.CacheHit)
+	return true
+}
+
+// metricName is the cache label of a key in metrics, which leaves out IDs
+// and hashes to keep the number of series small
+func metricName(key string) string {
+	switch {
+	case key == blogListKey:
+		return "blog_list"
+	case key == popularBlogsKey:
+		return "popular_blogs"
+	case strings.HasPrefix(key, blogKeyPrefix):
+		return "blog"
+	case strings.HasPrefix(key, commentKeyPrefix):
+		return "comments"
+	case strings.HasPrefix(key, renderKeyPrefix):
+		return "markdown"
+	default:
+		return "other"
+	}
+}
+
+func (c *Cache) set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		utils.FromContext(ctx).Warn("Cache entry %s could not be encoded: %v", key, err)
+		return
+	}
+	if err := c.store.Set(ctx, key, data, ttl); err != nil {
+		utils.FromContext(ctx).Warn("Cache write failed for %s: %v", key, err)
+	}
+}
+
+func (c *Cache) hset(ctx context.Context, key, field string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		utils.FromContext(ctx).Warn("Cache entry %s could not be encoded: %v", key, err)
+		return
+	}
+	if err := c.store.HSet(ctx, key, field, data, ttl); err != nil {
+		utils.FromContext(ctx).Warn("Cache write failed for %s: %v", key, err)
+	}
+}
+
+func (c *Cache) delete(ctx context.Context, keys ...string) {
+	if err := c.store.Delete(ctx, keys...); err != nil {
+		utils.FromContext(ctx).Warn("Cache invalidation failed for %v: %v", keys, err)
+	}
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestInvalidate(t *testing.T) {
+	ctx := context.Background()
+	blog := &model.Blog{ID: primitive.NewObjectID(), Title: "Cached"}
+	id := blog.ID.Hex()
+
+	cases := []struct {
+		name       string
+		invalidate func(c *Cache)
+		wantLists  bool
+	}{
+		{
+			name:       "Blog",
+			invalidate: func(c *Cache) { c.InvalidateBlog(ctx, id) },
+			wantLists:  false,
+		},
+		{
+			name:       "BlogEntry",
+			invalidate: func(c *Cache) { c.InvalidateBlogEntry(ctx, id) },
+			wantLists:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(NewMemoryStore(), &config.RedisConfig{BlogTTL: time.Minute, ListTTL: time.Minute, PopularTTL: time.Minute})
+			c.SetBlog(ctx, blog)
+			c.SetBlogList(ctx, "page", &model.BlogPage{Blogs: []*model.Blog{blog}})
+			c.SetPopularBlogs(ctx, []*model.Blog{blog})
+
+			tc.invalidate(c)
+
+			if _, ok := c.GetBlog(ctx, id); ok {
+				t.Error("blog still cached")
+			}
+			if _, ok := c.GetBlogList(ctx, "page"); ok != tc.wantLists {
+				t.Errorf("blog list cached = %v, want %v", ok, tc.wantLists)
+			}
+			if _, ok := c.GetPopularBlogs(ctx); ok != tc.wantLists {
+				t.Errorf("popular blogs cached = %v, want %v", ok, tc.wantLists)
+			}
+		})
+	}
+}
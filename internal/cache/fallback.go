@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// FallbackStore uses a primary store, such as Redis, while it is available
+// and a fallback store while it is not. Deletes also reach the fallback, so
+// it never serves entries this instance invalidated while the primary was
+// up. A nil fallback misses every read and drops every write.
+//
+// Entries the primary holds from before an outage are served again once it
+// is back, so staleness after an outage is bounded by the cache TTLs.
+type FallbackStore struct {
+	primary   Store
+	fallback  Store
+	available func() bool
+}
+
+// Ensure FallbackStore implements Store
+var _ Store = (*FallbackStore)(nil)
+
+// NewFallbackStore creates a store that uses primary while available reports
+// true and fallback otherwise
+func NewFallbackStore(primary, fallback Store, available func() bool) *FallbackStore {
+	return &FallbackStore{
+		primary:   primary,
+		fallback:  fallback,
+		available: available,
+	}
+}
+
+// Get gets the value of a key
+func (s *FallbackStore) Get(ctx context.Context, key string) ([]byte, error) {
+	store := s.store()
+	if store == nil {
+		return nil, ErrMiss
+	}
+	return store.Get(ctx, key)
+}
+
+// Set sets the value of a key with a TTL
+func (s *FallbackStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	store := s.store()
+	if store == nil {
+		return nil
+	}
+	return store.Set(ctx, key, value, ttl)
+}
+
+// HGet gets a field of a hash
+func (s *FallbackStore) HGet(ctx context.Context, key, field string) ([]byte, error) {
+	store := s.store()
+	if store == nil {
+		return nil, ErrMiss
+	}
+	return store.HGet(ctx, key, field)
+}
+
+// HSet sets a field of a hash. The TTL only applies when the hash is
+// created, so the whole hash expires that long after its first field was set.
+func (s *FallbackStore) HSet(ctx context.Context, key, field string, value []byte, ttl time.Duration) error {
+	store := s.store()
+	if store == nil {
+		return nil
+	}
+	return store.HSet(ctx, key, field, value, ttl)
+}
+
+// Delete deletes keys from the fallback and, while available, the primary
+func (s *FallbackStore) Delete(ctx context.Context, keys ...string) error {
+	if s.fallback != nil {
+		if err := s.fallback.Delete(ctx, keys...); err != nil {
+			return err
+		}
+	}
+	if s.available() {
+		return s.primary.Delete(ctx, keys...)
+	}
+	return nil
+}
+
+// store returns the store to use right now
+func (s *FallbackStore) store() Store {
+	if s.available() {
+		return s.primary
+	}
+	return s.fallback
+}
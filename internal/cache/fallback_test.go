@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFallbackStore(t *testing.T) {
+	ctx := context.Background()
+	primary, fallback := NewMemoryStore(), NewMemoryStore()
+	available := true
+	store := NewFallbackStore(primary, fallback, func() bool { return available })
+
+	// lookup reports what store holds for key, "" for a miss
+	lookup := func(s Store, key string) string {
+		value, err := s.Get(ctx, key)
+		if err != nil {
+			if !errors.Is(err, ErrMiss) {
+				t.Fatalf("Get(%s): %v", key, err)
+			}
+			return ""
+		}
+		return string(value)
+	}
+
+	steps := []struct {
+		name      string
+		available bool
+		run       func()
+		// want maps keys to the values expected through store, primary and fallback
+		want map[string][3]string
+	}{
+		{
+			name:      "WritesToPrimary",
+			available: true,
+			run:       func() { store.Set(ctx, "a", []byte("primary"), time.Minute) },
+			want:      map[string][3]string{"a": {"primary", "primary", ""}},
+		},
+		{
+			name:      "SwitchesToFallback",
+			available: false,
+			run:       func() { store.Set(ctx, "b", []byte("fallback"), time.Minute) },
+			want: map[string][3]string{
+				"a": {"", "primary", ""},
+				"b": {"fallback", "", "fallback"},
+			},
+		},
+		{
+			name:      "SwitchesBack",
+			available: true,
+			run:       func() {},
+			want: map[string][3]string{
+				"a": {"primary", "primary", ""},
+				"b": {"", "", "fallback"},
+			},
+		},
+		{
+			name:      "DeletesReachBoth",
+			available: true,
+			run:       func() { store.Delete(ctx, "a", "b") },
+			want: map[string][3]string{
+				"a": {"", "", ""},
+				"b": {"", "", ""},
+			},
+		},
+		{
+			name:      "DeletesSkipUnavailablePrimary",
+			available: false,
+			run: func() {
+				primary.Set(ctx, "c", []byte("primary"), time.Minute)
+				fallback.Set(ctx, "c", []byte("fallback"), time.Minute)
+				store.Delete(ctx, "c")
+			},
+			want: map[string][3]string{"c": {"", "primary", ""}},
+		},
+	}
+
+	for _, step := range steps {
+		available = step.available
+		step.run()
+		for key, want := range step.want {
+			got := [3]string{lookup(store, key), lookup(primary, key), lookup(fallback, key)}
+			if got != want {
+				t.Errorf("%s: %s through store, primary and fallback = %q, want %q", step.name, key, got, want)
+			}
+		}
+	}
+}
+
+func TestFallbackStoreWithoutFallback(t *testing.T) {
+	ctx := context.Background()
+	store := NewFallbackStore(NewMemoryStore(), nil, func() bool { return false })
+
+	if err := store.Set(ctx, "a", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.HSet(ctx, "h", "f", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("HSet: %v", err)
+	}
+	if _, err := store.Get(ctx, "a"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get = %v, want ErrMiss", err)
+	}
+	if _, err := store.HGet(ctx, "h", "f"); !errors.Is(err, ErrMiss) {
+		t.Errorf("HGet = %v, want ErrMiss", err)
+	}
+	if err := store.Delete(ctx, "a"); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store kept in process. It stands in for Redis when Redis
+// is disabled or unreachable, so its entries are per instance.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+// memoryEntry is a value or a hash of values with an expiry
+type memoryEntry struct {
+	value     []byte
+	fields    map[string][]byte
+	expiresAt time.Time
+}
+
+// Ensure MemoryStore implements Store
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a new in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+	}
+}
+
+// Get gets the value of a key
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entry(key, time.Now())
+	if entry == nil || entry.fields != nil {
+		return nil, ErrMiss
+	}
+	return entry.value, nil
+}
+
+// Set sets the value of a key with a TTL
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &memoryEntry{value: value, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// HGet gets a field of a hash
+func (s *MemoryStore) HGet(ctx context.Context, key, field string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entry(key, time.Now())
+	if entry == nil {
+		return nil, ErrMiss
+	}
+	value, ok := entry.fields[field]
+	if !ok {
+		return nil, ErrMiss
+	}
+	return value, nil
+}
+
+// HSet sets a field of a hash. The TTL only applies when the hash is
+// created, so the whole hash expires that long after its first field was set.
+func (s *MemoryStore) HSet(ctx context.Context, key, field string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry := s.entry(key, now)
+	if entry == nil || entry.fields == nil {
+		entry = &memoryEntry{fields: make(map[string][]byte), expiresAt: now.Add(ttl)}
+		s.entries[key] = entry
+	}
+	entry.fields[field] = value
+	return nil
+}
+
+// Delete deletes keys
+func (s *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// Cleanup periodically drops expired entries, until ctx is done
+func (s *MemoryStore) Cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.mu.Lock()
+				for key, entry := range s.entries {
+					if !now.Before(entry.expiresAt) {
+						delete(s.entries, key)
+					}
+				}
+				s.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// entry returns the unexpired entry of a key, dropping an expired one;
+// s.mu must be held
+func (s *MemoryStore) entry(key string, now time.Time) *memoryEntry {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	if !now.Before(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil
+	}
+	return entry
+}
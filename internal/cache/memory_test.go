@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		// run prepares the store and returns the lookup to check
+		run      func(s *MemoryStore) ([]byte, error)
+		want     string
+		wantMiss bool
+	}{
+		{
+			name: "Get",
+			run: func(s *MemoryStore) ([]byte, error) {
+				s.Set(ctx, "key", []byte("value"), time.Minute)
+				return s.Get(ctx, "key")
+			},
+			want: "value",
+		},
+		{
+			name:     "Missing",
+			run:      func(s *MemoryStore) ([]byte, error) { return s.Get(ctx, "key") },
+			wantMiss: true,
+		},
+		{
+			name: "Expired",
+			run: func(s *MemoryStore) ([]byte, error) {
+				s.Set(ctx, "key", []byte("value"), time.Millisecond)
+				time.Sleep(2 * time.Millisecond)
+				return s.Get(ctx, "key")
+			},
+			wantMiss: true,
+		},
+		{
+			name: "Deleted",
+			run: func(s *MemoryStore) ([]byte, error) {
+				s.Set(ctx, "key", []byte("value"), time.Minute)
+				s.Set(ctx, "other", []byte("value"), time.Minute)
+				s.Delete(ctx, "key", "other")
+				return s.Get(ctx, "other")
+			},
+			wantMiss: true,
+		},
+		{
+			name: "HGet",
+			run: func(s *MemoryStore) ([]byte, error) {
+				s.HSet(ctx, "hash", "a", []byte("first"), time.Minute)
+				s.HSet(ctx, "hash", "b", []byte("second"), time.Minute)
+				return s.HGet(ctx, "hash", "a")
+			},
+			want: "first",
+		},
+		{
+			name: "MissingField",
+			run: func(s *MemoryStore) ([]byte, error) {
+				s.HSet(ctx, "hash", "a", []byte("first"), time.Minute)
+				return s.HGet(ctx, "hash", "b")
+			},
+			wantMiss: true,
+		},
+		{
+			name: "HSetKeepsHashExpiry",
+			run: func(s *MemoryStore) ([]byte, error) {
+				s.HSet(ctx, "hash", "a", []byte("first"), time.Millisecond)
+				s.HSet(ctx, "hash", "b", []byte("second"), time.Minute)
+				time.Sleep(2 * time.Millisecond)
+				return s.HGet(ctx, "hash", "b")
+			},
+			wantMiss: true,
+		},
+		{
+			name: "ExpiredHash",
+			run: func(s *MemoryStore) ([]byte, error) {
+				s.HSet(ctx, "hash", "a", []byte("first"), time.Millisecond)
+				time.Sleep(2 * time.Millisecond)
+				return s.HGet(ctx, "hash", "a")
+			},
+			wantMiss: true,
+		},
+		{
+			name: "GetOfHash",
+			run: func(s *MemoryStore) ([]byte, error) {
+				s.HSet(ctx, "hash", "a", []byte("first"), time.Minute)
+				return s.Get(ctx, "hash")
+			},
+			wantMiss: true,
+		},
+		{
+			name: "HSetReplacesValue",
+			run: func(s *MemoryStore) ([]byte, error) {
+				s.Set(ctx, "key", []byte("value"), time.Minute)
+				s.HSet(ctx, "key", "a", []byte("first"), time.Minute)
+				return s.HGet(ctx, "key", "a")
+			},
+			want: "first",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := tc.run(NewMemoryStore())
+			if tc.wantMiss {
+				if !errors.Is(err, ErrMiss) {
+					t.Fatalf("got %q, %v, want ErrMiss", value, err)
+				}
+				return
+			}
+			if err != nil || string(value) != tc.want {
+				t.Fatalf("got %q, %v, want %q", value, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreCleanup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewMemoryStore()
+	s.Set(ctx, "short", []byte("value"), time.Millisecond)
+	s.Set(ctx, "long", []byte("value"), time.Hour)
+	s.Cleanup(ctx, 5*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mu.Lock()
+		_, short := s.entries["short"]
+		_, long := s.entries["long"]
+		s.mu.Unlock()
+
+		if !long {
+			t.Fatal("unexpired entry was dropped")
+		}
+		if !short {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired entry was not dropped")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
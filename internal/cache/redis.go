@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/db"
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisStore is a Store backed by Redis
+type RedisStore struct {
+	client *redis.Client
+}
+
+// Ensure RedisStore implements Store
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates a new Redis-backed store
+func NewRedisStore(r *db.Redis) *RedisStore {
+	return &RedisStore{
+		client: r.Client,
+	}
+}
+
+// Get gets the value of a key
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, ErrMiss
+	}
+	return data, err
+}
+
+// Set sets the value of a key with a TTL
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+// HGet gets a field of a hash
+func (s *RedisStore) HGet(ctx context.Context, key, field string) ([]byte, error) {
+	data, err := s.client.HGet(ctx, key, field).Bytes()
+	if err == redis.Nil {
+		return nil, ErrMiss
+	}
+	return data, err
+}
+
+// HSet sets a field of a hash. The TTL only applies when the hash is
+// created, so the whole hash expires that long after its first field was set.
+func (s *RedisStore) HSet(ctx context.Context, key, field string, value []byte, ttl time.Duration) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, field, value)
+		pipe.ExpireNX(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+// Delete deletes keys
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	return s.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,146 @@
+package comment
+
+import (
+	"net/http"
+
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests related to comments
+type Handler struct {
+	commentService *Service
+}
+
+// NewHandler creates a new comment handler
+func NewHandler(commentService *Service) *Handler {
+	return &Handler{
+		commentService: commentService,
+	}
+}
+
+// CommentResponse represents a comment and its loaded replies
+type CommentResponse struct {
+	ID             string             `json:"id"`
+	BlogID         string             `json:"blog_id"`
+	UserID         string             `json:"user_id"`
+	ParentID       string             `json:"parent_id,omitempty"`
+	Content        string             `json:"content"`
+	LikeCount      int                `json:"like_count"`
+	CreatedAt      string             `json:"created_at"`
+	UpdatedAt      string             `json:"updated_at"`
+	ReplyCount     int64              `json:"reply_count"`
+	HasMoreReplies bool               `json:"has_more_replies"`
+	Replies        []*CommentResponse `json:"replies"`
+}
+
+// newCommentResponse converts a comment model into its API representation
+func newCommentResponse(comment *model.Comment) *CommentResponse {
+	response := &CommentResponse{
+		ID:        comment.ID.Hex(),
+		BlogID:    comment.BlogID.Hex(),
+		UserID:    comment.UserID,
+		Content:   comment.Content,
+		LikeCount: len(comment.Likes),
+		CreatedAt: comment.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: comment.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Replies:   []*CommentResponse{},
+	}
+	if !comment.ParentID.IsZero() {
+		response.ParentID = comment.ParentID.Hex()
+	}
+	return response
+}
+
+// newNodeResponse converts a comment tree node and its replies recursively
+func newNodeResponse(node *model.CommentNode) *CommentResponse {
+	response := newCommentResponse(node.Comment)
+	response.ReplyCount = node.ReplyCount
+	response.HasMoreReplies = node.HasMoreReplies
+	for _, reply := range node.Replies {
+		response.Replies = append(response.Replies, newNodeResponse(reply))
+	}
+	return response
+}
+
+// CreateComment handles creating a comment or reply for the authenticated user
+func (h *Handler) CreateComment(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "CreateComment")
+
+	var input model.CommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		apperror.Respond(c, apperror.InvalidRequest(err))
+		return
+	}
+
+	// Get the user ID from the context (set by auth middleware)
+	uid, exists := c.Get("uid")
+	if !exists {
+		logger.Error("User ID not found in context - authentication middleware may have failed")
+		apperror.Respond(c, apperror.ErrUnauthenticated)
+		return
+	}
+
+	userID := uid.(string)
+	comment, err := h.commentService.CreateComment(c.Request.Context(), middleware.GetViewer(c), &input)
+	if err != nil {
+		logger.With("userID", userID, "blogID", input.BlogID).Warn("Failed to create comment: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCommentResponse(comment))
+}
+
+// ListComments handles fetching a blog's comments as a nested tree.
+// Query parameters: limit/offset paginate the root level, replies_limit caps
+// replies per comment, depth caps nesting and parent_id roots the tree at a
+// specific comment to load a deeper or further page of a thread.
+func (h *Handler) ListComments(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "ListComments")
+
+	opts := model.CommentTreeOptions{
+		ParentID: c.Query("parent_id"),
+	}
+	var err error
+	if opts.Limit, err = utils.ParseQueryInt(c, "limit"); err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "limit must be an integer"))
+		return
+	}
+	if opts.Offset, err = utils.ParseQueryInt(c, "offset"); err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "offset must be an integer"))
+		return
+	}
+	if opts.RepliesLimit, err = utils.ParseQueryInt(c, "replies_limit"); err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "replies_limit must be an integer"))
+		return
+	}
+	depth, err := utils.ParseQueryInt(c, "depth")
+	if err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "depth must be an integer"))
+		return
+	}
+	opts.Depth = int(depth)
+
+	blogID := c.Param("id")
+	tree, err := h.commentService.GetCommentTree(c.Request.Context(), blogID, middleware.GetViewer(c), opts)
+	if err != nil {
+		logger.With("blogID", blogID).Warn("Failed to get comments: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	comments := make([]*CommentResponse, 0, len(tree.Comments))
+	for _, node := range tree.Comments {
+		comments = append(comments, newNodeResponse(node))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"total":    tree.Total,
+	})
+}
@@ -0,0 +1,168 @@
+package comment
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository is a thread-safe in-memory implementation of
+// model.CommentRepository, used by tests and local development without
+// MongoDB. Comments are copied on the way in and out, so callers never share
+// state with the store.
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	comments map[primitive.ObjectID]*model.Comment
+}
+
+// Ensure MemoryRepository implements model.CommentRepository
+var _ model.CommentRepository = (*MemoryRepository)(nil)
+
+// NewMemoryRepository creates an empty in-memory comment repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		comments: make(map[primitive.ObjectID]*model.Comment),
+	}
+}
+
+// FindByID finds a comment by ID
+func (r *MemoryRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comment, ok := r.comments[id]
+	if !ok {
+		return nil, ErrCommentNotFound
+	}
+	return cloneComment(comment), nil
+}
+
+// Create creates a new comment and sets its generated ID
+func (r *MemoryRepository) Create(ctx context.Context, comment *model.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if comment.ID.IsZero() {
+		comment.ID = primitive.NewObjectID()
+	}
+	r.comments[comment.ID] = cloneComment(comment)
+	return nil
+}
+
+// FindRoots finds a page of top-level comments on a blog, oldest first
+func (r *MemoryRepository) FindRoots(ctx context.Context, blogID primitive.ObjectID, limit, offset int64) ([]*model.Comment, int64, error) {
+	comments := r.find(func(comment *model.Comment) bool {
+		return comment.BlogID == blogID && comment.ParentID.IsZero()
+	}, oldestFirst)
+	return paginate(comments, limit, offset), int64(len(comments)), nil
+}
+
+// FindReplies finds a page of direct replies to a comment, oldest first
+func (r *MemoryRepository) FindReplies(ctx context.Context, parentID primitive.ObjectID, limit, offset int64) ([]*model.Comment, int64, error) {
+	comments := r.find(func(comment *model.Comment) bool {
+		return comment.ParentID == parentID
+	}, oldestFirst)
+	return paginate(comments, limit, offset), int64(len(comments)), nil
+}
+
+// FindByParents finds the oldest limit direct replies to each of the given
+// comments, oldest first
+func (r *MemoryRepository) FindByParents(ctx context.Context, parentIDs []primitive.ObjectID, limit int64) ([]*model.Comment, error) {
+	replies := r.find(func(comment *model.Comment) bool {
+		return !comment.ParentID.IsZero() && slices.Contains(parentIDs, comment.ParentID)
+	}, oldestFirst)
+
+	kept := make(map[primitive.ObjectID]int64, len(parentIDs))
+	comments := make([]*model.Comment, 0, len(replies))
+	for _, reply := range replies {
+		if kept[reply.ParentID] < limit {
+			kept[reply.ParentID]++
+			comments = append(comments, reply)
+		}
+	}
+	return comments, nil
+}
+
+// CountByParents counts the direct replies to each of the given comments
+func (r *MemoryRepository) CountByParents(ctx context.Context, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[primitive.ObjectID]int64)
+	for _, comment := range r.comments {
+		if !comment.ParentID.IsZero() && slices.Contains(parentIDs, comment.ParentID) {
+			counts[comment.ParentID]++
+		}
+	}
+	return counts, nil
+}
+
+// FindByUser finds a user's most recent comments along with the total number
+// of comments they wrote
+func (r *MemoryRepository) FindByUser(ctx context.Context, userID string, limit int64) ([]*model.Comment, int64, error) {
+	comments := r.find(func(comment *model.Comment) bool {
+		return comment.UserID == userID
+	}, func(a, b *model.Comment) int {
+		return oldestFirst(b, a)
+	})
+	return paginate(comments, limit, 0), int64(len(comments)), nil
+}
+
+// DeleteByBlog deletes every comment on a blog
+func (r *MemoryRepository) DeleteByBlog(ctx context.Context, blogID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, comment := range r.comments {
+		if comment.BlogID == blogID {
+			delete(r.comments, id)
+		}
+	}
+	return nil
+}
+
+// find copies every comment matching match, sorted by compare
+func (r *MemoryRepository) find(match func(comment *model.Comment) bool, compare func(a, b *model.Comment) int) []*model.Comment {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comments := []*model.Comment{}
+	for _, comment := range r.comments {
+		if match(comment) {
+			comments = append(comments, cloneComment(comment))
+		}
+	}
+	slices.SortFunc(comments, compare)
+	return comments
+}
+
+// oldestFirst orders comments by creation time, then by ID
+func oldestFirst(a, b *model.Comment) int {
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+// paginate skips offset comments and keeps at most limit, where a limit of
+// zero keeps all of them like MongoDB does
+func paginate(comments []*model.Comment, limit, offset int64) []*model.Comment {
+	total := int64(len(comments))
+	start := min(max(offset, 0), total)
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+	return comments[start:end]
+}
+
+// cloneComment returns a deep copy of a comment
+func cloneComment(comment *model.Comment) *model.Comment {
+	clone := *comment
+	clone.Likes = slices.Clone(comment.Likes)
+	return &clone
+}
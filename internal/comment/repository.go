@@ -0,0 +1,223 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/dksensei/letsnormalizeit/internal/db"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const collectionName = "comments"
+
+// Repository handles comment data operations
+type Repository struct {
+	db         *db.MongoDB
+	collection string
+}
+
+// Ensure Repository implements model.CommentRepository
+var _ model.CommentRepository = (*Repository)(nil)
+
+// NewRepository creates a new comment repository
+func NewRepository(mongodb *db.MongoDB) *Repository {
+	return &Repository{
+		db:         mongodb,
+		collection: collectionName,
+	}
+}
+
+// FindByID finds a comment by ID
+func (r *Repository) FindByID(ctx context.Context, id primitive.ObjectID) (*model.Comment, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	var comment model.Comment
+	err := coll.FindOne(ctx, bson.M{"_id": id}).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// Create creates a new comment and sets its generated ID
+func (r *Repository) Create(ctx context.Context, comment *model.Comment) error {
+	coll := r.db.GetCollection(r.collection)
+
+	result, err := coll.InsertOne(ctx, comment)
+	if err != nil {
+		return err
+	}
+
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		comment.ID = id
+	}
+	return nil
+}
+
+// FindRoots finds a page of top-level comments on a blog, oldest first
+func (r *Repository) FindRoots(ctx context.Context, blogID primitive.ObjectID, limit, offset int64) ([]*model.Comment, int64, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	filter := bson.M{"blog_id": blogID, "parent_id": bson.M{"$exists": false}}
+	total, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit).
+		SetSkip(offset)
+
+	comments, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// FindReplies finds a page of direct replies to a comment, oldest first
+func (r *Repository) FindReplies(ctx context.Context, parentID primitive.ObjectID, limit, offset int64) ([]*model.Comment, int64, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	filter := bson.M{"parent_id": parentID}
+	total, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit).
+		SetSkip(offset)
+
+	comments, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// FindByParents finds the oldest limit direct replies to each of the given
+// comments, oldest first. Each parent looks up its own replies through the
+// parent_id index and stops after limit of them, so no more than limit
+// replies per parent are ever read, however busy the thread.
+func (r *Repository) FindByParents(ctx context.Context, parentIDs []primitive.ObjectID, limit int64) ([]*model.Comment, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	oldestFirst := bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+	replies := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$expr": bson.M{"$eq": bson.A{"$parent_id", "$$parent"}}}}},
+		{{Key: "$sort", Value: oldestFirst}},
+		{{Key: "$limit", Value: limit}},
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": bson.M{"$in": parentIDs}}}},
+		{{Key: "$project", Value: bson.M{"_id": 1}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":     r.collection,
+			"let":      bson.M{"parent": "$_id"},
+			"pipeline": replies,
+			"as":       "replies",
+		}}},
+		{{Key: "$unwind", Value: "$replies"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$replies"}}},
+		{{Key: "$sort", Value: oldestFirst}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var comments []*model.Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// CountByParents counts the direct replies to each of the given comments
+func (r *Repository) CountByParents(ctx context.Context, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]int64, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"parent_id": bson.M{"$in": parentIDs}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$parent_id", "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ParentID primitive.ObjectID `bson:"_id"`
+		Count    int64              `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[primitive.ObjectID]int64, len(results))
+	for _, result := range results {
+		counts[result.ParentID] = result.Count
+	}
+	return counts, nil
+}
+
+// FindByUser finds a user's most recent comments along with the total number
+// of comments they wrote
+func (r *Repository) FindByUser(ctx context.Context, userID string, limit int64) ([]*model.Comment, int64, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	filter := bson.M{"user_id": userID}
+	total, err := coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+
+	comments, err := r.find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// DeleteByBlog deletes every comment on a blog
+func (r *Repository) DeleteByBlog(ctx context.Context, blogID primitive.ObjectID) error {
+	coll := r.db.GetCollection(r.collection)
+
+	_, err := coll.DeleteMany(ctx, bson.M{"blog_id": blogID})
+	return err
+}
+
+// find runs a query and decodes every matching comment
+func (r *Repository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*model.Comment, error) {
+	coll := r.db.GetCollection(r.collection)
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	comments := []*model.Comment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
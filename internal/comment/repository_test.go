@@ -0,0 +1,22 @@
+package comment_test
+
+import (
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/comment"
+	"github.com/dksensei/letsnormalizeit/internal/db/dbtest"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/repotest"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.TestCommentRepository(t, func(t *testing.T) model.CommentRepository {
+		return comment.NewMemoryRepository()
+	})
+}
+
+func TestRepository(t *testing.T) {
+	repotest.TestCommentRepository(t, func(t *testing.T) model.CommentRepository {
+		return comment.NewRepository(dbtest.NewMongoDB(t))
+	})
+}
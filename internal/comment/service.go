@@ -0,0 +1,268 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/cache"
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxContentLength  = 5000
+	defaultRootLimit  = 20
+	maxRootLimit      = 100
+	maxRepliesPerNode = 50
+)
+
+var (
+	// ErrCommentNotFound is returned when a comment does not exist
+	ErrCommentNotFound = apperror.NotFound("comment_not_found", "comment not found")
+
+	// ErrInvalidCommentID is returned when a comment ID is not a valid ObjectID
+	ErrInvalidCommentID = apperror.Validation("invalid_comment_id", "invalid comment ID format")
+
+	// ErrParentMismatch is returned when a parent comment belongs to a different blog
+	ErrParentMismatch = apperror.Validation("parent_mismatch", "parent comment belongs to a different blog")
+
+	// ErrInvalidInput is wrapped by all comment validation errors
+	ErrInvalidInput = apperror.Validation("invalid_comment_input", "invalid comment input")
+)
+
+// Service handles comment-related business logic
+type Service struct {
+	repo        model.CommentRepository
+	blogService model.BlogService
+	cache       *cache.Cache
+	config      *config.CommentConfig
+}
+
+// Ensure Service implements model.CommentService
+var _ model.CommentService = (*Service)(nil)
+
+// NewService creates a new comment service
+func NewService(repo model.CommentRepository, blogService model.BlogService, cache *cache.Cache, cfg *config.CommentConfig) *Service {
+	return &Service{
+		repo:        repo,
+		blogService: blogService,
+		cache:       cache,
+		config:      cfg,
+	}
+}
+
+// CreateComment creates a comment or reply authored by the viewer. Blogs the
+// viewer may not read are reported as not found, as when reading them.
+func (s *Service) CreateComment(ctx context.Context, viewer model.Viewer, input *model.CommentInput) (*model.Comment, error) {
+	logger := utils.FromContext(ctx).With("userID", viewer.UserID, "blogID", input.BlogID, "operation", "CreateComment")
+
+	content := strings.TrimSpace(input.Content)
+	if content == "" {
+		return nil, fmt.Errorf("%w: content is required", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(content) > maxContentLength {
+		return nil, fmt.Errorf("%w: content must be at most %d characters", ErrInvalidInput, maxContentLength)
+	}
+
+	blog, err := s.blogService.GetVisibleBlog(ctx, input.BlogID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentID primitive.ObjectID
+	if input.ParentID != "" {
+		parent, err := s.getComment(ctx, input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.BlogID != blog.ID {
+			logger.Warn("Reply parent %s belongs to blog %s", parent.ID.Hex(), parent.BlogID.Hex())
+			return nil, ErrParentMismatch
+		}
+		parentID = parent.ID
+	}
+
+	comment := model.NewComment(blog.ID, viewer.UserID, content, parentID)
+	if err := s.repo.Create(ctx, comment); err != nil {
+		logger.Error("Failed to create comment in database: %v", err)
+		return nil, err
+	}
+	s.cache.InvalidateComments(ctx, blog.ID.Hex())
+	if err := s.blogService.IncrementCommentCount(ctx, blog.ID.Hex(), 1); err != nil {
+		logger.Error("Failed to increment blog comment count: %v", err)
+	}
+
+	logger.With("commentID", comment.ID.Hex()).Info("Comment created successfully")
+	return comment, nil
+}
+
+// GetCommentTree gets a blog's comments as a nested tree. Root comments are
+// paginated with Limit/Offset and every node carries at most RepliesLimit
+// replies, down to Depth levels (capped at the configured maximum). Only
+// comments of blogs the viewer may read are listed. Trees are read through
+// the cache and dropped whenever a comment is added to the blog.
+func (s *Service) GetCommentTree(ctx context.Context, blogID string, viewer model.Viewer, opts model.CommentTreeOptions) (*model.CommentTree, error) {
+	blog, err := s.blogService.GetVisibleBlog(ctx, blogID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = s.normalizeOptions(opts)
+	query := fmt.Sprintf("%s:%d:%d:%d:%d", opts.ParentID, opts.Depth, opts.Limit, opts.Offset, opts.RepliesLimit)
+	if tree, ok := s.cache.GetComments(ctx, blog.ID.Hex(), query); ok {
+		return tree, nil
+	}
+
+	tree, err := s.buildTree(ctx, blog, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.SetComments(ctx, blog.ID.Hex(), query, tree)
+	return tree, nil
+}
+
+// ListUserComments lists a user's most recent comments along with the total
+// number of comments they wrote
+func (s *Service) ListUserComments(ctx context.Context, userID string, limit int64) ([]*model.Comment, int64, error) {
+	if limit <= 0 || limit > maxRootLimit {
+		limit = defaultRootLimit
+	}
+	return s.repo.FindByUser(ctx, userID, limit)
+}
+
+// DeleteBlogComments deletes every comment on a deleted blog. It is
+// registered as a blog.DeleteHook.
+func (s *Service) DeleteBlogComments(ctx context.Context, blogID primitive.ObjectID) error {
+	if err := s.repo.DeleteByBlog(ctx, blogID); err != nil {
+		return err
+	}
+	s.cache.InvalidateComments(ctx, blogID.Hex())
+	return nil
+}
+
+// buildTree loads a page of root comments and their replies level by level
+func (s *Service) buildTree(ctx context.Context, blog *model.Blog, opts model.CommentTreeOptions) (*model.CommentTree, error) {
+	var (
+		roots []*model.Comment
+		total int64
+		err   error
+	)
+	if opts.ParentID != "" {
+		parent, err := s.getComment(ctx, opts.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.BlogID != blog.ID {
+			return nil, ErrParentMismatch
+		}
+		roots, total, err = s.repo.FindReplies(ctx, parent.ID, opts.Limit, opts.Offset)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		roots, total, err = s.repo.FindRoots(ctx, blog.ID, opts.Limit, opts.Offset)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nodes := newNodes(roots)
+	level := nodes
+	for depth := 1; len(level) > 0; depth++ {
+		ids := make([]primitive.ObjectID, 0, len(level))
+		for _, node := range level {
+			ids = append(ids, node.Comment.ID)
+		}
+
+		counts, err := s.repo.CountByParents(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		// At the deepest level only report how many replies are hidden
+		if depth >= opts.Depth {
+			for _, node := range level {
+				node.ReplyCount = counts[node.Comment.ID]
+				node.HasMoreReplies = node.ReplyCount > 0
+			}
+			break
+		}
+
+		replies, err := s.repo.FindByParents(ctx, ids, opts.RepliesLimit)
+		if err != nil {
+			return nil, err
+		}
+		byParent := make(map[primitive.ObjectID][]*model.Comment)
+		for _, reply := range replies {
+			byParent[reply.ParentID] = append(byParent[reply.ParentID], reply)
+		}
+
+		var next []*model.CommentNode
+		for _, node := range level {
+			children := byParent[node.Comment.ID]
+			node.ReplyCount = counts[node.Comment.ID]
+			node.HasMoreReplies = node.ReplyCount > int64(len(children))
+			node.Replies = newNodes(children)
+			next = append(next, node.Replies...)
+		}
+		level = next
+	}
+
+	return &model.CommentTree{
+		Comments: nodes,
+		Total:    total,
+	}, nil
+}
+
+// normalizeOptions applies defaults and server-side limits to tree options
+func (s *Service) normalizeOptions(opts model.CommentTreeOptions) model.CommentTreeOptions {
+	if opts.Depth <= 0 || opts.Depth > s.config.MaxDepth {
+		opts.Depth = s.config.MaxDepth
+	}
+	if opts.Depth <= 0 {
+		opts.Depth = 1
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultRootLimit
+	}
+	if opts.Limit > maxRootLimit {
+		opts.Limit = maxRootLimit
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+	if opts.RepliesLimit <= 0 {
+		opts.RepliesLimit = s.config.RepliesPerLevel
+	}
+	if opts.RepliesLimit > maxRepliesPerNode {
+		opts.RepliesLimit = maxRepliesPerNode
+	}
+	return opts
+}
+
+// getComment parses a comment ID and loads the comment
+func (s *Service) getComment(ctx context.Context, id string) (*model.Comment, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidCommentID
+	}
+	return s.repo.FindByID(ctx, objID)
+}
+
+// newNodes wraps comments in tree nodes with no replies loaded yet
+func newNodes(comments []*model.Comment) []*model.CommentNode {
+	nodes := make([]*model.CommentNode, 0, len(comments))
+	for _, comment := range comments {
+		nodes = append(nodes, &model.CommentNode{
+			Comment: comment,
+			Replies: []*model.CommentNode{},
+		})
+	}
+	return nodes
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/dksensei/letsnormalizeit/internal/utils"
 	"github.com/joho/godotenv"
@@ -13,16 +14,67 @@ import (
 // Config holds the application configuration
 type Config struct {
 	Server   ServerConfig   `mapstructure:"server"`
+	Auth     AuthConfig     `mapstructure:"auth"`
 	Firebase FirebaseConfig `mapstructure:"firebase"`
 	MongoDB  MongoDBConfig  `mapstructure:"mongodb"`
 	Redis    RedisConfig    `mapstructure:"redis"`
+	Startup  StartupConfig  `mapstructure:"startup"`
 	Logger   LoggerConfig   `mapstructure:"logger"`
+	Blogs    BlogConfig     `mapstructure:"blogs"`
+	Comments CommentConfig  `mapstructure:"comments"`
+	// RateLimit is named ratelimit so it maps to LNI_RATELIMIT_* variables
+	RateLimit RateLimitConfig `mapstructure:"ratelimit"`
+	Metrics   MetricsConfig   `mapstructure:"metrics"`
+	Tracing   TracingConfig   `mapstructure:"tracing"`
 }
 
 // ServerConfig holds server-specific configuration
 type ServerConfig struct {
 	Port         string `mapstructure:"port"`
 	AllowOrigins string `mapstructure:"allow_origins"`
+	// TrustedProxies are the proxy IPs or CIDRs whose X-Forwarded-For and
+	// X-Real-IP headers are believed when resolving the client IP. None are
+	// trusted by default, so the client IP is the connection's peer address.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// TrustedPlatform is a header set by the hosting platform with the client
+	// IP, e.g. CF-Connecting-IP behind Cloudflare. It is believed for every
+	// request, so only set it when the platform overwrites the header.
+	TrustedPlatform string `mapstructure:"trusted_platform"`
+	// HealthCheckTimeout bounds each dependency check of /readyz
+	HealthCheckTimeout time.Duration `mapstructure:"health_check_timeout"`
+	// ShutdownDrain is how long /readyz reports not ready before the server
+	// stops accepting connections, so load balancers stop routing to it
+	ShutdownDrain time.Duration `mapstructure:"shutdown_drain"`
+}
+
+// Auth providers
+const (
+	AuthProviderFirebase = "firebase"
+	AuthProviderLocal    = "local"
+)
+
+// AuthConfig selects how ID tokens are verified. The local provider checks
+// RS256/HS256 JWTs against a shared secret and/or a JSON Web Key Set file and
+// needs no network access.
+type AuthConfig struct {
+	Provider    string `mapstructure:"provider"`
+	JWTSecret   string `mapstructure:"jwt_secret"`
+	JWTKeysFile string `mapstructure:"jwt_keys_file"`
+	JWTIssuer   string `mapstructure:"jwt_issuer"`
+	JWTAudience string `mapstructure:"jwt_audience"`
+
+	// TokenCacheSize is how many verified tokens are kept in memory, 0 to
+	// disable the in-memory cache. Without either cache tier every token is
+	// checked for revocation.
+	TokenCacheSize int `mapstructure:"token_cache_size"`
+	// TokenCacheTTL caps how long a verified token is cached; tokens are
+	// never cached past their expiry
+	TokenCacheTTL time.Duration `mapstructure:"token_cache_ttl"`
+	// TokenCacheRedis also shares verified tokens between instances in Redis
+	TokenCacheRedis bool `mapstructure:"token_cache_redis"`
+	// RevocationCheckInterval is how often a cached token is verified again,
+	// which is when revoked tokens and disabled users are noticed
+	RevocationCheckInterval time.Duration `mapstructure:"revocation_check_interval"`
 }
 
 // FirebaseConfig holds Firebase-specific configuration
@@ -35,13 +87,110 @@ type FirebaseConfig struct {
 type MongoDBConfig struct {
 	URI      string `mapstructure:"uri"`
 	Database string `mapstructure:"database"`
+	// AutoMigrate applies pending schema migrations at startup; otherwise
+	// they are only reported and must be applied with the migrate command
+	AutoMigrate bool `mapstructure:"auto_migrate"`
 }
 
-// RedisConfig holds Redis-specific configuration
+// RedisConfig holds Redis-specific configuration. Redis only backs caches
+// and the rate limiter, so the server can run on in-memory fallbacks with
+// Enabled unset.
 type RedisConfig struct {
-	Address  string `mapstructure:"address"`
-	Password string `mapstructure:"password"`
-	DB       int    `mapstructure:"db"`
+	Enabled    bool          `mapstructure:"enabled"`
+	Address    string        `mapstructure:"address"`
+	Password   string        `mapstructure:"password"`
+	DB         int           `mapstructure:"db"`
+	BlogTTL    time.Duration `mapstructure:"blog_ttl"`
+	ListTTL    time.Duration `mapstructure:"list_ttl"`
+	PopularTTL time.Duration `mapstructure:"popular_ttl"`
+	CommentTTL time.Duration `mapstructure:"comment_ttl"`
+	RenderTTL  time.Duration `mapstructure:"render_ttl"`
+	// ReconnectInterval is how often Redis is pinged to detect it going
+	// away or coming back
+	ReconnectInterval time.Duration `mapstructure:"reconnect_interval"`
+}
+
+// StartupConfig controls how dependencies are connected at startup. Each is
+// tried RetryAttempts times, doubling the backoff from RetryInitialBackoff up
+// to RetryMaxBackoff between attempts.
+type StartupConfig struct {
+	RetryAttempts       int           `mapstructure:"retry_attempts"`
+	RetryInitialBackoff time.Duration `mapstructure:"retry_initial_backoff"`
+	RetryMaxBackoff     time.Duration `mapstructure:"retry_max_backoff"`
+}
+
+// BlogConfig holds blog lifecycle configuration
+type BlogConfig struct {
+	SchedulerInterval time.Duration `mapstructure:"scheduler_interval"`
+}
+
+// CommentConfig holds comment thread configuration
+type CommentConfig struct {
+	MaxDepth        int   `mapstructure:"max_depth"`
+	RepliesPerLevel int64 `mapstructure:"replies_per_level"`
+}
+
+// Rate limiter backends
+const (
+	RateLimitBackendRedis  = "redis"
+	RateLimitBackendMemory = "memory"
+)
+
+// RateLimitConfig holds the rate limiter backend and its policies
+type RateLimitConfig struct {
+	// Backend is redis (shared by all replicas) or memory (per process)
+	Backend string `mapstructure:"backend"`
+	// Anonymous applies to requests without a valid token
+	Anonymous RateLimitPolicyConfig `mapstructure:"anonymous"`
+	// Authenticated applies to signed in users on public and protected routes
+	Authenticated RateLimitPolicyConfig `mapstructure:"authenticated"`
+	// Auth applies to the login and registration routes
+	Auth  RateLimitPolicyConfig `mapstructure:"auth"`
+	Admin RateLimitPolicyConfig `mapstructure:"admin"`
+	// PreAuth applies to protected and admin routes before the token is
+	// verified, so floods of invalid tokens are limited too
+	PreAuth RateLimitPolicyConfig `mapstructure:"preauth"`
+}
+
+// RateLimitPolicyConfig allows Requests per Window, counted by the first
+// available identity of the comma separated Identity list (uid, ip)
+type RateLimitPolicyConfig struct {
+	Requests int           `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
+	Identity string        `mapstructure:"identity"`
+}
+
+// MetricsConfig controls the Prometheus metrics endpoint
+type MetricsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Path is the route serving the metrics
+	Path string `mapstructure:"path"`
+}
+
+// Tracing exporters
+const (
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+	TracingExporterFile   = "file"
+)
+
+// TracingConfig controls OpenTelemetry tracing. The stdout and file
+// exporters write spans as JSON and need no collector.
+type TracingConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Exporter is otlp (OTLP over HTTP), stdout or file
+	Exporter    string `mapstructure:"exporter"`
+	ServiceName string `mapstructure:"service_name"`
+	// SampleRatio is the fraction of new traces recorded; requests
+	// continuing a caller's trace follow its sampling decision
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+	// OTLPEndpoint is the host:port of the OTLP/HTTP collector
+	OTLPEndpoint string `mapstructure:"otlp_endpoint"`
+	OTLPInsecure bool   `mapstructure:"otlp_insecure"`
+	// File is where the file exporter appends spans
+	File string `mapstructure:"file"`
+	// ExcludePaths are never traced, e.g. health checks
+	ExcludePaths []string `mapstructure:"exclude_paths"`
 }
 
 // LoggerConfig holds logger-specific configuration
@@ -50,6 +199,14 @@ type LoggerConfig struct {
 	Encoding         string   `mapstructure:"encoding"`
 	OutputPaths      []string `mapstructure:"output_paths"`
 	ErrorOutputPaths []string `mapstructure:"error_output_paths"`
+
+	// AccessSampleRate is the fraction of successful requests that are
+	// access logged; failed and slow requests are always logged
+	AccessSampleRate float64 `mapstructure:"access_sample_rate"`
+	// AccessExcludePaths are never access logged, e.g. health checks
+	AccessExcludePaths []string `mapstructure:"access_exclude_paths"`
+	// SlowRequestThreshold logs slower requests as warnings, 0 to disable
+	SlowRequestThreshold time.Duration `mapstructure:"slow_request_threshold"`
 }
 
 // Load loads the configuration from files and environment variables
@@ -66,18 +223,82 @@ func Load() *Config {
 	// Set default values (will be overridden by env vars if present)
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.allow_origins", "*")
+	viper.SetDefault("server.trusted_proxies", []string{})
+	viper.SetDefault("server.trusted_platform", "")
+	viper.SetDefault("server.health_check_timeout", 2*time.Second)
+	viper.SetDefault("server.shutdown_drain", 5*time.Second)
+	viper.SetDefault("auth.provider", AuthProviderFirebase)
+	viper.SetDefault("auth.token_cache_size", 10000)
+	viper.SetDefault("auth.token_cache_ttl", time.Hour)
+	viper.SetDefault("auth.token_cache_redis", false)
+	viper.SetDefault("auth.revocation_check_interval", 5*time.Minute)
 	viper.SetDefault("firebase.credentials_file", "./firebase-credentials.json")
 	viper.SetDefault("mongodb.uri", "mongodb://localhost:27017")
 	viper.SetDefault("mongodb.database", "letsnormalizeit")
+	viper.SetDefault("mongodb.auto_migrate", true)
+	viper.SetDefault("redis.enabled", true)
 	viper.SetDefault("redis.address", "localhost:6379")
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
+	viper.SetDefault("redis.blog_ttl", 15*time.Minute)
+	viper.SetDefault("redis.list_ttl", 2*time.Minute)
+	viper.SetDefault("redis.popular_ttl", 5*time.Minute)
+	viper.SetDefault("redis.comment_ttl", 5*time.Minute)
+	viper.SetDefault("redis.render_ttl", 24*time.Hour)
+	viper.SetDefault("redis.reconnect_interval", 5*time.Second)
+
+	// Startup defaults
+	viper.SetDefault("startup.retry_attempts", 5)
+	viper.SetDefault("startup.retry_initial_backoff", time.Second)
+	viper.SetDefault("startup.retry_max_backoff", 15*time.Second)
+
+	// Blog lifecycle defaults
+	viper.SetDefault("blogs.scheduler_interval", time.Minute)
+
+	// Comment thread defaults
+	viper.SetDefault("comments.max_depth", 5)
+	viper.SetDefault("comments.replies_per_level", 3)
+
+	// Rate limit defaults
+	viper.SetDefault("ratelimit.backend", RateLimitBackendRedis)
+	viper.SetDefault("ratelimit.anonymous.requests", 60)
+	viper.SetDefault("ratelimit.anonymous.window", time.Minute)
+	viper.SetDefault("ratelimit.anonymous.identity", "ip")
+	viper.SetDefault("ratelimit.authenticated.requests", 300)
+	viper.SetDefault("ratelimit.authenticated.window", time.Minute)
+	viper.SetDefault("ratelimit.authenticated.identity", "uid")
+	viper.SetDefault("ratelimit.auth.requests", 20)
+	viper.SetDefault("ratelimit.auth.window", time.Minute)
+	viper.SetDefault("ratelimit.auth.identity", "ip")
+	viper.SetDefault("ratelimit.admin.requests", 600)
+	viper.SetDefault("ratelimit.admin.window", time.Minute)
+	viper.SetDefault("ratelimit.admin.identity", "uid")
+	viper.SetDefault("ratelimit.preauth.requests", 1200)
+	viper.SetDefault("ratelimit.preauth.window", time.Minute)
+	viper.SetDefault("ratelimit.preauth.identity", "ip")
+
+	// Metrics defaults
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.path", "/metrics")
+
+	// Tracing defaults
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", TracingExporterStdout)
+	viper.SetDefault("tracing.service_name", "letsnormalizeit")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+	viper.SetDefault("tracing.otlp_endpoint", "localhost:4318")
+	viper.SetDefault("tracing.otlp_insecure", true)
+	viper.SetDefault("tracing.file", "./logs/traces.json")
+	viper.SetDefault("tracing.exclude_paths", []string{"/healthz", "/readyz", "/metrics"})
 
 	// Logger defaults
 	viper.SetDefault("logger.level", "info")
 	viper.SetDefault("logger.encoding", "json")
 	viper.SetDefault("logger.output_paths", []string{"stdout"})
 	viper.SetDefault("logger.error_output_paths", []string{"stderr"})
+	viper.SetDefault("logger.access_sample_rate", 1.0)
+	viper.SetDefault("logger.access_exclude_paths", []string{"/healthz", "/readyz", "/metrics"})
+	viper.SetDefault("logger.slow_request_threshold", time.Second)
 
 	// Try to read config file as fallback (optional)
 	configPath := "./configs"
@@ -103,15 +324,71 @@ func Load() *Config {
 	// Explicitly bind environment variables to ensure they override config file values
 	viper.BindEnv("server.port", "LNI_SERVER_PORT")
 	viper.BindEnv("server.allow_origins", "LNI_SERVER_ALLOW_ORIGINS")
+	viper.BindEnv("server.trusted_proxies", "LNI_SERVER_TRUSTED_PROXIES")
+	viper.BindEnv("server.trusted_platform", "LNI_SERVER_TRUSTED_PLATFORM")
+	viper.BindEnv("server.health_check_timeout", "LNI_SERVER_HEALTH_CHECK_TIMEOUT")
+	viper.BindEnv("server.shutdown_drain", "LNI_SERVER_SHUTDOWN_DRAIN")
+	viper.BindEnv("auth.provider", "LNI_AUTH_PROVIDER")
+	viper.BindEnv("auth.jwt_secret", "LNI_AUTH_JWT_SECRET")
+	viper.BindEnv("auth.jwt_keys_file", "LNI_AUTH_JWT_KEYS_FILE")
+	viper.BindEnv("auth.jwt_issuer", "LNI_AUTH_JWT_ISSUER")
+	viper.BindEnv("auth.jwt_audience", "LNI_AUTH_JWT_AUDIENCE")
+	viper.BindEnv("auth.token_cache_size", "LNI_AUTH_TOKEN_CACHE_SIZE")
+	viper.BindEnv("auth.token_cache_ttl", "LNI_AUTH_TOKEN_CACHE_TTL")
+	viper.BindEnv("auth.token_cache_redis", "LNI_AUTH_TOKEN_CACHE_REDIS")
+	viper.BindEnv("auth.revocation_check_interval", "LNI_AUTH_REVOCATION_CHECK_INTERVAL")
 	viper.BindEnv("firebase.credentials_file", "LNI_FIREBASE_CREDENTIALS_FILE")
 	viper.BindEnv("firebase.project_id", "LNI_FIREBASE_PROJECT_ID")
 	viper.BindEnv("mongodb.uri", "LNI_MONGODB_URI")
 	viper.BindEnv("mongodb.database", "LNI_MONGODB_DATABASE")
+	viper.BindEnv("mongodb.auto_migrate", "LNI_MONGODB_AUTO_MIGRATE")
+	viper.BindEnv("redis.enabled", "LNI_REDIS_ENABLED")
 	viper.BindEnv("redis.address", "LNI_REDIS_ADDRESS")
 	viper.BindEnv("redis.password", "LNI_REDIS_PASSWORD")
 	viper.BindEnv("redis.db", "LNI_REDIS_DB")
+	viper.BindEnv("redis.blog_ttl", "LNI_REDIS_BLOG_TTL")
+	viper.BindEnv("redis.list_ttl", "LNI_REDIS_LIST_TTL")
+	viper.BindEnv("redis.popular_ttl", "LNI_REDIS_POPULAR_TTL")
+	viper.BindEnv("redis.comment_ttl", "LNI_REDIS_COMMENT_TTL")
+	viper.BindEnv("redis.render_ttl", "LNI_REDIS_RENDER_TTL")
+	viper.BindEnv("redis.reconnect_interval", "LNI_REDIS_RECONNECT_INTERVAL")
+	viper.BindEnv("startup.retry_attempts", "LNI_STARTUP_RETRY_ATTEMPTS")
+	viper.BindEnv("startup.retry_initial_backoff", "LNI_STARTUP_RETRY_INITIAL_BACKOFF")
+	viper.BindEnv("startup.retry_max_backoff", "LNI_STARTUP_RETRY_MAX_BACKOFF")
+	viper.BindEnv("blogs.scheduler_interval", "LNI_BLOGS_SCHEDULER_INTERVAL")
+	viper.BindEnv("comments.max_depth", "LNI_COMMENTS_MAX_DEPTH")
+	viper.BindEnv("comments.replies_per_level", "LNI_COMMENTS_REPLIES_PER_LEVEL")
+	viper.BindEnv("ratelimit.backend", "LNI_RATELIMIT_BACKEND")
+	viper.BindEnv("ratelimit.anonymous.requests", "LNI_RATELIMIT_ANONYMOUS_REQUESTS")
+	viper.BindEnv("ratelimit.anonymous.window", "LNI_RATELIMIT_ANONYMOUS_WINDOW")
+	viper.BindEnv("ratelimit.anonymous.identity", "LNI_RATELIMIT_ANONYMOUS_IDENTITY")
+	viper.BindEnv("ratelimit.authenticated.requests", "LNI_RATELIMIT_AUTHENTICATED_REQUESTS")
+	viper.BindEnv("ratelimit.authenticated.window", "LNI_RATELIMIT_AUTHENTICATED_WINDOW")
+	viper.BindEnv("ratelimit.authenticated.identity", "LNI_RATELIMIT_AUTHENTICATED_IDENTITY")
+	viper.BindEnv("ratelimit.auth.requests", "LNI_RATELIMIT_AUTH_REQUESTS")
+	viper.BindEnv("ratelimit.auth.window", "LNI_RATELIMIT_AUTH_WINDOW")
+	viper.BindEnv("ratelimit.auth.identity", "LNI_RATELIMIT_AUTH_IDENTITY")
+	viper.BindEnv("ratelimit.admin.requests", "LNI_RATELIMIT_ADMIN_REQUESTS")
+	viper.BindEnv("ratelimit.admin.window", "LNI_RATELIMIT_ADMIN_WINDOW")
+	viper.BindEnv("ratelimit.admin.identity", "LNI_RATELIMIT_ADMIN_IDENTITY")
+	viper.BindEnv("ratelimit.preauth.requests", "LNI_RATELIMIT_PREAUTH_REQUESTS")
+	viper.BindEnv("ratelimit.preauth.window", "LNI_RATELIMIT_PREAUTH_WINDOW")
+	viper.BindEnv("ratelimit.preauth.identity", "LNI_RATELIMIT_PREAUTH_IDENTITY")
+	viper.BindEnv("metrics.enabled", "LNI_METRICS_ENABLED")
+	viper.BindEnv("metrics.path", "LNI_METRICS_PATH")
+	viper.BindEnv("tracing.enabled", "LNI_TRACING_ENABLED")
+	viper.BindEnv("tracing.exporter", "LNI_TRACING_EXPORTER")
+	viper.BindEnv("tracing.service_name", "LNI_TRACING_SERVICE_NAME")
+	viper.BindEnv("tracing.sample_ratio", "LNI_TRACING_SAMPLE_RATIO")
+	viper.BindEnv("tracing.otlp_endpoint", "LNI_TRACING_OTLP_ENDPOINT")
+	viper.BindEnv("tracing.otlp_insecure", "LNI_TRACING_OTLP_INSECURE")
+	viper.BindEnv("tracing.file", "LNI_TRACING_FILE")
+	viper.BindEnv("tracing.exclude_paths", "LNI_TRACING_EXCLUDE_PATHS")
 	viper.BindEnv("logger.level", "LNI_LOGGER_LEVEL")
 	viper.BindEnv("logger.encoding", "LNI_LOGGER_ENCODING")
+	viper.BindEnv("logger.access_sample_rate", "LNI_LOGGER_ACCESS_SAMPLE_RATE")
+	viper.BindEnv("logger.access_exclude_paths", "LNI_LOGGER_ACCESS_EXCLUDE_PATHS")
+	viper.BindEnv("logger.slow_request_threshold", "LNI_LOGGER_SLOW_REQUEST_THRESHOLD")
 
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
@@ -135,12 +412,48 @@ func Load() *Config {
 }
 
 func validateConfig(config *Config) error {
-	if config.Firebase.ProjectID == "" {
-		return fmt.Errorf("Firebase project ID is required")
+	switch config.Auth.Provider {
+	case AuthProviderFirebase:
+		if config.Firebase.ProjectID == "" {
+			return fmt.Errorf("Firebase project ID is required")
+		}
+
+		if config.Firebase.CredentialsFile == "" {
+			return fmt.Errorf("Firebase credentials file path is required")
+		}
+
+		// Check if Firebase credentials file exists
+		if _, err := os.Stat(config.Firebase.CredentialsFile); os.IsNotExist(err) {
+			return fmt.Errorf("Firebase credentials file not found: %s", config.Firebase.CredentialsFile)
+		}
+	case AuthProviderLocal:
+		if config.Auth.JWTSecret == "" && config.Auth.JWTKeysFile == "" {
+			return fmt.Errorf("local auth requires a JWT secret or key set file")
+		}
+	default:
+		return fmt.Errorf("unknown auth provider: %s", config.Auth.Provider)
 	}
 
-	if config.Firebase.CredentialsFile == "" {
-		return fmt.Errorf("Firebase credentials file path is required")
+	switch config.RateLimit.Backend {
+	case RateLimitBackendRedis, RateLimitBackendMemory:
+	default:
+		return fmt.Errorf("unknown rate limit backend: %s", config.RateLimit.Backend)
+	}
+
+	if config.Blogs.SchedulerInterval <= 0 {
+		return fmt.Errorf("blog scheduler interval must be positive")
+	}
+
+	if config.Redis.ReconnectInterval <= 0 {
+		return fmt.Errorf("Redis reconnect interval must be positive")
+	}
+
+	if config.Tracing.Enabled {
+		switch config.Tracing.Exporter {
+		case TracingExporterOTLP, TracingExporterStdout, TracingExporterFile:
+		default:
+			return fmt.Errorf("unknown tracing exporter: %s", config.Tracing.Exporter)
+		}
 	}
 
 	if config.MongoDB.URI == "" {
@@ -151,13 +464,12 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("MongoDB database name is required")
 	}
 
-	// if config.Redis.Address == "" {
-	// 	return fmt.Errorf("Redis address is required")
-	// }
+	if config.Redis.Enabled && config.Redis.Address == "" {
+		return fmt.Errorf("Redis address is required")
+	}
 
-	// Check if Firebase credentials file exists
-	if _, err := os.Stat(config.Firebase.CredentialsFile); os.IsNotExist(err) {
-		return fmt.Errorf("Firebase credentials file not found: %s", config.Firebase.CredentialsFile)
+	if config.Startup.RetryAttempts < 1 {
+		return fmt.Errorf("startup retry attempts must be at least 1")
 	}
 
 	return nil
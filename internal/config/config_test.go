@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig is a configuration validateConfig accepts
+func validConfig() *Config {
+	return &Config{
+		Auth:      AuthConfig{Provider: AuthProviderLocal, JWTSecret: "secret"},
+		RateLimit: RateLimitConfig{Backend: RateLimitBackendMemory},
+		MongoDB:   MongoDBConfig{URI: "mongodb://localhost:27017", Database: "lni"},
+		Redis:     RedisConfig{ReconnectInterval: 5 * time.Second},
+		Blogs:     BlogConfig{SchedulerInterval: time.Minute},
+		Startup:   StartupConfig{RetryAttempts: 1},
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	cases := []struct {
+		name      string
+		configure func(cfg *Config)
+		wantErr   string
+	}{
+		{
+			name:      "Valid",
+			configure: func(cfg *Config) {},
+		},
+		{
+			name:      "ZeroSchedulerInterval",
+			configure: func(cfg *Config) { cfg.Blogs.SchedulerInterval = 0 },
+			wantErr:   "scheduler interval",
+		},
+		{
+			name:      "NegativeSchedulerInterval",
+			configure: func(cfg *Config) { cfg.Blogs.SchedulerInterval = -time.Second },
+			wantErr:   "scheduler interval",
+		},
+		{
+			name:      "ZeroReconnectInterval",
+			configure: func(cfg *Config) { cfg.Redis.ReconnectInterval = 0 },
+			wantErr:   "reconnect interval",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := validConfig()
+			tc.configure(cfg)
+
+			err := validateConfig(cfg)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateConfig: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("validateConfig = %v, want an error about %s", err, tc.wantErr)
+			}
+		})
+	}
+}
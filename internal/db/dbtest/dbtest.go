@@ -0,0 +1,61 @@
+// Package dbtest connects tests to a real MongoDB or Redis. Tests using them
+// are skipped unless LNI_TEST_MONGODB_URI or LNI_TEST_REDIS_ADDR points at a
+// server, e.g.
+//
+//	LNI_TEST_MONGODB_URI=mongodb://localhost:27017 LNI_TEST_REDIS_ADDR=localhost:6379 go test ./...
+package dbtest
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/db"
+	"github.com/dksensei/letsnormalizeit/internal/migrate"
+	"github.com/google/uuid"
+)
+
+// URIEnv is the environment variable holding the test server's URI
+const URIEnv = "LNI_TEST_MONGODB_URI"
+
+// NewMongoDB connects to the test server and returns a connection to a fresh
+// database with every schema migration applied, which is dropped when the
+// test ends. It skips the test when no server is configured.
+func NewMongoDB(t *testing.T) *db.MongoDB {
+	t.Helper()
+
+	uri := os.Getenv(URIEnv)
+	if uri == "" {
+		t.Skipf("%s not set, skipping MongoDB test", URIEnv)
+	}
+
+	name := "lni_test_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16]
+	mongodb, err := db.NewMongoDB(&config.MongoDBConfig{URI: uri, Database: name})
+	if err != nil {
+		t.Fatalf("connect to MongoDB: %v", err)
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := mongodb.Database.Drop(ctx); err != nil {
+			t.Errorf("drop test database: %v", err)
+		}
+		if err := mongodb.Close(ctx); err != nil {
+			t.Errorf("disconnect from MongoDB: %v", err)
+		}
+	})
+
+	migrator, err := migrate.New(mongodb, migrate.Migrations())
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background(), 0, false); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	return mongodb
+}
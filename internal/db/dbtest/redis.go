@@ -0,0 +1,43 @@
+package dbtest
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/db"
+	"github.com/google/uuid"
+)
+
+// RedisAddrEnv is the environment variable holding the test Redis address
+const RedisAddrEnv = "LNI_TEST_REDIS_ADDR"
+
+// NewRedis connects to the test Redis and returns the connection along with a
+// key prefix unique to the test. Keys are not cleaned up, so tests must only
+// use keys under the prefix and give them an expiry. It skips the test when
+// no server is configured.
+func NewRedis(t *testing.T) (*db.Redis, string) {
+	t.Helper()
+
+	addr := os.Getenv(RedisAddrEnv)
+	if addr == "" {
+		t.Skipf("%s not set, skipping Redis test", RedisAddrEnv)
+	}
+
+	r := db.OpenRedis(&config.RedisConfig{Address: addr})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := r.Ping(ctx); err != nil {
+		r.Close()
+		t.Fatalf("connect to Redis: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := r.Close(); err != nil {
+			t.Errorf("disconnect from Redis: %v", err)
+		}
+	})
+	return r, "lni_test_" + uuid.NewString() + ":"
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/metrics"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -22,7 +23,8 @@ func NewMongoDB(cfg *config.MongoDBConfig) (*MongoDB, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	clientOptions := options.Client().ApplyURI(cfg.URI)
+	// Observe the duration of every command
+	clientOptions := options.Client().ApplyURI(cfg.URI).SetMonitor(metrics.CommandMonitor())
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, err
@@ -31,6 +33,7 @@ func NewMongoDB(cfg *config.MongoDBConfig) (*MongoDB, error) {
 	// Ping the database to verify connection
 	err = client.Ping(ctx, readpref.Primary())
 	if err != nil {
+		client.Disconnect(context.Background())
 		return nil, err
 	}
 
@@ -46,6 +49,11 @@ func (m *MongoDB) Close(ctx context.Context) error {
 	return m.Client.Disconnect(ctx)
 }
 
+// Ping checks that the primary is reachable
+func (m *MongoDB) Ping(ctx context.Context) error {
+	return m.Client.Ping(ctx, readpref.Primary())
+}
+
 // GetCollection returns a MongoDB collection
 func (m *MongoDB) GetCollection(name string) *mongo.Collection {
 	return m.Database.Collection(name)
@@ -3,35 +3,96 @@ package db
 import (
 	"context"
 	"log"
+	"sync/atomic"
+	"time"
 
 	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/metrics"
+	"github.com/dksensei/letsnormalizeit/internal/tracing"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
 	"github.com/go-redis/redis/v8"
 )
 
-// Redis represents a Redis connection
+// Redis represents a Redis connection. Since Redis only backs caches and the
+// rate limiter, callers check Available and fall back to in-memory state
+// while it is unreachable.
 type Redis struct {
 	Client *redis.Client
+
+	available atomic.Bool
 }
 
 // NewRedis creates a new Redis connection
 func NewRedis(cfg *config.RedisConfig) (*Redis, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     cfg.Address,
-		Password: cfg.Password,
-		DB:       cfg.DB,
-	})
+	r := OpenRedis(cfg)
 
 	// Ping Redis to verify the connection
-	ctx := context.Background()
-	_, err := client.Ping(ctx).Result()
-	if err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := r.Ping(ctx); err != nil {
+		r.Close()
 		return nil, err
 	}
+	r.available.Store(true)
 
 	log.Println("Connected to Redis")
+	return r, nil
+}
+
+// OpenRedis creates a Redis client without connecting. It is unavailable
+// until Watch sees Redis answer.
+func OpenRedis(cfg *config.RedisConfig) *Redis {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Address,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+	// Observe the duration of every command and trace it
+	client.AddHook(metrics.RedisHook{})
+	client.AddHook(tracing.RedisHook{})
+
 	return &Redis{
 		Client: client,
-	}, nil
+	}
+}
+
+// Available reports whether Redis answered the last ping
+func (r *Redis) Available() bool {
+	return r.available.Load()
+}
+
+// Watch pings Redis every interval until ctx is done, marking it unavailable
+// when it stops answering and available again once it is back
+func (r *Redis) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pingCtx, cancel := context.WithTimeout(ctx, interval)
+				err := r.Ping(pingCtx)
+				cancel()
+				if ctx.Err() != nil {
+					return
+				}
+
+				switch wasAvailable := r.available.Swap(err == nil); {
+				case err != nil && wasAvailable:
+					utils.Warn("Redis unreachable, falling back to in-memory caches and rate limits: %v", err)
+				case err == nil && !wasAvailable:
+					utils.Info("Redis reachable again, reattaching caches and rate limits")
+				}
+			}
+		}
+	}()
+}
+
+// Ping checks that Redis is reachable
+func (r *Redis) Ping(ctx context.Context) error {
+	return r.Client.Ping(ctx).Err()
 }
 
 // Close closes the Redis connection
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+)
+
+// Retry calls connect until it succeeds or the configured attempts run out,
+// doubling the backoff between attempts up to the maximum. Backoffs are
+// jittered so replicas starting together do not retry in lockstep.
+func Retry(ctx context.Context, name string, cfg *config.StartupConfig, connect func() error) error {
+	backoff := cfg.RetryInitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := connect()
+		if err == nil {
+			return nil
+		}
+		if attempt >= cfg.RetryAttempts {
+			return fmt.Errorf("%s unreachable after %d attempts: %w", name, attempt, err)
+		}
+
+		wait := backoff/2 + rand.N(backoff/2+1)
+		utils.Warn("Failed to connect to %s (attempt %d/%d), retrying in %s: %v", name, attempt, cfg.RetryAttempts, wait, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, cfg.RetryMaxBackoff)
+	}
+}
@@ -0,0 +1,121 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/db"
+)
+
+var errRefused = errors.New("connection refused")
+
+func TestRetry(t *testing.T) {
+	cases := []struct {
+		name string
+		// failures is how many calls fail before connect succeeds
+		failures int
+		attempts int
+		want     int
+		wantErr  bool
+	}{
+		{name: "FirstAttempt", failures: 0, attempts: 3, want: 1},
+		{name: "LastAttempt", failures: 2, attempts: 3, want: 3},
+		{name: "OutOfAttempts", failures: 5, attempts: 3, want: 3, wantErr: true},
+		{name: "SingleAttempt", failures: 5, attempts: 1, want: 1, wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.StartupConfig{
+				RetryAttempts:       tc.attempts,
+				RetryInitialBackoff: time.Millisecond,
+				RetryMaxBackoff:     time.Millisecond,
+			}
+
+			calls := 0
+			err := db.Retry(context.Background(), "test", cfg, func() error {
+				calls++
+				if calls <= tc.failures {
+					return errRefused
+				}
+				return nil
+			})
+
+			if calls != tc.want {
+				t.Errorf("connect called %d times, want %d", calls, tc.want)
+			}
+			if tc.wantErr {
+				if !errors.Is(err, errRefused) {
+					t.Errorf("Retry() = %v, want it to wrap %v", err, errRefused)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Retry() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestRetryCancelled(t *testing.T) {
+	cfg := &config.StartupConfig{
+		RetryAttempts:       5,
+		RetryInitialBackoff: time.Hour,
+		RetryMaxBackoff:     time.Hour,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- db.Retry(ctx, "test", cfg, func() error {
+			calls++
+			return errRefused
+		})
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Retry() = %v, want %v", err, context.Canceled)
+		}
+		if calls != 1 {
+			t.Errorf("connect called %d times, want 1", calls)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Retry did not return after the context was cancelled")
+	}
+}
+
+func TestRetryBackoffCap(t *testing.T) {
+	// Uncapped, the doubling backoffs would wait at least
+	// 0.5+1+2+...+256ms ≈ 511ms before the last attempt. Capped at 2ms,
+	// the nine waits add up to at most 17ms.
+	cfg := &config.StartupConfig{
+		RetryAttempts:       10,
+		RetryInitialBackoff: time.Millisecond,
+		RetryMaxBackoff:     2 * time.Millisecond,
+	}
+
+	calls := 0
+	start := time.Now()
+	err := db.Retry(context.Background(), "test", cfg, func() error {
+		calls++
+		return errRefused
+	})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, errRefused) {
+		t.Errorf("Retry() = %v, want it to wrap %v", err, errRefused)
+	}
+	if calls != cfg.RetryAttempts {
+		t.Errorf("connect called %d times, want %d", calls, cfg.RetryAttempts)
+	}
+	if elapsed >= 250*time.Millisecond {
+		t.Errorf("Retry took %s, want the backoff capped at %s", elapsed, cfg.RetryMaxBackoff)
+	}
+}
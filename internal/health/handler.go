@@ -0,0 +1,38 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles the health check routes
+type Handler struct {
+	registry *Registry
+}
+
+// NewHandler creates a new health handler
+func NewHandler(registry *Registry) *Handler {
+	return &Handler{
+		registry: registry,
+	}
+}
+
+// Liveness reports that the process is up and serving. It checks no
+// dependencies, so an outage does not get the process restarted.
+func (h *Handler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, Report{Status: StatusOK})
+}
+
+// Readiness reports whether the server can take traffic, with the status
+// and latency of each dependency. It responds with 503 Service Unavailable
+// while a required dependency is down or the server is draining, but keeps
+// taking traffic while degraded.
+func (h *Handler) Readiness(c *gin.Context) {
+	report := h.registry.Check(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK && report.Status != StatusDegraded {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,165 @@
+// Package health reports whether the server is alive and ready for traffic.
+// Subsystems register a Checker for each dependency they use; readiness
+// fails while a required one fails or while the server drains on shutdown,
+// and is degraded while an optional one fails.
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Statuses reported for the server and its dependencies
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+// Checker checks a dependency
+type Checker interface {
+	// Check returns an error when the dependency is unusable
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function such as db.MongoDB.Ping to a Checker
+type CheckerFunc func(ctx context.Context) error
+
+// Check calls f
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Registry holds the registered checks and the drain state
+type Registry struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]check
+
+	draining atomic.Bool
+}
+
+// check is a registered dependency check
+type check struct {
+	checker  Checker
+	optional bool
+}
+
+// Report is the outcome of a readiness check
+type Report struct {
+	Status string                  `json:"status"`
+	Checks map[string]*CheckResult `json:"checks,omitempty"`
+}
+
+// CheckResult is the outcome of one dependency's check
+type CheckResult struct {
+	Status    string  `json:"status"`
+	Optional  bool    `json:"optional,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// NewRegistry creates a registry whose checks each get timeout to complete
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{
+		timeout: timeout,
+		checks:  make(map[string]check),
+	}
+}
+
+// Register adds the check of a required dependency, replacing any check of
+// the same name
+func (r *Registry) Register(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check{checker: checker}
+}
+
+// RegisterOptional adds the check of a dependency the server can run
+// without, replacing any check of the same name. Its failure degrades
+// readiness without failing it.
+func (r *Registry) RegisterOptional(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check{checker: checker, optional: true}
+}
+
+// Drain marks the server as shutting down, so it reports not ready while
+// in-flight requests complete
+func (r *Registry) Drain() {
+	r.draining.Store(true)
+}
+
+// Draining reports whether the server is shutting down
+func (r *Registry) Draining() bool {
+	return r.draining.Load()
+}
+
+// Check runs every registered check concurrently and reports the result.
+// The server is ready when it is not draining and every required check
+// passed; it is degraded when an optional check failed.
+func (r *Registry) Check(ctx context.Context) *Report {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.checks))
+	for name := range r.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]check, len(names))
+	for i, name := range names {
+		checks[i] = r.checks[name]
+	}
+	r.mu.RUnlock()
+
+	results := make([]*CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.run(ctx, check.checker)
+			results[i].Optional = check.optional
+		}()
+	}
+	wg.Wait()
+
+	report := &Report{Status: StatusOK, Checks: make(map[string]*CheckResult, len(names))}
+	for i, name := range names {
+		result := results[i]
+		report.Checks[name] = result
+		switch {
+		case result.Status == StatusOK:
+		case !result.Optional:
+			report.Status = StatusUnavailable
+		case report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	if r.Draining() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// run runs one check within the timeout
+func (r *Registry) run(ctx context.Context, checker Checker) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := &CheckResult{
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	healthy = CheckerFunc(func(ctx context.Context) error { return nil })
+	failing = CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+	hanging = CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+)
+
+func TestRegistryCheck(t *testing.T) {
+	cases := []struct {
+		name      string
+		required  map[string]Checker
+		optional  map[string]Checker
+		drain     bool
+		want      string
+		wantCheck map[string]string
+	}{
+		{name: "NoChecks", want: StatusOK},
+		{
+			name:      "Healthy",
+			required:  map[string]Checker{"mongodb": healthy},
+			optional:  map[string]Checker{"redis": healthy},
+			want:      StatusOK,
+			wantCheck: map[string]string{"mongodb": StatusOK, "redis": StatusOK},
+		},
+		{
+			name:      "RequiredDown",
+			required:  map[string]Checker{"mongodb": failing},
+			optional:  map[string]Checker{"redis": healthy},
+			want:      StatusUnavailable,
+			wantCheck: map[string]string{"mongodb": StatusUnavailable, "redis": StatusOK},
+		},
+		{
+			name:      "OptionalDown",
+			required:  map[string]Checker{"mongodb": healthy},
+			optional:  map[string]Checker{"redis": failing},
+			want:      StatusDegraded,
+			wantCheck: map[string]string{"mongodb": StatusOK, "redis": StatusUnavailable},
+		},
+		{
+			name:      "BothDown",
+			required:  map[string]Checker{"mongodb": failing},
+			optional:  map[string]Checker{"redis": failing},
+			want:      StatusUnavailable,
+			wantCheck: map[string]string{"mongodb": StatusUnavailable, "redis": StatusUnavailable},
+		},
+		{
+			name:      "TimedOut",
+			required:  map[string]Checker{"mongodb": hanging},
+			want:      StatusUnavailable,
+			wantCheck: map[string]string{"mongodb": StatusUnavailable},
+		},
+		{
+			name:      "Draining",
+			required:  map[string]Checker{"mongodb": healthy},
+			drain:     true,
+			want:      StatusDraining,
+			wantCheck: map[string]string{"mongodb": StatusOK},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := NewRegistry(10 * time.Millisecond)
+			for name, checker := range tc.required {
+				registry.Register(name, checker)
+			}
+			for name, checker := range tc.optional {
+				registry.RegisterOptional(name, checker)
+			}
+			if tc.drain {
+				registry.Drain()
+			}
+
+			report := registry.Check(context.Background())
+			if report.Status != tc.want {
+				t.Errorf("Status = %s, want %s", report.Status, tc.want)
+			}
+			if len(report.Checks) != len(tc.wantCheck) {
+				t.Errorf("got %d checks, want %d", len(report.Checks), len(tc.wantCheck))
+			}
+			for name, want := range tc.wantCheck {
+				result := report.Checks[name]
+				if result == nil {
+					t.Errorf("check %s missing", name)
+					continue
+				}
+				if result.Status != want {
+					t.Errorf("check %s = %s, want %s", name, result.Status, want)
+				}
+				if (result.Error != "") != (want != StatusOK) {
+					t.Errorf("check %s error = %q", name, result.Error)
+				}
+				if _, optional := tc.optional[name]; result.Optional != optional {
+					t.Errorf("check %s Optional = %v, want %v", name, result.Optional, optional)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryReplacesChecks(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register("redis", failing)
+	registry.RegisterOptional("redis", failing)
+
+	if report := registry.Check(context.Background()); report.Status != StatusDegraded {
+		t.Errorf("Status = %s, want %s", report.Status, StatusDegraded)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := NewRegistry(time.Second)
+	handler := NewHandler(registry)
+	router := gin.New()
+	router.GET("/healthz", handler.Liveness)
+	router.GET("/readyz", handler.Readiness)
+
+	steps := []struct {
+		name       string
+		change     func()
+		wantLive   int
+		wantReady  int
+		wantStatus string
+	}{
+		{name: "Ready", change: func() {}, wantLive: http.StatusOK, wantReady: http.StatusOK, wantStatus: StatusOK},
+		{name: "Degraded", change: func() { registry.RegisterOptional("redis", failing) }, wantLive: http.StatusOK, wantReady: http.StatusOK, wantStatus: StatusDegraded},
+		{name: "Unavailable", change: func() { registry.Register("mongodb", failing) }, wantLive: http.StatusOK, wantReady: http.StatusServiceUnavailable, wantStatus: StatusUnavailable},
+		{name: "Recovered", change: func() { registry.Register("mongodb", healthy) }, wantLive: http.StatusOK, wantReady: http.StatusOK, wantStatus: StatusDegraded},
+		{name: "Draining", change: registry.Drain, wantLive: http.StatusOK, wantReady: http.StatusServiceUnavailable, wantStatus: StatusDraining},
+	}
+
+	for _, step := range steps {
+		step.change()
+
+		live := httptest.NewRecorder()
+		router.ServeHTTP(live, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		if live.Code != step.wantLive {
+			t.Errorf("%s: liveness = %d, want %d", step.name, live.Code, step.wantLive)
+		}
+
+		ready := httptest.NewRecorder()
+		router.ServeHTTP(ready, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		if ready.Code != step.wantReady {
+			t.Errorf("%s: readiness = %d, want %d", step.name, ready.Code, step.wantReady)
+		}
+		var report Report
+		if err := json.Unmarshal(ready.Body.Bytes(), &report); err != nil {
+			t.Fatalf("%s: decode report: %v", step.name, err)
+		}
+		if report.Status != step.wantStatus {
+			t.Errorf("%s: status = %s, want %s", step.name, report.Status, step.wantStatus)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// samples returns how many observations a histogram series holds, or the
+// value of a counter series, by gathering Registry
+func samples(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	families, err := Registry.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			matched := 0
+			for _, pair := range metric.GetLabel() {
+				if value, ok := labels[pair.GetName()]; ok && value == pair.GetValue() {
+					matched++
+				}
+			}
+			if matched != len(labels) {
+				continue
+			}
+			if histogram := metric.GetHistogram(); histogram != nil {
+				return float64(histogram.GetSampleCount())
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestRedisHook(t *testing.T) {
+	ctx := context.Background()
+	failed := errors.New("connection reset")
+
+	command := func(name string, err error) redis.Cmder {
+		cmd := redis.NewStringCmd(ctx, name, "key")
+		if err != nil {
+			cmd.SetErr(err)
+		}
+		return cmd
+	}
+
+	cases := []struct {
+		name        string
+		cmds        []redis.Cmder
+		wantCommand string
+		wantOutcome string
+	}{
+		{name: "Success", cmds: []redis.Cmder{command("test_set", nil)}, wantCommand: "test_set", wantOutcome: outcomeSuccess},
+		{name: "MissingKey", cmds: []redis.Cmder{command("test_get", redis.Nil)}, wantCommand: "test_get", wantOutcome: outcomeSuccess},
+		{name: "Error", cmds: []redis.Cmder{command("test_del", failed)}, wantCommand: "test_del", wantOutcome: outcomeError},
+		{name: "Pipeline", cmds: []redis.Cmder{command("test_get", redis.Nil), command("test_set", nil)}, wantCommand: "pipeline", wantOutcome: outcomeSuccess},
+		{name: "FailedPipeline", cmds: []redis.Cmder{command("test_get", nil), command("test_set", failed)}, wantCommand: "pipeline", wantOutcome: outcomeError},
+	}
+
+	hook := RedisHook{}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			labels := map[string]string{"command": tc.wantCommand, "outcome": tc.wantOutcome}
+			before := samples(t, "lni_redis_command_duration_seconds", labels)
+
+			if len(tc.cmds) == 1 {
+				hookCtx, err := hook.BeforeProcess(ctx, tc.cmds[0])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := hook.AfterProcess(hookCtx, tc.cmds[0]); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				hookCtx, err := hook.BeforeProcessPipeline(ctx, tc.cmds)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := hook.AfterProcessPipeline(hookCtx, tc.cmds); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := samples(t, "lni_redis_command_duration_seconds", labels) - before; got != 1 {
+				t.Errorf("observations of %v = %v, want 1", labels, got)
+			}
+		})
+	}
+
+	// Commands that never went through BeforeProcess are not observed
+	labels := map[string]string{"command": "test_orphan", "outcome": outcomeSuccess}
+	if err := hook.AfterProcess(ctx, command("test_orphan", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if got := samples(t, "lni_redis_command_duration_seconds", labels); got != 0 {
+		t.Errorf("observations of %v = %v, want 0", labels, got)
+	}
+}
+
+func TestCommandMonitor(t *testing.T) {
+	monitor := CommandMonitor()
+	ctx := context.Background()
+	finished := event.CommandFinishedEvent{CommandName: "test_find", Duration: time.Millisecond}
+
+	for _, tc := range []struct {
+		outcome string
+		observe func()
+	}{
+		{outcomeSuccess, func() { monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished}) }},
+		{outcomeError, func() { monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished}) }},
+	} {
+		labels := map[string]string{"command": "test_find", "outcome": tc.outcome}
+		before := samples(t, "lni_mongodb_command_duration_seconds", labels)
+		tc.observe()
+		if got := samples(t, "lni_mongodb_command_duration_seconds", labels) - before; got != 1 {
+			t.Errorf("observations of %v = %v, want 1", labels, got)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	ObserveCache("test_cache", CacheHit)
+	ObserveCache("test_cache", CacheHit)
+	ObserveCache("test_cache", CacheMiss)
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+
+	for _, want := range []string{
+		`lni_cache_requests_total{cache="test_cache",result="hit"} 2`,
+		`lni_cache_requests_total{cache="test_cache",result="miss"} 1`,
+		"go_goroutines ",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %q", want)
+		}
+	}
+}
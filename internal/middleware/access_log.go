@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog creates a middleware that writes one structured line per request
+// through the request's logger, so it carries the request ID, client IP and
+// user. Server errors are logged as errors and requests slower than the
+// threshold as warnings; successful requests are sampled at the configured
+// rate. It must run after RequestID.
+func AccessLog(cfg *config.LoggerConfig) gin.HandlerFunc {
+	excluded := make(map[string]bool, len(cfg.AccessExcludePaths))
+	for _, path := range cfg.AccessExcludePaths {
+		excluded[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if excluded[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		status := c.Writer.Status()
+		slow := cfg.SlowRequestThreshold > 0 && latency >= cfg.SlowRequestThreshold
+		if status < http.StatusBadRequest && !slow && !sampled(cfg.AccessSampleRate) {
+			return
+		}
+
+		// c.Request carries the logger as enriched by later middleware,
+		// e.g. with the uid set by AuthMiddleware
+		logger := utils.FromContext(c.Request.Context()).With(
+			"status", status,
+			"latencyMs", float64(latency.Microseconds())/1000,
+			"bytes", max(c.Writer.Size(), 0),
+			"route", c.FullPath(),
+			"userAgent", c.Request.UserAgent(),
+		)
+		if errs := c.Errors.ByType(gin.ErrorTypePrivate).String(); errs != "" {
+			logger = logger.With("errors", errs)
+		}
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			logger.Error("Request failed")
+		case slow:
+			logger.Warn("Slow request (threshold: %s)", cfg.SlowRequestThreshold)
+		default:
+			logger.Info("Request completed")
+		}
+	}
+}
+
+// sampled reports whether a request falls within the sample rate
+func sampled(rate float64) bool {
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
@@ -0,0 +1,95 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeLogs routes the global logger into an observer for the rest of the test
+func observeLogs(t *testing.T) *observer.ObservedLogs {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger, sugared := utils.Logger, utils.SugaredLogger
+	utils.Logger = zap.New(core)
+	utils.SugaredLogger = utils.Logger.Sugar()
+	t.Cleanup(func() {
+		utils.Logger, utils.SugaredLogger = logger, sugared
+	})
+	return logs
+}
+
+func TestAccessLog(t *testing.T) {
+	cases := []struct {
+		name      string
+		cfg       config.LoggerConfig
+		path      string
+		wantLevel zapcore.Level
+		wantMsg   string
+	}{
+		{name: "SuccessNotSampled", cfg: config.LoggerConfig{AccessSampleRate: 0}, path: "/items/1"},
+		{name: "SuccessSampled", cfg: config.LoggerConfig{AccessSampleRate: 1}, path: "/items/1", wantLevel: zapcore.InfoLevel, wantMsg: "Request completed"},
+		{name: "ClientError", cfg: config.LoggerConfig{AccessSampleRate: 0}, path: "/items/404", wantLevel: zapcore.InfoLevel, wantMsg: "Request completed"},
+		{name: "ServerError", cfg: config.LoggerConfig{AccessSampleRate: 0}, path: "/items/500", wantLevel: zapcore.ErrorLevel, wantMsg: "Request failed"},
+		{name: "Slow", cfg: config.LoggerConfig{AccessSampleRate: 0, SlowRequestThreshold: time.Nanosecond}, path: "/items/1", wantLevel: zapcore.WarnLevel, wantMsg: "Slow request (threshold: 1ns)"},
+		{name: "Excluded", cfg: config.LoggerConfig{AccessSampleRate: 1, AccessExcludePaths: []string{"/items/500"}}, path: "/items/500"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := observeLogs(t)
+
+			router := gin.New()
+			router.Use(middleware.RequestID(), middleware.AccessLog(&tc.cfg))
+			router.GET("/items/:id", func(c *gin.Context) {
+				switch c.Param("id") {
+				case "404":
+					c.Status(http.StatusNotFound)
+				case "500":
+					c.Status(http.StatusInternalServerError)
+				default:
+					c.String(http.StatusOK, "ok")
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			req.Header.Set(middleware.RequestIDHeader, "req-1")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			entries := logs.All()
+			if tc.wantMsg == "" {
+				if len(entries) != 0 {
+					t.Fatalf("logged %d entries, want none", len(entries))
+				}
+				return
+			}
+			if len(entries) != 1 {
+				t.Fatalf("logged %d entries, want 1", len(entries))
+			}
+
+			entry := entries[0]
+			if entry.Level != tc.wantLevel || entry.Message != tc.wantMsg {
+				t.Errorf("logged %s %q, want %s %q", entry.Level, entry.Message, tc.wantLevel, tc.wantMsg)
+			}
+			fields := entry.ContextMap()
+			for key, want := range map[string]interface{}{"requestID": "req-1", "route": "/items/:id", "path": tc.path} {
+				if fields[key] != want {
+					t.Errorf("field %s = %v, want %v", key, fields[key], want)
+				}
+			}
+			if _, ok := fields["latencyMs"]; !ok {
+				t.Error("latencyMs not logged")
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package middleware
 
 import (
 	"context"
-	"net/http"
 	"strings"
 
-	"github.com/dksensei/letsnormalizeit/internal/auth"
+	firebaseauth "firebase.google.com/go/v4/auth"
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/metrics"
+	"github.com/dksensei/letsnormalizeit/internal/model"
 	"github.com/dksensei/letsnormalizeit/internal/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -15,19 +17,18 @@ type contextKey string
 
 const UserIDKey contextKey = "userID"
 
+// TokenKey is the gin context key holding the verified *firebaseauth.Token
+const TokenKey = "token"
+
 // AuthMiddleware creates a middleware for authenticating requests
-func AuthMiddleware(authService *auth.Service) gin.HandlerFunc {
+func AuthMiddleware(authService model.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logger := utils.NewLogContext(
-			"path", c.Request.URL.Path,
-			"method", c.Request.Method,
-			"clientIP", c.ClientIP(),
-		)
+		logger := utils.FromContext(c.Request.Context())
 
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			logger.Warn("Request missing Authorization header")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+			apperror.Respond(c, apperror.Unauthorized("missing_authorization", "Authorization header is required"))
 			return
 		}
 		// Extract the token from the Authorization header
@@ -35,22 +36,19 @@ func AuthMiddleware(authService *auth.Service) gin.HandlerFunc {
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
 			logger.Warn("Invalid Authorization header format")
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer <token>"})
+			apperror.Respond(c, apperror.Unauthorized("invalid_authorization", "Authorization header format must be Bearer <token>"))
 			return
 		}
 
-		idToken := parts[1]
-		token, err := authService.VerifyToken(c.Request.Context(), idToken)
+		token, err := verifyToken(c, authService, parts[1])
 		if err != nil {
 			logger.Warn("Token verification failed: %v", err)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			if _, ok := apperror.As(err); !ok {
+				err = apperror.Wrap(err, apperror.KindUnauthorized, "invalid_token", "invalid token")
+			}
+			apperror.Respond(c, err)
 			return
 		}
-		// Set user ID in the context
-		c.Set("uid", token.UID)
-		// Add user data to the request context for potential usage in services
-		ctx := context.WithValue(c.Request.Context(), UserIDKey, token.UID)
-		c.Request = c.Request.WithContext(ctx)
 
 		// Update logger with user information
 		logger.With("userID", token.UID).Info("User authenticated successfully")
@@ -66,7 +64,7 @@ func GetUserID(ctx context.Context) (string, bool) {
 }
 
 // OptionalAuth middleware tries to authenticate but allows requests to proceed if authentication fails
-func OptionalAuth(authService *auth.Service) gin.HandlerFunc {
+func OptionalAuth(authService model.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -80,48 +78,55 @@ func OptionalAuth(authService *auth.Service) gin.HandlerFunc {
 			return
 		}
 
-		idToken := parts[1]
-		token, err := authService.VerifyToken(c.Request.Context(), idToken)
-		if err != nil {
+		if _, err := verifyToken(c, authService, parts[1]); err != nil {
 			c.Next() // Proceed without setting user ID
 			return
 		}
 
-		// Set user ID in the context
-		c.Set("uid", token.UID)
-		ctx := context.WithValue(c.Request.Context(), UserIDKey, token.UID)
-		c.Request = c.Request.WithContext(ctx)
-
 		c.Next()
 	}
 }
 
-// AdminOnly middleware ensures the user has admin claims
-func AdminOnly(authService *auth.Service) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// First, use the standard auth middleware
-		_, exists := c.Get("uid")
-		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
-			return
-		}
+// GetToken retrieves the token verified by AuthMiddleware or OptionalAuth
+func GetToken(c *gin.Context) *firebaseauth.Token {
+	token, _ := c.Get(TokenKey)
+	verified, _ := token.(*firebaseauth.Token)
+	return verified
+}
 
-		// Check for admin claim in the token
-		tokenString := strings.Split(c.GetHeader("Authorization"), " ")[1]
-		token, err := authService.VerifyToken(c.Request.Context(), tokenString)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			return
-		}
+// verifyToken verifies the request's ID token, unless an earlier middleware
+// already did, and stores the token and user ID in the context
+func verifyToken(c *gin.Context, authService model.AuthService, idToken string) (*firebaseauth.Token, error) {
+	if token := GetToken(c); token != nil {
+		return token, nil
+	}
 
-		// Check if the user has admin claim
-		// This assumes that 'admin' is a boolean claim in your Firebase token
-		isAdmin, ok := token.Claims["admin"].(bool)
-		if !ok || !isAdmin {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-			return
-		}
+	token, err := authService.VerifyToken(c.Request.Context(), idToken)
+	metrics.TokenVerifications.WithLabelValues(verificationOutcome(err)).Inc()
+	if err != nil {
+		return nil, err
+	}
 
-		c.Next()
+	// Set the token and user ID in the context
+	c.Set(TokenKey, token)
+	c.Set("uid", token.UID)
+	// Add user data to the request context for potential usage in services,
+	// and tag the rest of the request's logs with the user
+	ctx := context.WithValue(c.Request.Context(), UserIDKey, token.UID)
+	ctx = utils.ContextWithLogger(ctx, utils.FromContext(ctx).With("uid", token.UID))
+	c.Request = c.Request.WithContext(ctx)
+
+	return token, nil
+}
+
+// verificationOutcome is the metrics outcome of a token verification: valid,
+// the code of a typed error or the generic internal error code
+func verificationOutcome(err error) string {
+	if err == nil {
+		return "valid"
+	}
+	if typed, ok := apperror.As(err); ok {
+		return typed.Code
 	}
+	return apperror.CodeInternal
 }
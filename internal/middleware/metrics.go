@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute is the route label of requests that matched no route
+const unmatchedRoute = "unmatched"
+
+// knownMethods are the methods reported as themselves in metrics
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// Metrics creates a middleware that counts requests and observes their
+// latency by method, route template and status. Routes are labelled by their
+// template, e.g. /api/v1/blogs/:id, so IDs never become label values.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		method := c.Request.Method
+		if !knownMethods[method] {
+			method = "OTHER"
+		}
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(method, route, status).Observe(latency.Seconds())
+	}
+}
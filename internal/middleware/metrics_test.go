@@ -0,0 +1,63 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/metrics"
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsRouteLabels(t *testing.T) {
+	router := gin.New()
+	router.Use(middleware.Metrics())
+	router.GET("/metrics-test/items/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.Handle("PROPFIND", "/metrics-test/items/:id", func(c *gin.Context) { c.Status(http.StatusMultiStatus) })
+	router.POST("/metrics-test/items", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		wantMethod string
+		wantRoute  string
+		wantStatus string
+	}{
+		{name: "Template", method: http.MethodGet, path: "/metrics-test/items/42", wantMethod: "GET", wantRoute: "/metrics-test/items/:id", wantStatus: "200"},
+		{name: "OtherID", method: http.MethodGet, path: "/metrics-test/items/43", wantMethod: "GET", wantRoute: "/metrics-test/items/:id", wantStatus: "200"},
+		{name: "Static", method: http.MethodPost, path: "/metrics-test/items", wantMethod: "POST", wantRoute: "/metrics-test/items", wantStatus: "201"},
+		{name: "UnknownMethod", method: "PROPFIND", path: "/metrics-test/items/42", wantMethod: "OTHER", wantRoute: "/metrics-test/items/:id", wantStatus: "207"},
+		{name: "Unmatched", method: http.MethodGet, path: "/metrics-test/nope/42", wantMethod: "GET", wantRoute: "unmatched", wantStatus: "404"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			counter := metrics.HTTPRequests.WithLabelValues(tc.wantMethod, tc.wantRoute, tc.wantStatus)
+			before := testutil.ToFloat64(counter)
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tc.method, tc.path, nil))
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("requests{method=%s,route=%s,status=%s} grew by %v, want 1", tc.wantMethod, tc.wantRoute, tc.wantStatus, got)
+			}
+		})
+	}
+
+	// Raw paths never become label values
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "route" && (label.GetValue() == "/metrics-test/items/42" || label.GetValue() == "/metrics-test/nope/42") {
+					t.Errorf("%s has raw path label %q", family.GetName(), label.GetValue())
+				}
+			}
+		}
+	}
+}
@@ -1,120 +1,94 @@
 package middleware
 
 import (
-	"net/http"
-	"sync"
+	"math"
+	"strconv"
 	"time"
 
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/metrics"
+	"github.com/dksensei/letsnormalizeit/internal/ratelimit"
 	"github.com/dksensei/letsnormalizeit/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
-// RateLimiter implements a simple in-memory rate limiter
-type RateLimiter struct {
-	// Map of IP/user to last request time and count
-	clients map[string]rateLimitClient
-	mu      *sync.Mutex
-	limit   int           // Maximum number of requests allowed
-	window  time.Duration // Time window for rate limiting
-}
-
-type rateLimitClient struct {
-	count    int       // Number of requests made
-	lastSeen time.Time // Last request time
-}
-
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
-	return &RateLimiter{
-		clients: make(map[string]rateLimitClient),
-		mu:      &sync.Mutex{},
-		limit:   limit,
-		window:  window,
+// RateLimit creates a middleware that limits requests according to a policy.
+// Requests are counted per identity and the quota is reported in the
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers, plus
+// Retry-After once it is exhausted. If the limiter fails the request is let
+// through rather than taking the API down with it.
+//
+// Policies counting by uid must run after AuthMiddleware or OptionalAuth.
+func RateLimit(limiter ratelimit.Limiter, policy ratelimit.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limitRequest(c, limiter, policy)
 	}
 }
 
-// RateLimit creates a middleware that limits requests based on IP address
-func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
+// RateLimitByUser creates a middleware that applies the authenticated policy
+// to requests of signed in users and the anonymous policy to the rest, so
+// users sharing an IP do not share a quota. It must run after AuthMiddleware
+// or OptionalAuth.
+func RateLimitByUser(limiter ratelimit.Limiter, anonymous, authenticated ratelimit.Policy) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get client identifier (IP address or user ID if authenticated)
-		clientID := c.ClientIP()
-
-		// Create logger with request context
-		logger := utils.NewLogContext(
-			"path", c.Request.URL.Path,
-			"method", c.Request.Method,
-			"clientIP", clientID,
-		)
-
-		// If user is authenticated, use user ID instead
-		if uid, exists := c.Get("uid"); exists {
-			clientID = uid.(string)
-			logger = logger.With("userID", clientID)
+		if c.GetString("uid") != "" {
+			limitRequest(c, limiter, authenticated)
+			return
 		}
+		limitRequest(c, limiter, anonymous)
+	}
+}
 
-		allow := func() bool {
-			rl.mu.Lock()
-			defer rl.mu.Unlock()
+// limitRequest counts the request against the policy and either continues
+// the chain or aborts it with 429 Too Many Requests
+func limitRequest(c *gin.Context, limiter ratelimit.Limiter, policy ratelimit.Policy) {
+	identity, value := rateLimitIdentity(c, policy.Identities)
+	logger := utils.FromContext(c.Request.Context()).With(
+		"policy", policy.Name,
+		"identity", identity,
+	)
+
+	key := policy.Name + ":" + string(identity) + ":" + value
+	result, err := limiter.Allow(c.Request.Context(), key, policy.Limit)
+	if err != nil {
+		logger.Error("Rate limiter unavailable, allowing request: %v", err)
+		c.Next()
+		return
+	}
 
-			now := time.Now()
-			client, exists := rl.clients[clientID]
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
 
-			// If client doesn't exist or window has elapsed, reset the counter
-			if !exists || now.Sub(client.lastSeen) > rl.window {
-				rl.clients[clientID] = rateLimitClient{
-					count:    1,
-					lastSeen: now,
-				}
-				return true
-			}
-
-			// If client has exceeded the limit, reject the request
-			if client.count >= rl.limit {
-				logger.Warn("Rate limit exceeded by client: %s (count: %d, limit: %d)",
-					clientID, client.count, rl.limit)
-				return false
-			}
+	if !result.Allowed {
+		logger.Warn("Rate limit exceeded (limit: %d per %s)", policy.Limit.Requests, policy.Limit.Window)
+		metrics.RateLimitRejections.WithLabelValues(policy.Name).Inc()
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		apperror.Respond(c, apperror.ErrRateLimited)
+		return
+	}
 
-			// Otherwise, increment the counter and allow the request
-			client.count++
-			client.lastSeen = now
-			rl.clients[clientID] = client
-			return true
-		}()
+	c.Next()
+}
 
-		if !allow {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": "Rate limit exceeded. Try again later.",
-			})
-			return
+// rateLimitIdentity picks the first identity of the request among the
+// allowed ones, falling back to the client IP. The client IP only honours
+// forwarding headers from the router's trusted proxies.
+func rateLimitIdentity(c *gin.Context, identities []ratelimit.Identity) (ratelimit.Identity, string) {
+	for _, identity := range identities {
+		switch identity {
+		case ratelimit.IdentityUser:
+			if uid := c.GetString("uid"); uid != "" {
+				return identity, uid
+			}
+		case ratelimit.IdentityIP:
+			return identity, c.ClientIP()
 		}
-
-		c.Next()
 	}
+	return ratelimit.IdentityIP, c.ClientIP()
 }
 
-// Cleanup periodically removes old clients from the rate limiter
-func (rl *RateLimiter) Cleanup(interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	go func() {
-		for range ticker.C {
-			rl.mu.Lock()
-			now := time.Now()
-			cleanedCount := 0
-			for clientID, client := range rl.clients {
-				// Remove clients that haven't been seen in a while
-				if now.Sub(client.lastSeen) > rl.window*2 {
-					delete(rl.clients, clientID)
-					cleanedCount++
-				}
-			}
-			totalClients := len(rl.clients)
-			rl.mu.Unlock()
-
-			if cleanedCount > 0 {
-				utils.Info("Rate limiter cleanup: removed %d stale clients, %d remaining",
-					cleanedCount, totalClients)
-			}
-		}
-	}()
+// ceilSeconds rounds a duration up to whole seconds for rate limit headers
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
@@ -0,0 +1,113 @@
+package middleware_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/dksensei/letsnormalizeit/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newRateLimitedRouter serves GET / behind a policy allowing two requests a
+// minute. The X-Test-UID header stands in for the uid earlier
+// authentication middleware would set.
+func newRateLimitedRouter(t *testing.T, identity string) *gin.Engine {
+	t.Helper()
+
+	policy, err := ratelimit.NewPolicy("test", config.RateLimitPolicyConfig{Requests: 2, Window: time.Minute, Identity: identity})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if uid := c.GetHeader("X-Test-UID"); uid != "" {
+			c.Set("uid", uid)
+		}
+	})
+	router.Use(middleware.RateLimit(ratelimit.NewMemoryLimiter(), policy))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestRateLimitIdentity(t *testing.T) {
+	cases := []struct {
+		name     string
+		identity string
+		// headers returns the headers of the i-th request
+		headers func(i int) map[string]string
+		want    []int
+	}{
+		{
+			name:     "Users",
+			identity: "uid",
+			headers: func(i int) map[string]string {
+				return map[string]string{"X-Test-UID": fmt.Sprintf("user-%d", i%2)}
+			},
+			want: []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:     "AnonymousFallsBackToIP",
+			identity: "uid,ip",
+			headers:  func(i int) map[string]string { return nil },
+			want:     []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newRateLimitedRouter(t, tc.identity)
+
+			for i, want := range tc.want {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				for name, value := range tc.headers(i) {
+					req.Header.Set(name, value)
+				}
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, req)
+
+				if recorder.Code != want {
+					t.Fatalf("request %d: status = %d, want %d", i+1, recorder.Code, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	router := newRateLimitedRouter(t, "ip")
+
+	for i, want := range []struct {
+		status     int
+		remaining  string
+		retryAfter string
+	}{
+		{http.StatusOK, "1", ""},
+		{http.StatusOK, "0", ""},
+		{http.StatusTooManyRequests, "0", "30"},
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if recorder.Code != want.status {
+			t.Errorf("request %d: status = %d, want %d", i+1, recorder.Code, want.status)
+		}
+		if got := recorder.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: RateLimit-Limit = %q, want 2", i+1, got)
+		}
+		if got := recorder.Header().Get("RateLimit-Remaining"); got != want.remaining {
+			t.Errorf("request %d: RateLimit-Remaining = %q, want %s", i+1, got, want.remaining)
+		}
+		if got := recorder.Header().Get("Retry-After"); got != want.retryAfter {
+			t.Errorf("request %d: Retry-After = %q, want %q", i+1, got, want.retryAfter)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RolesKey is the gin context key holding the authenticated user's []model.Role
+const RolesKey = "roles"
+
+// LoadRoles resolves the roles of the authenticated user, if any, and stores
+// them in the context for handlers to build their model.Viewer from. Users
+// the resolver forbids, such as disabled users, are rejected before any
+// handler runs; other failures are logged and the request continues without
+// any roles.
+func LoadRoles(resolver model.RoleResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := loadRoles(c, resolver); err != nil {
+			logger := utils.FromContext(c.Request.Context())
+			if apperror.IsKind(err, apperror.KindForbidden) {
+				logger.Warn("User rejected: %v", err)
+				apperror.Respond(c, err)
+				return
+			}
+			logger.Warn("Failed to resolve user roles: %v", err)
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission ensures the authenticated user has a role granting the
+// permission. It must run after AuthMiddleware.
+func RequirePermission(resolver model.RoleResolver, permission model.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := utils.FromContext(c.Request.Context()).With("permission", permission)
+
+		if c.GetString("uid") == "" {
+			apperror.Respond(c, apperror.ErrUnauthenticated)
+			return
+		}
+
+		roles, err := loadRoles(c, resolver)
+		if apperror.IsKind(err, apperror.KindForbidden) {
+			logger.Warn("User rejected: %v", err)
+			apperror.Respond(c, err)
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to resolve user roles: %v", err)
+			apperror.Respond(c, apperror.Wrap(err, apperror.KindInternal, "role_lookup_failed", "failed to resolve user roles"))
+			return
+		}
+
+		if !model.HasPermission(roles, permission) {
+			logger.Warn("Permission denied")
+			apperror.Respond(c, apperror.ErrPermissionDenied)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetRoles retrieves the roles resolved by LoadRoles or RequirePermission
+func GetRoles(c *gin.Context) []model.Role {
+	roles, _ := c.Get(RolesKey)
+	list, _ := roles.([]model.Role)
+	return list
+}
+
+// GetViewer builds the model.Viewer of the request from the uid set by the
+// auth middleware and the roles resolved by LoadRoles or RequirePermission
+func GetViewer(c *gin.Context) model.Viewer {
+	return model.Viewer{
+		UserID: c.GetString("uid"),
+		Roles:  GetRoles(c),
+	}
+}
+
+// loadRoles returns the roles already resolved by an earlier middleware or
+// looks them up and stores them. Roles always come from the user's record,
+// never from token claims, so demoting or disabling a user takes effect on
+// their next request.
+func loadRoles(c *gin.Context, resolver model.RoleResolver) ([]model.Role, error) {
+	if roles, exists := c.Get(RolesKey); exists {
+		return roles.([]model.Role), nil
+	}
+
+	uid := c.GetString("uid")
+	if uid == "" {
+		return nil, nil
+	}
+
+	roles, err := resolver.GetUserRoles(c.Request.Context(), uid)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Set(RolesKey, roles)
+	return roles, nil
+}
@@ -2,9 +2,9 @@ package middleware
 
 import (
 	"fmt"
-	"net/http"
 	"runtime/debug"
 
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
 	"github.com/dksensei/letsnormalizeit/internal/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -14,21 +14,16 @@ func Recovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				// Create a logger with request context
-				logger := utils.With(
-					"path", c.Request.URL.Path,
-					"method", c.Request.Method,
-					"clientIP", c.ClientIP(),
+				// Use the request's logger, which carries its request ID
+				logger := utils.FromContext(c.Request.Context()).With(
 					"userAgent", c.Request.UserAgent(),
 				)
 
 				// Log the error and stack trace with context
 				logger.Error("Panic recovered: %v\nStack trace: %s", err, debug.Stack())
 
-				// Return an error response
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-					"error": fmt.Sprintf("Internal server error: %v", err),
-				})
+				// Return an error response without the panic's details
+				apperror.Respond(c, fmt.Errorf("panic: %v", err))
 			}
 		}()
 
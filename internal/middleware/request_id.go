@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header carrying the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the gin context key holding the request ID
+const RequestIDKey = "requestID"
+
+// validRequestID matches request IDs accepted from clients and proxies
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID assigns every request an ID, honouring a well-formed
+// X-Request-ID sent by the client or a proxy, and echoes it in the response.
+// It stores a logger carrying the request ID, path, method and client IP in
+// the request context, where handlers and services get it with
+// utils.FromContext, plus the trace ID when the request is traced. It should
+// run right after Recovery and Tracing.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		fields := []interface{}{
+			"requestID", requestID,
+			"path", c.Request.URL.Path,
+			"method", c.Request.Method,
+			"clientIP", c.ClientIP(),
+		}
+		// Correlate logs and traces both ways when the request is traced
+		if span := trace.SpanFromContext(c.Request.Context()); span.SpanContext().IsValid() {
+			span.SetAttributes(attribute.String("request.id", requestID))
+			fields = append(fields, "traceID", span.SpanContext().TraceID().String())
+		}
+		logger := utils.NewLogContext(fields...)
+		c.Request = c.Request.WithContext(utils.ContextWithLogger(c.Request.Context(), logger))
+
+		c.Next()
+	}
+}
+
+// GetRequestID retrieves the request ID assigned by RequestID
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
@@ -0,0 +1,63 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		// kept reports whether the header is used as the request ID
+		kept bool
+	}{
+		{name: "UUID", header: "0b6f8c4e-3a52-4d4e-9a55-2f0c6f1e9d3a", kept: true},
+		{name: "EqualsSign", header: "Root=1-67891233-abcdef012345678912345678:web.1", kept: false},
+		{name: "Punctuation", header: "req_42.retry-1:a", kept: true},
+		{name: "MaxLength", header: strings.Repeat("a", 128), kept: true},
+		{name: "Missing", header: "", kept: false},
+		{name: "TooLong", header: strings.Repeat("a", 129), kept: false},
+		{name: "Spaces", header: "two words", kept: false},
+		{name: "Markup", header: "<script>alert(1)</script>", kept: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var seen string
+			router := gin.New()
+			router.Use(middleware.RequestID())
+			router.GET("/", func(c *gin.Context) {
+				seen = middleware.GetRequestID(c)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set(middleware.RequestIDHeader, tc.header)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			echoed := recorder.Header().Get(middleware.RequestIDHeader)
+			if echoed != seen {
+				t.Errorf("echoed request ID %q, handler saw %q", echoed, seen)
+			}
+			if tc.kept {
+				if seen != tc.header {
+					t.Errorf("request ID = %q, want %q", seen, tc.header)
+				}
+				return
+			}
+			if _, err := uuid.Parse(seen); err != nil {
+				t.Errorf("request ID = %q, want a generated UUID", seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// Tracing creates a middleware that records a span for each request, named
+// after its route template, and continues traces of callers that send a W3C
+// traceparent header. Spans go to the global tracer provider, so requests
+// are not recorded unless tracing.Setup enabled it. It must run before
+// RequestID so request logs carry the trace ID.
+func Tracing(cfg *config.TracingConfig) gin.HandlerFunc {
+	excluded := make(map[string]bool, len(cfg.ExcludePaths))
+	for _, path := range cfg.ExcludePaths {
+		excluded[path] = true
+	}
+
+	return otelgin.Middleware(cfg.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !excluded[r.URL.Path]
+	}))
+}
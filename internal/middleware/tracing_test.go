@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/dksensei/letsnormalizeit/internal/tracing"
+)
+
+func TestTracing(t *testing.T) {
+	const (
+		callerTrace = "4bf92f3577b34da6a3ce929d0e0e4736"
+		callerSpan  = "00f067aa0ba902b7"
+	)
+
+	cases := []struct {
+		name        string
+		path        string
+		traceparent string
+		// wantSpans is how many spans the request records, the request's
+		// own and the one the handler starts
+		wantSpans int
+		// wantCaller reports whether the request continues the caller's trace
+		wantCaller bool
+	}{
+		{name: "NewTrace", path: "/blogs/42", wantSpans: 2},
+		{name: "Traceparent", path: "/blogs/42", traceparent: "00-" + callerTrace + "-" + callerSpan + "-01", wantSpans: 2, wantCaller: true},
+		{name: "MalformedTraceparent", path: "/blogs/42", traceparent: "00-not-a-trace-01", wantSpans: 2},
+		{name: "Excluded", path: "/health", wantSpans: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The middleware reads the globals once when it is created
+			recorder := tracetest.NewSpanRecorder()
+			previousProvider := otel.GetTracerProvider()
+			previousPropagator := otel.GetTextMapPropagator()
+			otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+			otel.SetTextMapPropagator(propagation.TraceContext{})
+			t.Cleanup(func() {
+				otel.SetTracerProvider(previousProvider)
+				otel.SetTextMapPropagator(previousPropagator)
+			})
+
+			var outgoing http.Header
+			router := gin.New()
+			router.Use(middleware.Tracing(&config.TracingConfig{
+				ServiceName:  "letsnormalizeit-test",
+				ExcludePaths: []string{"/health"},
+			}))
+			handler := func(c *gin.Context) {
+				ctx, span := tracing.Start(c.Request.Context(), "handler")
+				outgoing = make(http.Header)
+				otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(outgoing))
+				tracing.End(span, nil)
+				c.Status(http.StatusOK)
+			}
+			router.GET("/blogs/:id", handler)
+			router.GET("/health", handler)
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.traceparent != "" {
+				req.Header.Set("traceparent", tc.traceparent)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			ended := recorder.Ended()
+			if len(ended) != tc.wantSpans {
+				t.Fatalf("recorded %d spans, want %d", len(ended), tc.wantSpans)
+			}
+			if tc.wantSpans == 1 {
+				return
+			}
+
+			inner, request := ended[0], ended[1]
+			if request.Name() != "/blogs/:id" {
+				t.Errorf("request span name = %q, want the route template", request.Name())
+			}
+			if request.SpanKind() != trace.SpanKindServer {
+				t.Errorf("request span kind = %s, want %s", request.SpanKind(), trace.SpanKindServer)
+			}
+			if inner.Parent().SpanID() != request.SpanContext().SpanID() {
+				t.Error("handler span is not a child of the request span")
+			}
+
+			traceID := request.SpanContext().TraceID().String()
+			if tc.wantCaller {
+				if traceID != callerTrace {
+					t.Errorf("trace ID = %s, want the caller's %s", traceID, callerTrace)
+				}
+				if got := request.Parent().SpanID().String(); got != callerSpan {
+					t.Errorf("request span parent = %s, want the caller's %s", got, callerSpan)
+				}
+				if !request.Parent().IsRemote() {
+					t.Error("request span parent is not marked remote")
+				}
+			} else if traceID == callerTrace || request.Parent().IsValid() {
+				t.Errorf("request continued a trace it was not given: parent %s", request.Parent().SpanID())
+			}
+
+			// Calls made from the handler carry the trace onwards
+			want := "00-" + traceID + "-" + inner.SpanContext().SpanID().String() + "-01"
+			if got := outgoing.Get("traceparent"); got != want {
+				t.Errorf("outgoing traceparent = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Package migrate applies versioned changes to the MongoDB schema, such as
+// the indexes the repositories rely on. Applied versions are recorded in the
+// schema_migrations collection, so each migration runs once per database.
+//
+// Migrations are not applied in a transaction: a migration that fails halfway
+// is retried from the start on the next run, so Up and Down must be safe to
+// run again.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/db"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CollectionName is the collection recording applied migrations
+const CollectionName = "schema_migrations"
+
+// Migration is a versioned schema change
+type Migration struct {
+	// Version orders migrations; it must be positive and never reused
+	Version int
+	// Description says what the migration changes
+	Description string
+	// Up applies the change
+	Up func(ctx context.Context, database *mongo.Database) error
+	// Down reverts the change
+	Down func(ctx context.Context, database *mongo.Database) error
+}
+
+// record is an applied migration as stored in schema_migrations
+type record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// Status describes a migration known to the binary, the database or both
+type Status struct {
+	Version     int
+	Description string
+	// Applied reports whether the database records the migration
+	Applied   bool
+	AppliedAt time.Time
+	// Unknown reports an applied migration this binary does not define,
+	// e.g. one added by a newer release
+	Unknown bool
+}
+
+// Migrator applies migrations to a database
+type Migrator struct {
+	db         *db.MongoDB
+	migrations []*Migration
+}
+
+// New creates a migrator for the given migrations, which must have distinct
+// positive versions and both an Up and a Down function
+func New(mongodb *db.MongoDB, migrations []*Migration) (*Migrator, error) {
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, migration := range sorted {
+		if migration.Version <= 0 {
+			return nil, fmt.Errorf("migration %q: version must be positive", migration.Description)
+		}
+		if i > 0 && sorted[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("duplicate migration version %d", migration.Version)
+		}
+		if migration.Up == nil || migration.Down == nil {
+			return nil, fmt.Errorf("migration %d: both Up and Down are required", migration.Version)
+		}
+	}
+
+	return &Migrator{db: mongodb, migrations: sorted}, nil
+}
+
+// Status lists every migration in version order with whether it is applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Description: migration.Description}
+		if rec, ok := applied[migration.Version]; ok {
+			status.Applied, status.AppliedAt = true, rec.AppliedAt
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, rec := range applied {
+		statuses = append(statuses, Status{
+			Version:     rec.Version,
+			Description: rec.Description,
+			Applied:     true,
+			AppliedAt:   rec.AppliedAt,
+			Unknown:     true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Pending lists the migrations not applied yet, in the order Up applies them
+func (m *Migrator) Pending(ctx context.Context) ([]*Migration, error) {
+	return m.Up(ctx, 0, true)
+}
+
+// Up applies every pending migration up to and including version target,
+// or all of them when target is 0, in version order. It returns the
+// migrations applied; with dryRun set it returns those it would apply
+// without changing anything. It stops at the first failure.
+func (m *Migrator) Up(ctx context.Context, target int, dryRun bool) ([]*Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan []*Migration
+	for _, migration := range m.migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; !ok {
+			plan = append(plan, migration)
+		}
+	}
+	if dryRun {
+		return plan, nil
+	}
+
+	coll := m.db.GetCollection(CollectionName)
+	for i, migration := range plan {
+		utils.Info("Applying migration %d: %s", migration.Version, migration.Description)
+		if err := migration.Up(ctx, m.db.Database); err != nil {
+			return plan[:i], fmt.Errorf("apply migration %d: %w", migration.Version, err)
+		}
+
+		rec := record{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now()}
+		_, err := coll.ReplaceOne(ctx, bson.M{"_id": rec.Version}, rec, options.Replace().SetUpsert(true))
+		if err != nil {
+			return plan[:i], fmt.Errorf("record migration %d: %w", migration.Version, err)
+		}
+	}
+	return plan, nil
+}
+
+// Down reverts every applied migration above version target, newest first,
+// so target 0 reverts all of them. It returns the migrations reverted; with
+// dryRun set it returns those it would revert without changing anything. It
+// fails without reverting anything when an applied migration above target
+// is unknown to this binary.
+func (m *Migrator) Down(ctx context.Context, target int, dryRun bool) ([]*Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[int]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+	for version := range applied {
+		if version > target && !known[version] {
+			return nil, fmt.Errorf("migration %d is applied but unknown to this binary", version)
+		}
+	}
+
+	var plan []*Migration
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version <= target {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			plan = append(plan, migration)
+		}
+	}
+	if dryRun {
+		return plan, nil
+	}
+
+	coll := m.db.GetCollection(CollectionName)
+	for i, migration := range plan {
+		utils.Info("Reverting migration %d: %s", migration.Version, migration.Description)
+		if err := migration.Down(ctx, m.db.Database); err != nil {
+			return plan[:i], fmt.Errorf("revert migration %d: %w", migration.Version, err)
+		}
+
+		if _, err := coll.DeleteOne(ctx, bson.M{"_id": migration.Version}); err != nil {
+			return plan[:i], fmt.Errorf("unrecord migration %d: %w", migration.Version, err)
+		}
+	}
+	return plan, nil
+}
+
+// applied loads the recorded migrations by version
+func (m *Migrator) applied(ctx context.Context) (map[int]record, error) {
+	coll := m.db.GetCollection(CollectionName)
+
+	cursor, err := coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	records := []record{}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]record, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = rec
+	}
+	return applied, nil
+}
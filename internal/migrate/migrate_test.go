@@ -0,0 +1,178 @@
+package migrate_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/db/dbtest"
+	"github.com/dksensei/letsnormalizeit/internal/migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestNew(t *testing.T) {
+	noop := func(ctx context.Context, database *mongo.Database) error { return nil }
+
+	cases := map[string][]*migrate.Migration{
+		"ZeroVersion":      {{Version: 0, Up: noop, Down: noop}},
+		"DuplicateVersion": {{Version: 1, Up: noop, Down: noop}, {Version: 1, Up: noop, Down: noop}},
+		"MissingDown":      {{Version: 1, Up: noop}},
+	}
+	for name, migrations := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := migrate.New(nil, migrations); err == nil {
+				t.Error("New() succeeded, want an error")
+			}
+		})
+	}
+
+	if _, err := migrate.New(nil, migrate.Migrations()); err != nil {
+		t.Errorf("New(Migrations()) error = %v", err)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	// dbtest applies every migration to the database it creates
+	mongodb := dbtest.NewMongoDB(t)
+	migrator, err := migrate.New(mongodb, migrate.Migrations())
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := migrate.Migrations()[len(migrate.Migrations())-1].Version
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.Unknown {
+			t.Errorf("migration %d: Applied = %v, Unknown = %v, want applied and known", status.Version, status.Applied, status.Unknown)
+		}
+	}
+	if !slices.Contains(indexNames(t, mongodb.Database, "users"), "email_1") {
+		t.Error("users.email index missing")
+	}
+
+	// A dry run changes nothing
+	planned, err := migrator.Down(ctx, 0, true)
+	if err != nil {
+		t.Fatalf("Down(dry run) error = %v", err)
+	}
+	assertVersions(t, planned, descending(latest, 1))
+	assertPending(t, migrator, nil)
+
+	reverted, err := migrator.Down(ctx, 0, false)
+	if err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	assertVersions(t, reverted, descending(latest, 1))
+	assertPending(t, migrator, descending(latest, 1))
+	if slices.Contains(indexNames(t, mongodb.Database, "users"), "email_1") {
+		t.Error("users.email index not dropped")
+	}
+
+	// Reverting again is a no-op
+	reverted, err = migrator.Down(ctx, 0, false)
+	if err != nil {
+		t.Fatalf("Down() again error = %v", err)
+	}
+	assertVersions(t, reverted, nil)
+
+	applied, err := migrator.Up(ctx, 2, false)
+	if err != nil {
+		t.Fatalf("Up(2) error = %v", err)
+	}
+	assertVersions(t, applied, []int{1, 2})
+
+	applied, err = migrator.Up(ctx, 0, false)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	assertVersions(t, applied, ascending(3, latest))
+	assertPending(t, migrator, nil)
+	if !slices.Contains(indexNames(t, mongodb.Database, "comments"), "parent_id_1_created_at_1__id_1") {
+		t.Error("comments.parent_id index missing")
+	}
+}
+
+func TestMigratorUnknownVersion(t *testing.T) {
+	ctx := context.Background()
+	mongodb := dbtest.NewMongoDB(t)
+	migrator, err := migrate.New(mongodb, migrate.Migrations())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A migration recorded by a newer release
+	_, err = mongodb.GetCollection(migrate.CollectionName).InsertOne(ctx, bson.M{"_id": 1000, "description": "from the future"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	last := statuses[len(statuses)-1]
+	if last.Version != 1000 || !last.Unknown || !last.Applied {
+		t.Errorf("last status = %+v, want applied unknown version 1000", last)
+	}
+
+	if reverted, err := migrator.Down(ctx, 0, false); err == nil || len(reverted) > 0 {
+		t.Errorf("Down() = %d reverted, %v, want an error and nothing reverted", len(reverted), err)
+	}
+	assertPending(t, migrator, nil)
+}
+
+func indexNames(t *testing.T, database *mongo.Database, collection string) []string {
+	t.Helper()
+
+	specs, err := database.Collection(collection).Indexes().ListSpecifications(context.Background())
+	if err != nil {
+		t.Fatalf("list %s indexes: %v", collection, err)
+	}
+	names := make([]string, len(specs))
+	for i, spec := range specs {
+		names[i] = spec.Name
+	}
+	return names
+}
+
+func assertPending(t *testing.T, migrator *migrate.Migrator, want []int) {
+	t.Helper()
+
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+	slices.Sort(want)
+	assertVersions(t, pending, want)
+}
+
+func assertVersions(t *testing.T, migrations []*migrate.Migration, want []int) {
+	t.Helper()
+
+	got := make([]int, len(migrations))
+	for i, migration := range migrations {
+		got[i] = migration.Version
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("versions = %v, want %v", got, want)
+	}
+}
+
+func ascending(from, to int) []int {
+	versions := []int{}
+	for v := from; v <= to; v++ {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+func descending(from, to int) []int {
+	versions := ascending(to, from)
+	slices.Reverse(versions)
+	return versions
+}
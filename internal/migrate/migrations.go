@@ -0,0 +1,138 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migrations returns the schema migrations of the application in version
+// order. Migrations are append-only: released ones must not change, so
+// they spell out collection and field names rather than sharing the
+// repositories' constants.
+//
+// Indexes keep MongoDB's default names, matching the indexes the server
+// used to create at startup, so applying these to an existing database
+// does not fail on or duplicate them.
+func Migrations() []*Migration {
+	return []*Migration{
+		{
+			Version:     1,
+			Description: "unique index on users.email",
+			// Only non-empty strings are indexed, so users without an email
+			// do not collide
+			Up: createIndexes("users", mongo.IndexModel{
+				Keys: bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetUnique(true).
+					SetPartialFilterExpression(bson.M{"email": bson.M{"$gt": ""}}),
+			}),
+			Down: dropIndexes("users", bson.D{{Key: "email", Value: 1}}),
+		},
+		{
+			Version:     2,
+			Description: "blog listing indexes by status, tag, author, date and popularity",
+			Up:          createIndexes("blogs", indexModels(blogIndexes)...),
+			Down:        dropIndexes("blogs", blogIndexes...),
+		},
+		{
+			Version:     3,
+			Description: "unique index on blog_revisions (blog_id, version)",
+			// Also rejects two edits racing to save the same version
+			Up: createIndexes("blog_revisions", mongo.IndexModel{
+				Keys:    bson.D{{Key: "blog_id", Value: 1}, {Key: "version", Value: -1}},
+				Options: options.Index().SetUnique(true),
+			}),
+			Down: dropIndexes("blog_revisions", bson.D{{Key: "blog_id", Value: 1}, {Key: "version", Value: -1}}),
+		},
+		{
+			Version:     4,
+			Description: "index on blog_slugs.blog_id",
+			Up:          createIndexes("blog_slugs", mongo.IndexModel{Keys: bson.D{{Key: "blog_id", Value: 1}}}),
+			Down:        dropIndexes("blog_slugs", bson.D{{Key: "blog_id", Value: 1}}),
+		},
+		{
+			Version:     5,
+			Description: "comment thread indexes by blog_id and parent_id",
+			Up:          createIndexes("comments", indexModels(commentIndexes)...),
+			Down:        dropIndexes("comments", commentIndexes...),
+		},
+	}
+}
+
+// blogIndexes back every blog listing filter and sort order. Each leads with
+// status and ends with the sort key and _id so cursor pagination is an index
+// range scan.
+var blogIndexes = []bson.D{
+	{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	{{Key: "status", Value: 1}, {Key: "tags", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	{{Key: "status", Value: 1}, {Key: "author_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	{{Key: "author_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	{{Key: "status", Value: 1}, {Key: "like_count", Value: -1}, {Key: "_id", Value: -1}},
+	{{Key: "status", Value: 1}, {Key: "comment_count", Value: -1}, {Key: "_id", Value: -1}},
+	{{Key: "status", Value: 1}, {Key: "publish_at", Value: 1}},
+}
+
+// commentIndexes back listing a blog's top-level comments and a comment's
+// replies, both oldest first
+var commentIndexes = []bson.D{
+	{{Key: "blog_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+	{{Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}},
+}
+
+// MongoDB error codes tolerated when dropping indexes
+const (
+	codeNamespaceNotFound = 26
+	codeIndexNotFound     = 27
+)
+
+// indexModels creates plain index models for keys
+func indexModels(keys []bson.D) []mongo.IndexModel {
+	models := make([]mongo.IndexModel, len(keys))
+	for i, key := range keys {
+		models[i] = mongo.IndexModel{Keys: key}
+	}
+	return models
+}
+
+// createIndexes returns a migration step creating indexes on a collection.
+// Creating an index that already exists with the same options is a no-op.
+func createIndexes(collection string, models ...mongo.IndexModel) func(ctx context.Context, database *mongo.Database) error {
+	return func(ctx context.Context, database *mongo.Database) error {
+		_, err := database.Collection(collection).Indexes().CreateMany(ctx, models)
+		return err
+	}
+}
+
+// dropIndexes returns a migration step dropping the indexes with the given
+// keys from a collection, ignoring ones that do not exist
+func dropIndexes(collection string, keys ...bson.D) func(ctx context.Context, database *mongo.Database) error {
+	return func(ctx context.Context, database *mongo.Database) error {
+		indexes := database.Collection(collection).Indexes()
+		for _, key := range keys {
+			_, err := indexes.DropOne(ctx, indexName(key))
+			var cmdErr mongo.CommandError
+			if errors.As(err, &cmdErr) && (cmdErr.Code == codeIndexNotFound || cmdErr.Code == codeNamespaceNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// indexName returns the name MongoDB gives an index on keys when none is
+// set, e.g. status_1_created_at_-1
+func indexName(keys bson.D) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s_%v", key.Key, key.Value))
+	}
+	return strings.Join(parts, "_")
+}
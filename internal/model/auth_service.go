@@ -8,9 +8,25 @@ import (
 
 // AuthService defines the interface for authentication services
 type AuthService interface {
-	// VerifyToken verifies the Firebase ID token and returns the token claims
+	// VerifyToken verifies the ID token's signature and expiry and returns
+	// the token claims. It may skip the revocation check, which can cost a
+	// round trip to the auth provider.
 	VerifyToken(ctx context.Context, idToken string) (*auth.Token, error)
 
+	// VerifyTokenAndCheckRevoked verifies the ID token like VerifyToken and
+	// also rejects tokens of disabled users and tokens issued before a
+	// revocation
+	VerifyTokenAndCheckRevoked(ctx context.Context, idToken string) (*auth.Token, error)
+
 	// GetUser gets a user by their UID
 	GetUser(ctx context.Context, uid string) (*auth.UserRecord, error)
+
+	// SetCustomUserClaims replaces the custom claims of a user
+	SetCustomUserClaims(ctx context.Context, uid string, claims map[string]interface{}) error
+
+	// SetUserDisabled disables or enables a user's account
+	SetUserDisabled(ctx context.Context, uid string, disabled bool) error
+
+	// RevokeRefreshTokens revokes all tokens issued to a user so far
+	RevokeRefreshTokens(ctx context.Context, uid string) error
 }
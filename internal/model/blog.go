@@ -6,22 +6,86 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Blog represents a blog post in the system
+// BlogStatus is the lifecycle state of a blog post
+type BlogStatus string
+
+const (
+	// BlogStatusDraft is only visible to the author and admins
+	BlogStatusDraft BlogStatus = "draft"
+	// BlogStatusScheduled is published automatically at PublishAt
+	BlogStatusScheduled BlogStatus = "scheduled"
+	// BlogStatusPublished is listed publicly
+	BlogStatusPublished BlogStatus = "published"
+	// BlogStatusUnlisted is readable by anyone with the link but not listed
+	BlogStatusUnlisted BlogStatus = "unlisted"
+	// BlogStatusArchived is withdrawn and only visible to the author and admins
+	BlogStatusArchived BlogStatus = "archived"
+)
+
+// Valid reports whether s is a known blog status
+func (s BlogStatus) Valid() bool {
+	switch s {
+	case BlogStatusDraft, BlogStatusScheduled, BlogStatusPublished, BlogStatusUnlisted, BlogStatusArchived:
+		return true
+	}
+	return false
+}
+
+// IsPublic reports whether a blog in this status can be read by anyone
+func (s BlogStatus) IsPublic() bool {
+	return s == BlogStatusPublished || s == BlogStatusUnlisted
+}
+
+// Viewer identifies who is reading blogs; the zero value is an anonymous reader
+type Viewer struct {
+	UserID string
+	Roles  []Role
+}
+
+// Can reports whether the viewer's roles grant a permission
+func (v Viewer) Can(permission Permission) bool {
+	return HasPermission(v.Roles, permission)
+}
+
+// IsAuthor reports whether the viewer is the given author
+func (v Viewer) IsAuthor(authorID string) bool {
+	return v.UserID != "" && v.UserID == authorID
+}
+
+// CanView reports whether the viewer may read the given author's unpublished blogs
+func (v Viewer) CanView(authorID string) bool {
+	return v.IsAuthor(authorID) || v.Can(PermissionViewUnpublished)
+}
+
+// CanManage reports whether the viewer may edit the given author's blogs
+func (v Viewer) CanManage(authorID string) bool {
+	return v.IsAuthor(authorID) || v.Can(PermissionEditAnyBlog)
+}
+
+// Blog represents a blog post in the system. The IDs of users who liked or
+// bookmarked it are never serialized to JSON, so they stay out of responses
+// and cached copies; only the counts are exposed.
 type Blog struct {
-	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Title        string             `json:"title" bson:"title"`
-	Content      string             `json:"content" bson:"content"`
-	AuthorID     string             `json:"author_id" bson:"author_id"`
-	Tags         []string           `json:"tags" bson:"tags"`
-	ImageURL     string             `json:"image_url" bson:"image_url"`
-	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
-	Likes        []string           `json:"likes" bson:"likes"`
-	BookmarkedBy []string           `json:"bookmarked_by" bson:"bookmarked_by"`
-	IsPublished  bool               `json:"is_published" bson:"is_published"`
-}
-
-// NewBlog creates a new blog post
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Title         string             `json:"title" bson:"title"`
+	Slug          string             `json:"slug" bson:"slug,omitempty"`
+	Content       string             `json:"content" bson:"content"`
+	AuthorID      string             `json:"author_id" bson:"author_id"`
+	Tags          []string           `json:"tags" bson:"tags"`
+	ImageURL      string             `json:"image_url" bson:"image_url"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
+	Likes         []string           `json:"-" bson:"likes"`
+	BookmarkedBy  []string           `json:"-" bson:"bookmarked_by"`
+	LikeCount     int64              `json:"like_count" bson:"like_count"`
+	BookmarkCount int64              `json:"bookmark_count" bson:"bookmark_count"`
+	CommentCount  int64              `json:"comment_count" bson:"comment_count"`
+	Status        BlogStatus         `json:"status" bson:"status"`
+	PublishAt     *time.Time         `json:"publish_at,omitempty" bson:"publish_at,omitempty"`
+	Version       int                `json:"version" bson:"version"`
+}
+
+// NewBlog creates a new draft blog post
 func NewBlog(title, content, authorID string, tags []string, imageURL string) *Blog {
 	now := time.Now()
 	return &Blog{
@@ -34,6 +98,172 @@ func NewBlog(title, content, authorID string, tags []string, imageURL string) *B
 		UpdatedAt:    now,
 		Likes:        []string{},
 		BookmarkedBy: []string{},
-		IsPublished:  true,
+		Status:       BlogStatusDraft,
+		Version:      1,
+	}
+}
+
+// ReactionResult describes a user's like and bookmark state on a blog after a toggle
+type ReactionResult struct {
+	BlogID        string `json:"blog_id"`
+	Liked         bool   `json:"liked"`
+	Bookmarked    bool   `json:"bookmarked"`
+	LikeCount     int64  `json:"like_count"`
+	BookmarkCount int64  `json:"bookmark_count"`
+}
+
+// NewReactionResult builds the reaction state of a blog as seen by the given user
+func NewReactionResult(blog *Blog, userID string) *ReactionResult {
+	result := &ReactionResult{
+		BlogID:        blog.ID.Hex(),
+		LikeCount:     blog.LikeCount,
+		BookmarkCount: blog.BookmarkCount,
+	}
+	for _, id := range blog.Likes {
+		if id == userID {
+			result.Liked = true
+			break
+		}
+	}
+	for _, id := range blog.BookmarkedBy {
+		if id == userID {
+			result.Bookmarked = true
+			break
+		}
 	}
+	return result
+}
+
+// BlogSort is the ordering of a blog listing
+type BlogSort string
+
+const (
+	// BlogSortNewest orders blogs by creation time, newest first
+	BlogSortNewest BlogSort = "newest"
+	// BlogSortMostLiked orders blogs by like count, highest first
+	BlogSortMostLiked BlogSort = "most_liked"
+	// BlogSortMostCommented orders blogs by comment count, highest first
+	BlogSortMostCommented BlogSort = "most_commented"
+)
+
+// BlogListFilter selects and orders a page of blogs
+type BlogListFilter struct {
+	Tag      string
+	AuthorID string
+	// Status filters on lifecycle state; empty lists every state
+	Status BlogStatus
+	// From and To bound created_at (inclusive, exclusive); zero means unbounded
+	From   time.Time
+	To     time.Time
+	Sort   BlogSort
+	Cursor string
+	Limit  int64
+}
+
+// BlogPage is a page of blogs with the cursor of the next page
+type BlogPage struct {
+	Blogs []*Blog `json:"blogs"`
+	// NextCursor is empty on the last page
+	NextCursor string `json:"next_cursor"`
+}
+
+// BlogInput represents the input for creating a blog post
+type BlogInput struct {
+	Title    string   `json:"title" binding:"required"`
+	Content  string   `json:"content" binding:"required"`
+	Tags     []string `json:"tags"`
+	ImageURL string   `json:"image_url"`
+	// Status defaults to published; scheduled requires PublishAt
+	Status    BlogStatus `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// BlogUpdateInput represents the input for updating a blog post.
+// Nil fields are left unchanged.
+type BlogUpdateInput struct {
+	Title     *string     `json:"title"`
+	Content   *string     `json:"content"`
+	Tags      *[]string   `json:"tags"`
+	ImageURL  *string     `json:"image_url"`
+	Status    *BlogStatus `json:"status"`
+	PublishAt *time.Time  `json:"publish_at"`
+	// Version is the version the edit is based on. When set, the edit is
+	// rejected if the blog has been saved since.
+	Version *int `json:"version"`
+	// ChangeSummary describes the edit in the revision history
+	ChangeSummary string `json:"change_summary"`
+}
+
+// TOCEntry is a heading in a rendered blog's table of contents
+type TOCEntry struct {
+	Level  int    `json:"level"`
+	Title  string `json:"title"`
+	Anchor string `json:"anchor"`
+}
+
+// RenderedContent is the sanitized HTML rendering of a blog's markdown
+type RenderedContent struct {
+	HTML               string     `json:"html"`
+	TOC                []TOCEntry `json:"toc"`
+	ReadingTimeMinutes int        `json:"reading_time_minutes"`
+}
+
+// BlogRevision is a saved version of a blog post
+type BlogRevision struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	BlogID   primitive.ObjectID `json:"blog_id" bson:"blog_id"`
+	Version  int                `json:"version" bson:"version"`
+	Title    string             `json:"title" bson:"title"`
+	Content  string             `json:"content" bson:"content"`
+	Tags     []string           `json:"tags" bson:"tags"`
+	ImageURL string             `json:"image_url" bson:"image_url"`
+	// EditorID is the user who saved this version
+	EditorID  string    `json:"editor_id" bson:"editor_id"`
+	Summary   string    `json:"summary" bson:"summary"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
+
+// NewBlogRevision snapshots the current state of a blog
+func NewBlogRevision(blog *Blog, editorID, summary string) *BlogRevision {
+	return &BlogRevision{
+		BlogID:    blog.ID,
+		Version:   blog.Version,
+		Title:     blog.Title,
+		Content:   blog.Content,
+		Tags:      blog.Tags,
+		ImageURL:  blog.ImageURL,
+		EditorID:  editorID,
+		Summary:   summary,
+		CreatedAt: blog.UpdatedAt,
+	}
+}
+
+// DiffOp is the kind of change a diff line represents
+type DiffOp string
+
+const (
+	// DiffEqual marks a line present in both versions
+	DiffEqual DiffOp = "equal"
+	// DiffInsert marks a line only present in the newer version
+	DiffInsert DiffOp = "insert"
+	// DiffDelete marks a line only present in the older version
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffLine is one line of a line-level diff. Line numbers are 1-based and
+// zero on the side the line does not exist in.
+type DiffLine struct {
+	Op      DiffOp `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+}
+
+// RevisionDiff compares two revisions of a blog
+type RevisionDiff struct {
+	FromVersion int        `json:"from_version"`
+	ToVersion   int        `json:"to_version"`
+	OldTitle    string     `json:"old_title"`
+	NewTitle    string     `json:"new_title"`
+	Lines       []DiffLine `json:"lines"`
 }
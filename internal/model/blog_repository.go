@@ -0,0 +1,89 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BlogRepository defines the interface for blog storage. Implementations
+// return blog.ErrBlogNotFound for missing blogs and keep each reaction
+// counter equal to the size of its set.
+type BlogRepository interface {
+	// FindByID finds a blog by ID
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Blog, error)
+
+	// List lists a page of blogs matching the filter, continuing after its cursor
+	List(ctx context.Context, filter *BlogListFilter) (*BlogPage, error)
+
+	// ListPopular lists the most liked published blogs
+	ListPopular(ctx context.Context, limit int64) ([]*Blog, error)
+
+	// Create creates a new blog and sets its generated ID
+	Create(ctx context.Context, blog *Blog) error
+
+	// Update updates the editable fields of a blog if its stored version
+	// still equals expectedVersion, failing with blog.ErrVersionConflict
+	// otherwise
+	Update(ctx context.Context, blog *Blog, expectedVersion int) error
+
+	// PublishDue publishes every scheduled blog whose publish time has passed
+	// and returns the IDs it published
+	PublishDue(ctx context.Context, now time.Time) ([]primitive.ObjectID, error)
+
+	// CountByAuthor counts the blogs written by an author in any status
+	CountByAuthor(ctx context.Context, authorID string) (int64, error)
+
+	// Delete deletes a blog by ID
+	Delete(ctx context.Context, id primitive.ObjectID) error
+
+	// IncrementCommentCount adjusts a blog's comment count by delta
+	IncrementCommentCount(ctx context.Context, id primitive.ObjectID, delta int64) error
+
+	// ToggleLike adds or removes the user's like
+	ToggleLike(ctx context.Context, id primitive.ObjectID, userID string) (*Blog, error)
+
+	// ToggleBookmark adds or removes the user's bookmark
+	ToggleBookmark(ctx context.Context, id primitive.ObjectID, userID string) (*Blog, error)
+
+	// SetLike adds the user's like if liked is true and removes it otherwise;
+	// unlike ToggleLike, repeating it changes nothing
+	SetLike(ctx context.Context, id primitive.ObjectID, userID string, liked bool) (*Blog, error)
+
+	// SetBookmark adds the user's bookmark if bookmarked is true and removes
+	// it otherwise; unlike ToggleBookmark, repeating it changes nothing
+	SetBookmark(ctx context.Context, id primitive.ObjectID, userID string, bookmarked bool) (*Blog, error)
+}
+
+// BlogRevisionRepository defines the interface for blog revision storage.
+// Each (blog, version) pair is stored at most once.
+type BlogRevisionRepository interface {
+	// Create stores a revision and sets its generated ID
+	Create(ctx context.Context, revision *BlogRevision) error
+
+	// FindByVersion finds a single revision of a blog, failing with
+	// blog.ErrRevisionNotFound
+	FindByVersion(ctx context.Context, blogID primitive.ObjectID, version int) (*BlogRevision, error)
+
+	// ListByBlog lists a blog's revisions, newest first, without their content
+	ListByBlog(ctx context.Context, blogID primitive.ObjectID) ([]*BlogRevision, error)
+
+	// DeleteByBlog deletes every revision of a blog
+	DeleteByBlog(ctx context.Context, blogID primitive.ObjectID) error
+}
+
+// BlogSlugRepository defines the interface for the registry of blog slugs,
+// current and historical
+type BlogSlugRepository interface {
+	// Reserve claims a slug for a blog, failing with blog.ErrSlugTaken if
+	// another blog owns it
+	Reserve(ctx context.Context, slug string, blogID primitive.ObjectID) error
+
+	// FindBlogID finds the blog a slug belongs to, failing with
+	// blog.ErrBlogNotFound
+	FindBlogID(ctx context.Context, slug string) (primitive.ObjectID, error)
+
+	// DeleteByBlog releases every slug of a blog
+	DeleteByBlog(ctx context.Context, blogID primitive.ObjectID) error
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"context"
+)
+
+// BlogService defines the interface for blog-related services
+type BlogService interface {
+	// GetBlogByID gets a blog by ID
+	GetBlogByID(ctx context.Context, id string) (*Blog, error)
+
+	// GetVisibleBlog gets a blog if the viewer may read it
+	GetVisibleBlog(ctx context.Context, id string, viewer Viewer) (*Blog, error)
+
+	// GetBlogBySlug gets a blog the viewer may read by its current or a former slug
+	GetBlogBySlug(ctx context.Context, slug string, viewer Viewer) (*Blog, error)
+
+	// ListBlogs lists a page of blogs matching the filter as seen by the viewer
+	ListBlogs(ctx context.Context, viewer Viewer, filter *BlogListFilter) (*BlogPage, error)
+
+	// CountBlogsByAuthor counts the blogs written by an author in any status
+	CountBlogsByAuthor(ctx context.Context, authorID string) (int64, error)
+
+	// ListPopularBlogs lists the most liked published blogs
+	ListPopularBlogs(ctx context.Context) ([]*Blog, error)
+
+	// RenderBlog renders a blog's markdown to sanitized HTML
+	RenderBlog(ctx context.Context, blog *Blog) (*RenderedContent, error)
+
+	// CreateBlog creates a new blog authored by the given user
+	CreateBlog(ctx context.Context, authorID string, input *BlogInput) (*Blog, error)
+
+	// UpdateBlog updates a blog the viewer may edit
+	UpdateBlog(ctx context.Context, id string, viewer Viewer, input *BlogUpdateInput) (*Blog, error)
+
+	// DeleteBlog deletes a blog the viewer may delete
+	DeleteBlog(ctx context.Context, id string, viewer Viewer) error
+
+	// ToggleLike atomically flips the user's like on a blog
+	ToggleLike(ctx context.Context, id, userID string) (*Blog, error)
+
+	// ToggleBookmark atomically flips the user's bookmark on a blog
+	ToggleBookmark(ctx context.Context, id, userID string) (*Blog, error)
+
+	// SetLike adds or removes the user's like on a blog
+	SetLike(ctx context.Context, id, userID string, liked bool) (*Blog, error)
+
+	// SetBookmark adds or removes the user's bookmark on a blog
+	SetBookmark(ctx context.Context, id, userID string, bookmarked bool) (*Blog, error)
+
+	// ListRevisions lists a blog's revisions, newest first
+	ListRevisions(ctx context.Context, id string, viewer Viewer) ([]*BlogRevision, error)
+
+	// DiffRevisions computes a line-level diff between two revisions of a blog
+	DiffRevisions(ctx context.Context, id string, viewer Viewer, fromVersion, toVersion int) (*RevisionDiff, error)
+
+	// RestoreRevision restores an older revision of a blog as a new version,
+	// provided the blog is still at expectedVersion when that is set
+	RestoreRevision(ctx context.Context, id string, viewer Viewer, version int, expectedVersion *int) (*Blog, error)
+
+	// IncrementCommentCount adjusts a blog's comment count by delta
+	IncrementCommentCount(ctx context.Context, id string, delta int64) error
+}
@@ -31,3 +31,34 @@ func NewComment(blogID primitive.ObjectID, userID, content string, parentID prim
 		ParentID:  parentID,
 	}
 }
+
+// CommentInput represents the input for creating a comment
+type CommentInput struct {
+	BlogID   string `json:"blog_id" binding:"required"`
+	Content  string `json:"content" binding:"required"`
+	ParentID string `json:"parent_id"`
+}
+
+// CommentNode is a comment together with a page of its replies
+type CommentNode struct {
+	Comment        *Comment
+	Replies        []*CommentNode
+	ReplyCount     int64
+	HasMoreReplies bool
+}
+
+// CommentTreeOptions controls how much of a comment thread is returned
+type CommentTreeOptions struct {
+	// ParentID roots the tree at a comment; empty means top-level comments
+	ParentID     string
+	Depth        int
+	Limit        int64
+	Offset       int64
+	RepliesLimit int64
+}
+
+// CommentTree is a page of root comments with nested replies
+type CommentTree struct {
+	Comments []*CommentNode
+	Total    int64
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CommentRepository defines the interface for comment storage.
+// Implementations return comment.ErrCommentNotFound for missing comments and
+// order threads oldest first, breaking ties by ID.
+type CommentRepository interface {
+	// FindByID finds a comment by ID
+	FindByID(ctx context.Context, id primitive.ObjectID) (*Comment, error)
+
+	// Create creates a new comment and sets its generated ID
+	Create(ctx context.Context, comment *Comment) error
+
+	// FindRoots finds a page of top-level comments on a blog and counts them all
+	FindRoots(ctx context.Context, blogID primitive.ObjectID, limit, offset int64) ([]*Comment, int64, error)
+
+	// FindReplies finds a page of direct replies to a comment and counts them all
+	FindReplies(ctx context.Context, parentID primitive.ObjectID, limit, offset int64) ([]*Comment, int64, error)
+
+	// FindByParents finds the oldest limit direct replies to each of the given comments
+	FindByParents(ctx context.Context, parentIDs []primitive.ObjectID, limit int64) ([]*Comment, error)
+
+	// CountByParents counts the direct replies to each of the given comments
+	CountByParents(ctx context.Context, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]int64, error)
+
+	// FindByUser finds a user's most recent comments and counts them all
+	FindByUser(ctx context.Context, userID string, limit int64) ([]*Comment, int64, error)
+
+	// DeleteByBlog deletes every comment on a blog
+	DeleteByBlog(ctx context.Context, blogID primitive.ObjectID) error
+}
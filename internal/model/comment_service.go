@@ -0,0 +1,17 @@
+package model
+
+import (
+	"context"
+)
+
+// CommentService defines the interface for comment-related services
+type CommentService interface {
+	// CreateComment creates a comment or reply by the viewer on a blog they may read
+	CreateComment(ctx context.Context, viewer Viewer, input *CommentInput) (*Comment, error)
+
+	// GetCommentTree gets the comments of a blog the viewer may read as a nested tree
+	GetCommentTree(ctx context.Context, blogID string, viewer Viewer, opts CommentTreeOptions) (*CommentTree, error)
+
+	// ListUserComments lists a user's most recent comments and counts all of them
+	ListUserComments(ctx context.Context, userID string, limit int64) ([]*Comment, int64, error)
+}
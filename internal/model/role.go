@@ -0,0 +1,125 @@
+package model
+
+import "context"
+
+// Role is a named set of permissions granted to a user
+type Role string
+
+const (
+	// RoleReader can comment on and react to blogs
+	RoleReader Role = "reader"
+	// RoleAuthor can also write blogs
+	RoleAuthor Role = "author"
+	// RoleModerator is reserved for comment moderation. Until moderation
+	// endpoints exist it grants no more than RoleReader.
+	RoleModerator Role = "moderator"
+	// RoleEditor can also edit, publish and preview other authors' blogs
+	RoleEditor Role = "editor"
+	// RoleAdmin can do everything, including managing users and their roles
+	RoleAdmin Role = "admin"
+)
+
+// DefaultRoles are the roles of users nobody has assigned roles to
+var DefaultRoles = []Role{RoleAuthor}
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Permission is a single action that roles can be allowed to perform
+type Permission string
+
+const (
+	// PermissionCreateComment allows commenting on blogs
+	PermissionCreateComment Permission = "comments:create"
+	// PermissionReact allows liking and bookmarking blogs
+	PermissionReact Permission = "blogs:react"
+	// PermissionCreateBlog allows writing blogs
+	PermissionCreateBlog Permission = "blogs:create"
+	// PermissionViewUnpublished allows reading other authors' unpublished blogs
+	PermissionViewUnpublished Permission = "blogs:view_unpublished"
+	// PermissionEditAnyBlog allows editing other authors' blogs
+	PermissionEditAnyBlog Permission = "blogs:edit_any"
+	// PermissionPublishAnyBlog allows changing the status of other authors' blogs
+	PermissionPublishAnyBlog Permission = "blogs:publish_any"
+	// PermissionDeleteAnyBlog allows deleting other authors' blogs
+	PermissionDeleteAnyBlog Permission = "blogs:delete_any"
+	// PermissionManageUsers allows administering user accounts and roles
+	PermissionManageUsers Permission = "users:manage"
+)
+
+// rolePermissions is the permission matrix. Each role lists every permission
+// it grants, including those of the roles below it.
+var rolePermissions = map[Role][]Permission{
+	RoleReader: {
+		PermissionCreateComment,
+		PermissionReact,
+	},
+	RoleAuthor: {
+		PermissionCreateComment,
+		PermissionReact,
+		PermissionCreateBlog,
+	},
+	RoleModerator: {
+		PermissionCreateComment,
+		PermissionReact,
+	},
+	RoleEditor: {
+		PermissionCreateComment,
+		PermissionReact,
+		PermissionCreateBlog,
+		PermissionViewUnpublished,
+		PermissionEditAnyBlog,
+		PermissionPublishAnyBlog,
+	},
+	RoleAdmin: {
+		PermissionCreateComment,
+		PermissionReact,
+		PermissionCreateBlog,
+		PermissionViewUnpublished,
+		PermissionEditAnyBlog,
+		PermissionPublishAnyBlog,
+		PermissionDeleteAnyBlog,
+		PermissionManageUsers,
+	},
+}
+
+// Can reports whether the role grants a permission
+func (r Role) Can(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether any of the roles grants a permission
+func HasPermission(roles []Role, permission Permission) bool {
+	for _, role := range roles {
+		if role.Can(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether role is among roles
+func HasRole(roles []Role, role Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleResolver looks up the roles of a user
+type RoleResolver interface {
+	// GetUserRoles gets the effective roles of a user. It fails with a
+	// forbidden error for users who may not use the API at all, such as
+	// disabled users.
+	GetUserRoles(ctx context.Context, uid string) ([]Role, error)
+}
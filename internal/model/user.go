@@ -17,6 +17,8 @@ type User struct {
 	Bookmarks []primitive.ObjectID `json:"bookmarks" bson:"bookmarks"`
 	Likes     []primitive.ObjectID `json:"likes" bson:"likes"`
 	IsAdmin   bool                 `json:"is_admin" bson:"is_admin"`
+	Roles     []Role               `json:"roles" bson:"roles,omitempty"`
+	Disabled  bool                 `json:"disabled" bson:"disabled"`
 }
 
 // NewUser creates a new user from Firebase user information
@@ -32,5 +34,48 @@ func NewUser(id, name, email, photoURL string) *User {
 		Bookmarks: []primitive.ObjectID{},
 		Likes:     []primitive.ObjectID{},
 		IsAdmin:   false,
+		Roles:     append([]Role(nil), DefaultRoles...),
 	}
 }
+
+// EffectiveRoles returns the user's roles, falling back to DefaultRoles for
+// users created before roles existed. The legacy IsAdmin flag grants RoleAdmin.
+func (u *User) EffectiveRoles() []Role {
+	roles := u.Roles
+	if len(roles) == 0 {
+		roles = DefaultRoles
+	}
+	roles = append([]Role(nil), roles...)
+	if u.IsAdmin && !HasRole(roles, RoleAdmin) {
+		roles = append(roles, RoleAdmin)
+	}
+	return roles
+}
+
+// UserListFilter selects users in the admin user listing. Query matches a
+// case-insensitive substring of the name or email.
+type UserListFilter struct {
+	Query    string
+	Role     Role
+	Disabled *bool
+	Limit    int64
+	Offset   int64
+}
+
+// UserPage is a page of users with the total number matching the filter
+type UserPage struct {
+	Users []*User `json:"users"`
+	Total int64   `json:"total"`
+}
+
+// UserActivity summarizes what a user has done on the platform
+type UserActivity struct {
+	User           *User      `json:"user"`
+	BlogCount      int64      `json:"blog_count"`
+	CommentCount   int64      `json:"comment_count"`
+	LikeCount      int        `json:"like_count"`
+	BookmarkCount  int        `json:"bookmark_count"`
+	RecentBlogs    []*Blog    `json:"recent_blogs"`
+	RecentComments []*Comment `json:"recent_comments"`
+	LastSignInAt   *time.Time `json:"last_sign_in_at,omitempty"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserRepository defines the interface for user storage. Implementations
+// return user.ErrUserNotFound for missing users, treat updates of missing
+// users as no-ops and keep bookmark and like sets free of duplicates.
+type UserRepository interface {
+	// FindByID finds a user by ID
+	FindByID(ctx context.Context, id string) (*User, error)
+
+	// FindByEmail finds a user by email
+	FindByEmail(ctx context.Context, email string) (*User, error)
+
+	// Create creates a new user, failing with user.ErrUserExists if the ID is
+	// taken and user.ErrEmailTaken if another user has the email
+	Create(ctx context.Context, user *User) error
+
+	// Update replaces an existing user and sets its UpdatedAt, failing with
+	// user.ErrEmailTaken if another user has the email
+	Update(ctx context.Context, user *User) error
+
+	// AddBookmark adds a blog to a user's bookmarks unless already there
+	AddBookmark(ctx context.Context, userID string, blogID primitive.ObjectID) error
+
+	// RemoveBookmark removes a blog from a user's bookmarks
+	RemoveBookmark(ctx context.Context, userID string, blogID primitive.ObjectID) error
+
+	// AddLike adds a blog to a user's likes unless already there
+	AddLike(ctx context.Context, userID string, blogID primitive.ObjectID) error
+
+	// RemoveLike removes a blog from a user's likes
+	RemoveLike(ctx context.Context, userID string, blogID primitive.ObjectID) error
+
+	// RemoveBlog removes a blog from the bookmarks and likes of every user
+	RemoveBlog(ctx context.Context, blogID primitive.ObjectID) error
+
+	// SetRoles replaces a user's roles, keeping the legacy is_admin flag in sync
+	SetRoles(ctx context.Context, userID string, roles []Role) error
+
+	// SetDisabled marks a user as disabled or enabled
+	SetDisabled(ctx context.Context, userID string, disabled bool) error
+
+	// List finds a page of users matching the filter, newest first, along
+	// with the total number of matches
+	List(ctx context.Context, filter *UserListFilter) ([]*User, int64, error)
+}
@@ -15,9 +15,30 @@ type UserService interface {
 	// UpdateUserProfile updates a user's profile
 	UpdateUserProfile(ctx context.Context, id, name string) (*User, error)
 
-	// ToggleBookmark toggles a bookmark for a user
-	ToggleBookmark(ctx context.Context, userID, blogID string) error
+	// SetUserRoles replaces a user's roles on behalf of an admin
+	SetUserRoles(ctx context.Context, actorID, uid string, roles []Role) (*User, error)
 
-	// ToggleLike toggles a like for a user
-	ToggleLike(ctx context.Context, userID, blogID string) error
+	// GrantRole adds a role to a user
+	GrantRole(ctx context.Context, uid string, role Role) (*User, error)
+
+	// RevokeRole removes a role from a user on behalf of an admin
+	RevokeRole(ctx context.Context, actorID, uid string, role Role) (*User, error)
+
+	// ListUsers lists a page of users matching the filter
+	ListUsers(ctx context.Context, filter *UserListFilter) (*UserPage, error)
+
+	// GetUserActivity summarizes a user's blogs, comments and reactions
+	GetUserActivity(ctx context.Context, viewer Viewer, uid string) (*UserActivity, error)
+
+	// SetUserDisabled disables or enables a user's account on behalf of an admin
+	SetUserDisabled(ctx context.Context, actorID, uid string, disabled bool) (*User, error)
+
+	// RevokeUserTokens revokes every token issued to a user
+	RevokeUserTokens(ctx context.Context, uid string) error
+
+	// ToggleBookmark toggles the viewer's bookmark on a blog they may read
+	ToggleBookmark(ctx context.Context, viewer Viewer, blogID string) (*ReactionResult, error)
+
+	// ToggleLike toggles the viewer's like on a blog they may read
+	ToggleLike(ctx context.Context, viewer Viewer, blogID string) (*ReactionResult, error)
 }
@@ -0,0 +1,34 @@
+package ratelimit
+
+import "context"
+
+// FallbackLimiter uses a primary limiter, such as the Redis one, while it is
+// available and a fallback limiter while it is not. Quotas are counted
+// separately by each, so a client may get up to a fresh quota when the
+// limiter switches.
+type FallbackLimiter struct {
+	primary   Limiter
+	fallback  Limiter
+	available func() bool
+}
+
+// Ensure FallbackLimiter implements Limiter
+var _ Limiter = (*FallbackLimiter)(nil)
+
+// NewFallbackLimiter creates a limiter that uses primary while available
+// reports true and fallback otherwise
+func NewFallbackLimiter(primary, fallback Limiter, available func() bool) *FallbackLimiter {
+	return &FallbackLimiter{
+		primary:   primary,
+		fallback:  fallback,
+		available: available,
+	}
+}
+
+// Allow records a request for key if the limit permits it
+func (l *FallbackLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	if l.available() {
+		return l.primary.Allow(ctx, key, limit)
+	}
+	return l.fallback.Allow(ctx, key, limit)
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// countingLimiter allows every request and counts the calls it gets
+type countingLimiter struct {
+	calls int
+}
+
+func (l *countingLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	l.calls++
+	return &Result{Allowed: true, Limit: limit.Requests}, nil
+}
+
+func TestFallbackLimiter(t *testing.T) {
+	primary, fallback := &countingLimiter{}, &countingLimiter{}
+	available := true
+	limiter := NewFallbackLimiter(primary, fallback, func() bool { return available })
+
+	steps := []struct {
+		name         string
+		available    bool
+		wantPrimary  int
+		wantFallback int
+	}{
+		{name: "Available", available: true, wantPrimary: 1, wantFallback: 0},
+		{name: "Unavailable", available: false, wantPrimary: 1, wantFallback: 1},
+		{name: "StillUnavailable", available: false, wantPrimary: 1, wantFallback: 2},
+		{name: "Recovered", available: true, wantPrimary: 2, wantFallback: 2},
+	}
+
+	for _, step := range steps {
+		available = step.available
+		if _, err := limiter.Allow(context.Background(), "alice", Limit{Requests: 1, Window: time.Second}); err != nil {
+			t.Fatalf("%s: Allow: %v", step.name, err)
+		}
+		if primary.calls != step.wantPrimary || fallback.calls != step.wantFallback {
+			t.Errorf("%s: primary calls = %d, fallback calls = %d, want %d and %d",
+				step.name, primary.calls, fallback.calls, step.wantPrimary, step.wantFallback)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+)
+
+// Limit allows Requests per Window. Requests are spread evenly over the
+// window but up to Requests can be made in a burst.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// interval is the time it takes for one request of quota to be replenished
+func (l Limit) interval() time.Duration {
+	return l.Window / time.Duration(l.Requests)
+}
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is how long until the full quota is available again
+	ResetAfter time.Duration
+	// RetryAfter is how long until the next request is allowed, 0 when allowed
+	RetryAfter time.Duration
+}
+
+// Limiter counts requests against a limit per key
+type Limiter interface {
+	// Allow records a request for key if the limit permits it
+	Allow(ctx context.Context, key string, limit Limit) (*Result, error)
+}
+
+// Identity is what requests are counted by
+type Identity string
+
+const (
+	// IdentityUser counts requests per authenticated user ID
+	IdentityUser Identity = "uid"
+	// IdentityIP counts requests per client IP address
+	IdentityIP Identity = "ip"
+)
+
+// Policy is a named limit applied to a group of routes. Requests are counted
+// by the first identity in Identities the request has; client IP is always
+// available.
+type Policy struct {
+	Name       string
+	Limit      Limit
+	Identities []Identity
+}
+
+// NewPolicy builds a policy from its configuration
+func NewPolicy(name string, cfg config.RateLimitPolicyConfig) (Policy, error) {
+	if cfg.Requests <= 0 || cfg.Window <= 0 {
+		return Policy{}, fmt.Errorf("rate limit policy %s: requests and window must be positive", name)
+	}
+
+	policy := Policy{
+		Name:  name,
+		Limit: Limit{Requests: cfg.Requests, Window: cfg.Window},
+	}
+	for _, value := range strings.Split(cfg.Identity, ",") {
+		identity := Identity(strings.TrimSpace(value))
+		switch identity {
+		case "":
+			continue
+		case IdentityUser, IdentityIP:
+			policy.Identities = append(policy.Identities, identity)
+		default:
+			return Policy{}, fmt.Errorf("rate limit policy %s: unknown identity %q", name, identity)
+		}
+	}
+	if len(policy.Identities) == 0 {
+		policy.Identities = []Identity{IdentityIP}
+	}
+	return policy, nil
+}
+
+// gcra applies the generic cell rate algorithm, a token bucket that only
+// stores the theoretical arrival time (TAT) of the next request. It returns
+// the result and the new TAT to store, or a zero TAT when nothing changes.
+func gcra(now, tat time.Time, limit Limit) (*Result, time.Time) {
+	interval := limit.interval()
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(interval)
+	allowAt := newTAT.Add(-limit.Window)
+	if now.Before(allowAt) {
+		return &Result{
+			Allowed:    false,
+			Limit:      limit.Requests,
+			Remaining:  0,
+			ResetAfter: tat.Sub(now),
+			RetryAfter: allowAt.Sub(now),
+		}, time.Time{}
+	}
+
+	return &Result{
+		Allowed:    true,
+		Limit:      limit.Requests,
+		Remaining:  int((limit.Window - newTAT.Sub(now)) / interval),
+		ResetAfter: newTAT.Sub(now),
+	}, newTAT
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+)
+
+func TestGCRA(t *testing.T) {
+	// Three requests every three seconds, one replenished each second
+	limit := Limit{Requests: 3, Window: 3 * time.Second}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name       string
+		at         time.Duration
+		allowed    bool
+		remaining  int
+		resetAfter time.Duration
+		retryAfter time.Duration
+	}{
+		{name: "First", at: 0, allowed: true, remaining: 2, resetAfter: time.Second},
+		{name: "Burst", at: 0, allowed: true, remaining: 1, resetAfter: 2 * time.Second},
+		{name: "LastOfBurst", at: 0, allowed: true, remaining: 0, resetAfter: 3 * time.Second},
+		{name: "OverQuota", at: 0, allowed: false, remaining: 0, resetAfter: 3 * time.Second, retryAfter: time.Second},
+		{name: "StillOverQuota", at: 500 * time.Millisecond, allowed: false, remaining: 0, resetAfter: 2500 * time.Millisecond, retryAfter: 500 * time.Millisecond},
+		{name: "Replenished", at: time.Second, allowed: true, remaining: 0, resetAfter: 3 * time.Second},
+		{name: "Idle", at: time.Minute, allowed: true, remaining: 2, resetAfter: time.Second},
+	}
+
+	var tat time.Time
+	for _, step := range steps {
+		now := start.Add(step.at)
+		result, newTAT := gcra(now, tat, limit)
+
+		if result.Allowed != step.allowed {
+			t.Fatalf("%s: Allowed = %v, want %v", step.name, result.Allowed, step.allowed)
+		}
+		if result.Limit != limit.Requests {
+			t.Errorf("%s: Limit = %d, want %d", step.name, result.Limit, limit.Requests)
+		}
+		if result.Remaining != step.remaining {
+			t.Errorf("%s: Remaining = %d, want %d", step.name, result.Remaining, step.remaining)
+		}
+		if result.ResetAfter != step.resetAfter {
+			t.Errorf("%s: ResetAfter = %v, want %v", step.name, result.ResetAfter, step.resetAfter)
+		}
+		if result.RetryAfter != step.retryAfter {
+			t.Errorf("%s: RetryAfter = %v, want %v", step.name, result.RetryAfter, step.retryAfter)
+		}
+
+		// Denied requests leave the state untouched
+		if step.allowed == newTAT.IsZero() {
+			t.Fatalf("%s: new TAT = %v with Allowed = %v", step.name, newTAT, step.allowed)
+		}
+		if !newTAT.IsZero() {
+			if want := now.Add(result.ResetAfter); !newTAT.Equal(want) {
+				t.Errorf("%s: new TAT = %v, want %v", step.name, newTAT, want)
+			}
+			tat = newTAT
+		}
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     config.RateLimitPolicyConfig
+		want    []Identity
+		wantErr string
+	}{
+		{
+			name: "Identities",
+			cfg:  config.RateLimitPolicyConfig{Requests: 10, Window: time.Minute, Identity: " uid ,ip"},
+			want: []Identity{IdentityUser, IdentityIP},
+		},
+		{
+			name: "DefaultsToIP",
+			cfg:  config.RateLimitPolicyConfig{Requests: 10, Window: time.Minute},
+			want: []Identity{IdentityIP},
+		},
+		{
+			name:    "UnknownIdentity",
+			cfg:     config.RateLimitPolicyConfig{Requests: 10, Window: time.Minute, Identity: "uid,email"},
+			wantErr: `unknown identity "email"`,
+		},
+		{
+			name:    "NoRequests",
+			cfg:     config.RateLimitPolicyConfig{Window: time.Minute},
+			wantErr: "must be positive",
+		},
+		{
+			name:    "NoWindow",
+			cfg:     config.RateLimitPolicyConfig{Requests: 10},
+			wantErr: "must be positive",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := NewPolicy("test", tc.cfg)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("NewPolicy error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewPolicy: %v", err)
+			}
+
+			if policy.Name != "test" || policy.Limit != (Limit{Requests: tc.cfg.Requests, Window: tc.cfg.Window}) {
+				t.Errorf("policy = %+v", policy)
+			}
+			if !slices.Equal(policy.Identities, tc.want) {
+				t.Errorf("Identities = %v, want %v", policy.Identities, tc.want)
+			}
+		})
+	}
+}
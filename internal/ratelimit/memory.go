@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryLimiter keeps rate limit state in process. Quotas are per replica and
+// reset on restart, so it is meant for development and single instances.
+type MemoryLimiter struct {
+	mu   sync.Mutex
+	tats map[string]time.Time
+}
+
+// Ensure MemoryLimiter implements Limiter
+var _ Limiter = (*MemoryLimiter)(nil)
+
+// NewMemoryLimiter creates a new in-process limiter
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		tats: make(map[string]time.Time),
+	}
+}
+
+// Allow records a request for key if the limit permits it
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result, tat := gcra(time.Now(), l.tats[key], limit)
+	if !tat.IsZero() {
+		l.tats[key] = tat
+	}
+	return result, nil
+}
+
+// Cleanup periodically forgets keys whose quota is fully replenished, until
+// ctx is done
+func (l *MemoryLimiter) Cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				l.mu.Lock()
+				for key, tat := range l.tats {
+					if tat.Before(now) {
+						delete(l.tats, key)
+					}
+				}
+				l.mu.Unlock()
+			}
+		}
+	}()
+}
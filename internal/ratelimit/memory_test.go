@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewMemoryLimiter()
+	limit := Limit{Requests: 2, Window: time.Minute}
+
+	for i, want := range []bool{true, true, false} {
+		result, err := limiter.Allow(ctx, "alice", limit)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		if result.Allowed != want {
+			t.Fatalf("request %d: Allowed = %v, want %v", i, result.Allowed, want)
+		}
+	}
+
+	// Keys have their own quota
+	result, err := limiter.Allow(ctx, "bob", limit)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if !result.Allowed || result.Remaining != 1 {
+		t.Errorf("bob: %+v, want allowed with 1 remaining", result)
+	}
+}
+
+func TestMemoryLimiterCleanup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	limiter := NewMemoryLimiter()
+	if _, err := limiter.Allow(ctx, "alice", Limit{Requests: 1, Window: time.Millisecond}); err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if _, err := limiter.Allow(ctx, "bob", Limit{Requests: 1, Window: time.Hour}); err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	limiter.Cleanup(ctx, 5*time.Millisecond)
+
+	// Only keys whose quota is replenished are forgotten
+	deadline := time.Now().Add(time.Second)
+	for {
+		limiter.mu.Lock()
+		_, alice := limiter.tats["alice"]
+		_, bob := limiter.tats["bob"]
+		limiter.mu.Unlock()
+
+		if !bob {
+			t.Fatal("key with quota in use was forgotten")
+		}
+		if !alice {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("replenished key was not forgotten")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
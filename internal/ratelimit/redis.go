@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/db"
+	"github.com/go-redis/redis/v8"
+)
+
+// gcraScript is gcra run atomically in Redis with the server's clock, so all
+// replicas share one quota. The key holds the TAT in microseconds and expires
+// once the quota is fully replenished.
+//
+// KEYS[1] = key, ARGV[1] = interval (µs), ARGV[2] = window (µs)
+// Returns {allowed, remaining, reset_after (µs), retry_after (µs)}.
+var gcraScript = redis.NewScript(`
+-- Replicate the writes rather than the script, which reads the clock
+redis.replicate_commands()
+
+local interval = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - window
+if now < allow_at then
+	return {0, 0, tat - now, allow_at - now}
+end
+
+redis.call("SET", KEYS[1], new_tat, "PX", math.ceil((new_tat - now) / 1000))
+return {1, math.floor((window - (new_tat - now)) / interval), new_tat - now, 0}
+`)
+
+// RedisLimiter keeps rate limit state in Redis, shared by every replica
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// Ensure RedisLimiter implements Limiter
+var _ Limiter = (*RedisLimiter)(nil)
+
+// NewRedisLimiter creates a new Redis-backed limiter
+func NewRedisLimiter(r *db.Redis) *RedisLimiter {
+	return &RedisLimiter{
+		client: r.Client,
+		prefix: "ratelimit:",
+	}
+}
+
+// Allow records a request for key if the limit permits it
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	values, err := gcraScript.Run(ctx, l.client, []string{l.prefix + key},
+		limit.interval().Microseconds(), limit.Window.Microseconds()).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit.Requests,
+		Remaining:  int(values[1]),
+		ResetAfter: time.Duration(values[2]) * time.Microsecond,
+		RetryAfter: time.Duration(values[3]) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/db/dbtest"
+)
+
+// TestRedisLimiter checks that gcraScript keeps the contract of gcra: the
+// same decisions and counters, with durations measured by the Redis clock
+// and a key that expires once the quota is replenished.
+func TestRedisLimiter(t *testing.T) {
+	r, prefix := dbtest.NewRedis(t)
+	limiter := NewRedisLimiter(r)
+	limiter.prefix = prefix
+	ctx := context.Background()
+
+	limit := Limit{Requests: 3, Window: 30 * time.Second}
+	interval := limit.interval()
+	// Allow for the time passing on the Redis clock between calls
+	const slack = time.Second
+
+	for i := range limit.Requests {
+		result, err := limiter.Allow(ctx, "alice", limit)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		if !result.Allowed || result.Limit != limit.Requests || result.Remaining != limit.Requests-1-i {
+			t.Fatalf("request %d: %+v, want allowed with %d remaining", i, result, limit.Requests-1-i)
+		}
+		if want := time.Duration(i+1) * interval; result.ResetAfter > want || result.ResetAfter < want-slack {
+			t.Errorf("request %d: ResetAfter = %v, want about %v", i, result.ResetAfter, want)
+		}
+		if result.RetryAfter != 0 {
+			t.Errorf("request %d: RetryAfter = %v, want 0", i, result.RetryAfter)
+		}
+	}
+
+	result, err := limiter.Allow(ctx, "alice", limit)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if result.Allowed || result.Remaining != 0 {
+		t.Fatalf("over quota: %+v, want denied", result)
+	}
+	if result.RetryAfter > interval || result.RetryAfter < interval-slack {
+		t.Errorf("RetryAfter = %v, want about %v", result.RetryAfter, interval)
+	}
+	if result.ResetAfter > limit.Window || result.ResetAfter < limit.Window-slack {
+		t.Errorf("ResetAfter = %v, want about %v", result.ResetAfter, limit.Window)
+	}
+
+	// The stored TAT expires once the whole quota is back
+	ttl, err := r.Client.PTTL(ctx, prefix+"alice").Result()
+	if err != nil {
+		t.Fatalf("PTTL: %v", err)
+	}
+	if ttl > limit.Window || ttl < limit.Window-slack {
+		t.Errorf("key TTL = %v, want about %v", ttl, limit.Window)
+	}
+
+	// Keys have their own quota
+	result, err = limiter.Allow(ctx, "bob", limit)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if !result.Allowed || result.Remaining != limit.Requests-1 {
+		t.Errorf("bob: %+v, want allowed with %d remaining", result, limit.Requests-1)
+	}
+}
@@ -0,0 +1,516 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/blog"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newBlog returns a published fixture blog created at the given minute
+func newBlog(title, authorID string, tags []string, minute int) *model.Blog {
+	b := model.NewBlog(title, title+" content", authorID, tags, "")
+	b.Status = model.BlogStatusPublished
+	b.CreatedAt, b.UpdatedAt = at(minute), at(minute)
+	return b
+}
+
+// blogIDs returns the IDs of blogs in order
+func blogIDs(blogs []*model.Blog) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(blogs))
+	for _, b := range blogs {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
+
+// TestBlogRepository checks that a model.BlogRepository behaves like the
+// MongoDB implementation. newRepo is called once per subtest and must return
+// an empty repository.
+func TestBlogRepository(t *testing.T, newRepo func(t *testing.T) model.BlogRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, repo model.BlogRepository, b *model.Blog) *model.Blog {
+		t.Helper()
+		requireNoError(t, repo.Create(ctx, b), "Create "+b.Title)
+		if b.ID.IsZero() {
+			t.Fatalf("Create %s did not set the ID", b.Title)
+		}
+		return b
+	}
+
+	t.Run("FindMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindByID(ctx, primitive.NewObjectID())
+		requireErrorIs(t, err, blog.ErrBlogNotFound, "FindByID")
+	})
+
+	t.Run("CreateAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		created := create(t, repo, newBlog("First", "author", []string{"go", "mongo"}, 0))
+
+		found, err := repo.FindByID(ctx, created.ID)
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.Title, "First", "Title")
+		assertEqual(t, found.AuthorID, "author", "AuthorID")
+		assertEqual(t, found.Status, model.BlogStatusPublished, "Status")
+		assertEqual(t, found.Version, 1, "Version")
+		assertElements(t, found.Tags, []string{"go", "mongo"}, "Tags")
+		assertTime(t, found.CreatedAt, created.CreatedAt, "CreatedAt")
+
+		// Callers must not be able to change stored blogs through results
+		found.Tags[0] = "changed"
+		again, err := repo.FindByID(ctx, created.ID)
+		requireNoError(t, err, "FindByID")
+		assertElements(t, again.Tags, []string{"go", "mongo"}, "Tags")
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		b := create(t, repo, newBlog("Draft", "author", nil, 0))
+		_, err := repo.ToggleLike(ctx, b.ID, "reader")
+		requireNoError(t, err, "ToggleLike")
+
+		b.Title = "Edited"
+		b.Tags = []string{"edited"}
+		b.Version = 2
+		requireNoError(t, repo.Update(ctx, b, 1), "Update")
+
+		found, err := repo.FindByID(ctx, b.ID)
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.Title, "Edited", "Title")
+		assertEqual(t, found.Version, 2, "Version")
+		assertElements(t, found.Tags, []string{"edited"}, "Tags")
+		// Reactions are not editable fields, so the stale copy must not reset them
+		assertEqual(t, found.LikeCount, int64(1), "LikeCount")
+		assertElements(t, found.Likes, []string{"reader"}, "Likes")
+
+		// A second edit based on the same version loses
+		b.Title = "Stale"
+		b.Version = 2
+		requireErrorIs(t, repo.Update(ctx, b, 1), blog.ErrVersionConflict, "Update stale")
+		found, err = repo.FindByID(ctx, b.ID)
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.Title, "Edited", "Title")
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		b := newBlog("Missing", "author", nil, 0)
+		b.ID = primitive.NewObjectID()
+
+		requireErrorIs(t, repo.Update(ctx, b, 1), blog.ErrBlogNotFound, "Update")
+	})
+
+	t.Run("List", func(t *testing.T) {
+		repo := newRepo(t)
+		goIntro := create(t, repo, newBlog("Go intro", "ada", []string{"go"}, 0))
+		mongoTips := create(t, repo, newBlog("Mongo tips", "alan", []string{"mongo"}, 1))
+		goAdvanced := create(t, repo, newBlog("Go advanced", "ada", []string{"go", "advanced"}, 2))
+		draft := newBlog("Draft", "ada", []string{"go"}, 3)
+		draft.Status = model.BlogStatusDraft
+		create(t, repo, draft)
+
+		cases := []struct {
+			name   string
+			filter model.BlogListFilter
+			want   []primitive.ObjectID
+		}{
+			{"Published", model.BlogListFilter{Status: model.BlogStatusPublished}, []primitive.ObjectID{goAdvanced.ID, mongoTips.ID, goIntro.ID}},
+			{"AnyStatus", model.BlogListFilter{}, []primitive.ObjectID{draft.ID, goAdvanced.ID, mongoTips.ID, goIntro.ID}},
+			{"Tag", model.BlogListFilter{Status: model.BlogStatusPublished, Tag: "go"}, []primitive.ObjectID{goAdvanced.ID, goIntro.ID}},
+			{"Author", model.BlogListFilter{AuthorID: "alan"}, []primitive.ObjectID{mongoTips.ID}},
+			{"Range", model.BlogListFilter{From: at(1), To: at(3)}, []primitive.ObjectID{goAdvanced.ID, mongoTips.ID}},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				tc.filter.Sort = model.BlogSortNewest
+				tc.filter.Limit = 10
+				page, err := repo.List(ctx, &tc.filter)
+				requireNoError(t, err, "List")
+				assertOrder(t, blogIDs(page.Blogs), tc.want, "List")
+				assertEqual(t, page.NextCursor, "", "NextCursor")
+			})
+		}
+	})
+
+	t.Run("ListPages", func(t *testing.T) {
+		repo := newRepo(t)
+		var want []primitive.ObjectID
+		for i := range 5 {
+			// Pairs of blogs share a creation time, so _id must break ties
+			b := create(t, repo, newBlog("Blog", "ada", nil, i/2))
+			want = append(want, b.ID)
+		}
+		newestFirst := []primitive.ObjectID{want[4], want[3], want[2], want[1], want[0]}
+
+		var got []primitive.ObjectID
+		filter := model.BlogListFilter{Sort: model.BlogSortNewest, Limit: 2}
+		for pages := 0; ; pages++ {
+			if pages > 5 {
+				t.Fatal("pagination did not terminate")
+			}
+			page, err := repo.List(ctx, &filter)
+			requireNoError(t, err, "List")
+			got = append(got, blogIDs(page.Blogs)...)
+			if page.NextCursor == "" {
+				break
+			}
+			filter.Cursor = page.NextCursor
+		}
+		assertOrder(t, got, newestFirst, "paged List")
+	})
+
+	t.Run("ListMostLiked", func(t *testing.T) {
+		repo := newRepo(t)
+		quiet := create(t, repo, newBlog("Quiet", "ada", nil, 0))
+		popular := create(t, repo, newBlog("Popular", "ada", nil, 1))
+		liked := create(t, repo, newBlog("Liked", "ada", nil, 2))
+		for _, userID := range []string{"u1", "u2"} {
+			_, err := repo.ToggleLike(ctx, popular.ID, userID)
+			requireNoError(t, err, "ToggleLike")
+		}
+		_, err := repo.ToggleLike(ctx, liked.ID, "u1")
+		requireNoError(t, err, "ToggleLike")
+
+		filter := model.BlogListFilter{Sort: model.BlogSortMostLiked, Limit: 2}
+		page, err := repo.List(ctx, &filter)
+		requireNoError(t, err, "List")
+		assertOrder(t, blogIDs(page.Blogs), []primitive.ObjectID{popular.ID, liked.ID}, "first page")
+
+		filter.Cursor = page.NextCursor
+		page, err = repo.List(ctx, &filter)
+		requireNoError(t, err, "List")
+		assertOrder(t, blogIDs(page.Blogs), []primitive.ObjectID{quiet.ID}, "second page")
+		assertEqual(t, page.NextCursor, "", "NextCursor")
+	})
+
+	t.Run("ListBadCursor", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, newBlog("Blog", "ada", nil, 0))
+		create(t, repo, newBlog("Blog", "ada", nil, 1))
+
+		page, err := repo.List(ctx, &model.BlogListFilter{Sort: model.BlogSortNewest, Limit: 1})
+		requireNoError(t, err, "List")
+
+		_, err = repo.List(ctx, &model.BlogListFilter{Sort: model.BlogSortMostLiked, Cursor: page.NextCursor, Limit: 1})
+		requireErrorIs(t, err, blog.ErrInvalidInput, "List with cursor of another sort")
+		_, err = repo.List(ctx, &model.BlogListFilter{Sort: model.BlogSortNewest, Cursor: "not a cursor", Limit: 1})
+		requireErrorIs(t, err, blog.ErrInvalidInput, "List with malformed cursor")
+	})
+
+	t.Run("ListPopular", func(t *testing.T) {
+		repo := newRepo(t)
+		older := create(t, repo, newBlog("Older", "ada", nil, 0))
+		newer := create(t, repo, newBlog("Newer", "ada", nil, 1))
+		top := create(t, repo, newBlog("Top", "ada", nil, 2))
+		draft := newBlog("Draft", "ada", nil, 3)
+		draft.Status = model.BlogStatusDraft
+		create(t, repo, draft)
+		// Drafts are never popular, however liked
+		for _, like := range []struct {
+			id     primitive.ObjectID
+			userID string
+		}{{top.ID, "u1"}, {draft.ID, "u1"}, {draft.ID, "u2"}} {
+			_, err := repo.ToggleLike(ctx, like.id, like.userID)
+			requireNoError(t, err, "ToggleLike")
+		}
+
+		blogs, err := repo.ListPopular(ctx, 10)
+		requireNoError(t, err, "ListPopular")
+		assertOrder(t, blogIDs(blogs), []primitive.ObjectID{top.ID, newer.ID, older.ID}, "ListPopular")
+
+		blogs, err = repo.ListPopular(ctx, 2)
+		requireNoError(t, err, "ListPopular")
+		assertOrder(t, blogIDs(blogs), []primitive.ObjectID{top.ID, newer.ID}, "ListPopular limited")
+	})
+
+	t.Run("PublishDue", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		past, future := now.Add(-time.Minute), now.Add(time.Hour)
+
+		due := newBlog("Due", "ada", nil, 0)
+		due.Status, due.PublishAt = model.BlogStatusScheduled, &past
+		create(t, repo, due)
+		later := newBlog("Later", "ada", nil, 1)
+		later.Status, later.PublishAt = model.BlogStatusScheduled, &future
+		create(t, repo, later)
+
+		published, err := repo.PublishDue(ctx, now)
+		requireNoError(t, err, "PublishDue")
+		assertOrder(t, published, []primitive.ObjectID{due.ID}, "published")
+
+		found, err := repo.FindByID(ctx, due.ID)
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.Status, model.BlogStatusPublished, "due Status")
+		found, err = repo.FindByID(ctx, later.ID)
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.Status, model.BlogStatusScheduled, "later Status")
+
+		// Nothing is published twice
+		published, err = repo.PublishDue(ctx, now)
+		requireNoError(t, err, "PublishDue again")
+		assertEqual(t, len(published), 0, "len(published)")
+	})
+
+	t.Run("CountByAuthor", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, newBlog("One", "ada", nil, 0))
+		draft := newBlog("Two", "ada", nil, 1)
+		draft.Status = model.BlogStatusDraft
+		create(t, repo, draft)
+		create(t, repo, newBlog("Three", "alan", nil, 2))
+
+		count, err := repo.CountByAuthor(ctx, "ada")
+		requireNoError(t, err, "CountByAuthor")
+		assertEqual(t, count, int64(2), "count")
+		count, err = repo.CountByAuthor(ctx, "nobody")
+		requireNoError(t, err, "CountByAuthor")
+		assertEqual(t, count, int64(0), "count")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		b := create(t, repo, newBlog("Doomed", "ada", nil, 0))
+
+		requireNoError(t, repo.Delete(ctx, b.ID), "Delete")
+		_, err := repo.FindByID(ctx, b.ID)
+		requireErrorIs(t, err, blog.ErrBlogNotFound, "FindByID")
+		requireErrorIs(t, repo.Delete(ctx, b.ID), blog.ErrBlogNotFound, "Delete again")
+	})
+
+	t.Run("IncrementCommentCount", func(t *testing.T) {
+		repo := newRepo(t)
+		b := create(t, repo, newBlog("Discussed", "ada", nil, 0))
+
+		requireNoError(t, repo.IncrementCommentCount(ctx, b.ID, 3), "IncrementCommentCount")
+		requireNoError(t, repo.IncrementCommentCount(ctx, b.ID, -1), "IncrementCommentCount")
+		found, err := repo.FindByID(ctx, b.ID)
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.CommentCount, int64(2), "CommentCount")
+
+		err = repo.IncrementCommentCount(ctx, primitive.NewObjectID(), 1)
+		requireErrorIs(t, err, blog.ErrBlogNotFound, "IncrementCommentCount missing")
+	})
+
+	t.Run("Toggle", func(t *testing.T) {
+		repo := newRepo(t)
+		b := create(t, repo, newBlog("Toggled", "ada", nil, 0))
+
+		liked, err := repo.ToggleLike(ctx, b.ID, "u1")
+		requireNoError(t, err, "ToggleLike")
+		assertElements(t, liked.Likes, []string{"u1"}, "Likes")
+		assertEqual(t, liked.LikeCount, int64(1), "LikeCount")
+
+		unliked, err := repo.ToggleLike(ctx, b.ID, "u1")
+		requireNoError(t, err, "ToggleLike again")
+		assertElements(t, unliked.Likes, nil, "Likes")
+		assertEqual(t, unliked.LikeCount, int64(0), "LikeCount")
+
+		bookmarked, err := repo.ToggleBookmark(ctx, b.ID, "u1")
+		requireNoError(t, err, "ToggleBookmark")
+		assertElements(t, bookmarked.BookmarkedBy, []string{"u1"}, "BookmarkedBy")
+		assertEqual(t, bookmarked.BookmarkCount, int64(1), "BookmarkCount")
+
+		_, err = repo.ToggleLike(ctx, primitive.NewObjectID(), "u1")
+		requireErrorIs(t, err, blog.ErrBlogNotFound, "ToggleLike missing")
+		_, err = repo.ToggleBookmark(ctx, primitive.NewObjectID(), "u1")
+		requireErrorIs(t, err, blog.ErrBlogNotFound, "ToggleBookmark missing")
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		repo := newRepo(t)
+		b := create(t, repo, newBlog("Set", "ada", nil, 0))
+
+		// Setting the same state twice leaves a single entry
+		for range 2 {
+			liked, err := repo.SetLike(ctx, b.ID, "u1", true)
+			requireNoError(t, err, "SetLike")
+			assertElements(t, liked.Likes, []string{"u1"}, "Likes")
+			assertEqual(t, liked.LikeCount, int64(1), "LikeCount")
+		}
+		for range 2 {
+			unliked, err := repo.SetLike(ctx, b.ID, "u1", false)
+			requireNoError(t, err, "SetLike false")
+			assertElements(t, unliked.Likes, nil, "Likes")
+			assertEqual(t, unliked.LikeCount, int64(0), "LikeCount")
+		}
+
+		for range 2 {
+			bookmarked, err := repo.SetBookmark(ctx, b.ID, "u1", true)
+			requireNoError(t, err, "SetBookmark")
+			assertElements(t, bookmarked.BookmarkedBy, []string{"u1"}, "BookmarkedBy")
+			assertEqual(t, bookmarked.BookmarkCount, int64(1), "BookmarkCount")
+		}
+		unbookmarked, err := repo.SetBookmark(ctx, b.ID, "u1", false)
+		requireNoError(t, err, "SetBookmark false")
+		assertElements(t, unbookmarked.BookmarkedBy, nil, "BookmarkedBy")
+		assertEqual(t, unbookmarked.BookmarkCount, int64(0), "BookmarkCount")
+
+		_, err = repo.SetLike(ctx, primitive.NewObjectID(), "u1", true)
+		requireErrorIs(t, err, blog.ErrBlogNotFound, "SetLike missing")
+		_, err = repo.SetBookmark(ctx, primitive.NewObjectID(), "u1", true)
+		requireErrorIs(t, err, blog.ErrBlogNotFound, "SetBookmark missing")
+	})
+
+	t.Run("ConcurrentToggles", func(t *testing.T) {
+		repo := newRepo(t)
+		b := create(t, repo, newBlog("Busy", "ada", nil, 0))
+
+		// Every user toggles twice, so no like may survive
+		const users = 10
+		done := make(chan error, users*2)
+		for i := range users * 2 {
+			go func() {
+				_, err := repo.ToggleLike(ctx, b.ID, string(rune('a'+i%users)))
+				done <- err
+			}()
+		}
+		for range users * 2 {
+			requireNoError(t, <-done, "ToggleLike")
+		}
+
+		found, err := repo.FindByID(ctx, b.ID)
+		requireNoError(t, err, "FindByID")
+		assertElements(t, found.Likes, nil, "Likes")
+		assertEqual(t, found.LikeCount, int64(0), "LikeCount")
+	})
+}
+
+// TestBlogRevisionRepository checks that a model.BlogRevisionRepository
+// behaves like the MongoDB implementation. newRepo is called once per subtest
+// and must return an empty repository.
+func TestBlogRevisionRepository(t *testing.T, newRepo func(t *testing.T) model.BlogRevisionRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, repo model.BlogRevisionRepository, blogID primitive.ObjectID, version int) *model.BlogRevision {
+		t.Helper()
+		b := newBlog("Version", "ada", []string{"go"}, version)
+		b.ID, b.Version = blogID, version
+		revision := model.NewBlogRevision(b, "ada", "edit")
+		requireNoError(t, repo.Create(ctx, revision), "Create")
+		if revision.ID.IsZero() {
+			t.Fatal("Create did not set the ID")
+		}
+		return revision
+	}
+
+	t.Run("CreateAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID := primitive.NewObjectID()
+		created := create(t, repo, blogID, 1)
+
+		found, err := repo.FindByVersion(ctx, blogID, 1)
+		requireNoError(t, err, "FindByVersion")
+		assertEqual(t, found.ID, created.ID, "ID")
+		assertEqual(t, found.Content, created.Content, "Content")
+		assertElements(t, found.Tags, []string{"go"}, "Tags")
+
+		_, err = repo.FindByVersion(ctx, blogID, 2)
+		requireErrorIs(t, err, blog.ErrRevisionNotFound, "FindByVersion missing version")
+		_, err = repo.FindByVersion(ctx, primitive.NewObjectID(), 1)
+		requireErrorIs(t, err, blog.ErrRevisionNotFound, "FindByVersion missing blog")
+	})
+
+	t.Run("CreateDuplicateVersion", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID := primitive.NewObjectID()
+		create(t, repo, blogID, 1)
+
+		b := newBlog("Racing", "alan", nil, 1)
+		b.ID = blogID
+		if err := repo.Create(ctx, model.NewBlogRevision(b, "alan", "race")); err == nil {
+			t.Fatal("Create of an existing version succeeded")
+		}
+	})
+
+	t.Run("ListByBlog", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID := primitive.NewObjectID()
+		for version := 1; version <= 3; version++ {
+			create(t, repo, blogID, version)
+		}
+		create(t, repo, primitive.NewObjectID(), 1)
+
+		revisions, err := repo.ListByBlog(ctx, blogID)
+		requireNoError(t, err, "ListByBlog")
+		if len(revisions) != 3 {
+			t.Fatalf("len(revisions) = %d, want 3", len(revisions))
+		}
+		for i, revision := range revisions {
+			assertEqual(t, revision.Version, 3-i, "Version")
+			assertEqual(t, revision.Content, "", "Content")
+			assertEqual(t, revision.Title, "Version", "Title")
+		}
+
+		revisions, err = repo.ListByBlog(ctx, primitive.NewObjectID())
+		requireNoError(t, err, "ListByBlog unknown blog")
+		assertEqual(t, len(revisions), 0, "len(revisions)")
+	})
+
+	t.Run("DeleteByBlog", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+		create(t, repo, blogID, 1)
+		create(t, repo, blogID, 2)
+		create(t, repo, otherID, 1)
+
+		requireNoError(t, repo.DeleteByBlog(ctx, blogID), "DeleteByBlog")
+		revisions, err := repo.ListByBlog(ctx, blogID)
+		requireNoError(t, err, "ListByBlog")
+		assertEqual(t, len(revisions), 0, "len(revisions)")
+		_, err = repo.FindByVersion(ctx, otherID, 1)
+		requireNoError(t, err, "FindByVersion other blog")
+	})
+}
+
+// TestBlogSlugRepository checks that a model.BlogSlugRepository behaves like
+// the MongoDB implementation. newRepo is called once per subtest and must
+// return an empty repository.
+func TestBlogSlugRepository(t *testing.T, newRepo func(t *testing.T) model.BlogSlugRepository) {
+	ctx := context.Background()
+
+	t.Run("Reserve", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+
+		requireNoError(t, repo.Reserve(ctx, "hello-world", blogID), "Reserve")
+		requireNoError(t, repo.Reserve(ctx, "hello-world", blogID), "Reserve by owner")
+		requireErrorIs(t, repo.Reserve(ctx, "hello-world", otherID), blog.ErrSlugTaken, "Reserve by another blog")
+
+		owner, err := repo.FindBlogID(ctx, "hello-world")
+		requireNoError(t, err, "FindBlogID")
+		assertEqual(t, owner, blogID, "owner")
+	})
+
+	t.Run("FindMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindBlogID(ctx, "missing")
+		requireErrorIs(t, err, blog.ErrBlogNotFound, "FindBlogID")
+	})
+
+	t.Run("DeleteByBlog", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+		requireNoError(t, repo.Reserve(ctx, "old-title", blogID), "Reserve")
+		requireNoError(t, repo.Reserve(ctx, "new-title", blogID), "Reserve")
+		requireNoError(t, repo.Reserve(ctx, "other", otherID), "Reserve")
+
+		requireNoError(t, repo.DeleteByBlog(ctx, blogID), "DeleteByBlog")
+		for _, slug := range []string{"old-title", "new-title"} {
+			_, err := repo.FindBlogID(ctx, slug)
+			requireErrorIs(t, err, blog.ErrBlogNotFound, "FindBlogID "+slug)
+		}
+		_, err := repo.FindBlogID(ctx, "other")
+		requireNoError(t, err, "FindBlogID other")
+
+		// Released slugs can be claimed by anyone
+		requireNoError(t, repo.Reserve(ctx, "old-title", otherID), "Reserve released slug")
+	})
+}
@@ -0,0 +1,187 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/comment"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// commentIDs returns the IDs of comments in order
+func commentIDs(comments []*model.Comment) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(comments))
+	for _, c := range comments {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+// TestCommentRepository checks that a model.CommentRepository behaves like
+// the MongoDB implementation. newRepo is called once per subtest and must
+// return an empty repository.
+func TestCommentRepository(t *testing.T, newRepo func(t *testing.T) model.CommentRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, repo model.CommentRepository, blogID primitive.ObjectID, userID string, parentID primitive.ObjectID, minute int) *model.Comment {
+		t.Helper()
+		c := model.NewComment(blogID, userID, "comment by "+userID, parentID)
+		c.CreatedAt, c.UpdatedAt = at(minute), at(minute)
+		requireNoError(t, repo.Create(ctx, c), "Create")
+		if c.ID.IsZero() {
+			t.Fatal("Create did not set the ID")
+		}
+		return c
+	}
+
+	t.Run("CreateAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID := primitive.NewObjectID()
+		root := create(t, repo, blogID, "ada", primitive.NilObjectID, 0)
+		reply := create(t, repo, blogID, "alan", root.ID, 1)
+
+		found, err := repo.FindByID(ctx, reply.ID)
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.BlogID, blogID, "BlogID")
+		assertEqual(t, found.UserID, "alan", "UserID")
+		assertEqual(t, found.ParentID, root.ID, "ParentID")
+		assertEqual(t, found.Content, "comment by alan", "Content")
+		assertTime(t, found.CreatedAt, at(1), "CreatedAt")
+
+		found, err = repo.FindByID(ctx, root.ID)
+		requireNoError(t, err, "FindByID root")
+		assertEqual(t, found.ParentID, primitive.NilObjectID, "root ParentID")
+
+		_, err = repo.FindByID(ctx, primitive.NewObjectID())
+		requireErrorIs(t, err, comment.ErrCommentNotFound, "FindByID missing")
+	})
+
+	t.Run("FindRoots", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID := primitive.NewObjectID()
+		second := create(t, repo, blogID, "alan", primitive.NilObjectID, 1)
+		first := create(t, repo, blogID, "ada", primitive.NilObjectID, 0)
+		// Same creation time as second, so _id breaks the tie
+		third := create(t, repo, blogID, "grace", primitive.NilObjectID, 1)
+		create(t, repo, blogID, "grace", first.ID, 2)
+		create(t, repo, primitive.NewObjectID(), "ada", primitive.NilObjectID, 0)
+
+		roots, total, err := repo.FindRoots(ctx, blogID, 10, 0)
+		requireNoError(t, err, "FindRoots")
+		assertOrder(t, commentIDs(roots), []primitive.ObjectID{first.ID, second.ID, third.ID}, "roots")
+		assertEqual(t, total, int64(3), "total")
+
+		roots, total, err = repo.FindRoots(ctx, blogID, 1, 1)
+		requireNoError(t, err, "FindRoots page")
+		assertOrder(t, commentIDs(roots), []primitive.ObjectID{second.ID}, "roots page")
+		assertEqual(t, total, int64(3), "total")
+
+		roots, total, err = repo.FindRoots(ctx, primitive.NewObjectID(), 10, 0)
+		requireNoError(t, err, "FindRoots unknown blog")
+		assertEqual(t, len(roots), 0, "len(roots)")
+		assertEqual(t, total, int64(0), "total")
+	})
+
+	t.Run("FindReplies", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID := primitive.NewObjectID()
+		root := create(t, repo, blogID, "ada", primitive.NilObjectID, 0)
+		later := create(t, repo, blogID, "alan", root.ID, 2)
+		earlier := create(t, repo, blogID, "grace", root.ID, 1)
+		create(t, repo, blogID, "ada", later.ID, 3)
+
+		replies, total, err := repo.FindReplies(ctx, root.ID, 10, 0)
+		requireNoError(t, err, "FindReplies")
+		assertOrder(t, commentIDs(replies), []primitive.ObjectID{earlier.ID, later.ID}, "replies")
+		assertEqual(t, total, int64(2), "total")
+
+		replies, total, err = repo.FindReplies(ctx, root.ID, 1, 1)
+		requireNoError(t, err, "FindReplies page")
+		assertOrder(t, commentIDs(replies), []primitive.ObjectID{later.ID}, "replies page")
+		assertEqual(t, total, int64(2), "total")
+	})
+
+	t.Run("FindByParents", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID := primitive.NewObjectID()
+		a := create(t, repo, blogID, "ada", primitive.NilObjectID, 0)
+		b := create(t, repo, blogID, "alan", primitive.NilObjectID, 1)
+		c := create(t, repo, blogID, "grace", primitive.NilObjectID, 2)
+		replyB := create(t, repo, blogID, "ada", b.ID, 3)
+		replyA := create(t, repo, blogID, "alan", a.ID, 4)
+		create(t, repo, blogID, "grace", c.ID, 5)
+		create(t, repo, blogID, "grace", replyA.ID, 6)
+		laterA := create(t, repo, blogID, "grace", a.ID, 7)
+		create(t, repo, blogID, "ada", a.ID, 8)
+
+		replies, err := repo.FindByParents(ctx, []primitive.ObjectID{a.ID, b.ID}, 10)
+		requireNoError(t, err, "FindByParents")
+		assertEqual(t, len(replies), 4, "len(replies)")
+
+		// The limit applies to each parent separately
+		replies, err = repo.FindByParents(ctx, []primitive.ObjectID{a.ID, b.ID}, 2)
+		requireNoError(t, err, "FindByParents limited")
+		assertOrder(t, commentIDs(replies), []primitive.ObjectID{replyB.ID, replyA.ID, laterA.ID}, "replies")
+
+		replies, err = repo.FindByParents(ctx, []primitive.ObjectID{}, 2)
+		requireNoError(t, err, "FindByParents none")
+		assertEqual(t, len(replies), 0, "len(replies)")
+	})
+
+	t.Run("CountByParents", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID := primitive.NewObjectID()
+		a := create(t, repo, blogID, "ada", primitive.NilObjectID, 0)
+		b := create(t, repo, blogID, "alan", primitive.NilObjectID, 1)
+		c := create(t, repo, blogID, "grace", primitive.NilObjectID, 2)
+		for i := range 3 {
+			create(t, repo, blogID, "ada", a.ID, 3+i)
+		}
+		create(t, repo, blogID, "alan", b.ID, 6)
+		create(t, repo, blogID, "grace", c.ID, 7)
+
+		counts, err := repo.CountByParents(ctx, []primitive.ObjectID{a.ID, b.ID, primitive.NewObjectID()})
+		requireNoError(t, err, "CountByParents")
+		assertEqual(t, len(counts), 2, "len(counts)")
+		assertEqual(t, counts[a.ID], int64(3), "counts[a]")
+		assertEqual(t, counts[b.ID], int64(1), "counts[b]")
+	})
+
+	t.Run("FindByUser", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID := primitive.NewObjectID()
+		oldest := create(t, repo, blogID, "ada", primitive.NilObjectID, 0)
+		middle := create(t, repo, primitive.NewObjectID(), "ada", primitive.NilObjectID, 1)
+		newest := create(t, repo, blogID, "ada", oldest.ID, 2)
+		create(t, repo, blogID, "alan", primitive.NilObjectID, 3)
+
+		comments, total, err := repo.FindByUser(ctx, "ada", 2)
+		requireNoError(t, err, "FindByUser")
+		assertOrder(t, commentIDs(comments), []primitive.ObjectID{newest.ID, middle.ID}, "comments")
+		assertEqual(t, total, int64(3), "total")
+
+		comments, total, err = repo.FindByUser(ctx, "nobody", 2)
+		requireNoError(t, err, "FindByUser unknown user")
+		assertEqual(t, len(comments), 0, "len(comments)")
+		assertEqual(t, total, int64(0), "total")
+	})
+
+	t.Run("DeleteByBlog", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+		root := create(t, repo, blogID, "ada", primitive.NilObjectID, 0)
+		reply := create(t, repo, blogID, "alan", root.ID, 1)
+		other := create(t, repo, otherID, "ada", primitive.NilObjectID, 2)
+
+		requireNoError(t, repo.DeleteByBlog(ctx, blogID), "DeleteByBlog")
+		for _, id := range []primitive.ObjectID{root.ID, reply.ID} {
+			_, err := repo.FindByID(ctx, id)
+			requireErrorIs(t, err, comment.ErrCommentNotFound, "FindByID deleted")
+		}
+		_, err := repo.FindByID(ctx, other.ID)
+		requireNoError(t, err, "FindByID other blog")
+
+		requireNoError(t, repo.DeleteByBlog(ctx, blogID), "DeleteByBlog again")
+	})
+}
@@ -0,0 +1,94 @@
+// Package repotest holds the conformance suites every repository
+// implementation must pass. Each suite takes a constructor returning an empty
+// repository and is run against both the MongoDB and the in-memory
+// implementations, so the two cannot drift apart:
+//
+//	func TestMemoryRepository(t *testing.T) {
+//		repotest.TestUserRepository(t, func(t *testing.T) model.UserRepository {
+//			return user.NewMemoryRepository()
+//		})
+//	}
+package repotest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// baseTime is the creation time of test fixtures. MongoDB stores times with
+// millisecond precision in UTC, so fixtures stay within it.
+var baseTime = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// at returns the fixture time offset by n minutes
+func at(n int) time.Time {
+	return baseTime.Add(time.Duration(n) * time.Minute)
+}
+
+// requireNoError fails the test immediately if err is not nil
+func requireNoError(t *testing.T, err error, action string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", action, err)
+	}
+}
+
+// requireErrorIs fails the test immediately unless err wraps target
+func requireErrorIs(t *testing.T, err, target error, action string) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Fatalf("%s: got error %v, want %v", action, err, target)
+	}
+}
+
+// assertEqual reports a mismatch between got and want
+func assertEqual[T comparable](t *testing.T, got, want T, what string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("%s = %v, want %v", what, got, want)
+	}
+}
+
+// assertTime reports a mismatch between two times at MongoDB's precision
+func assertTime(t *testing.T, got, want time.Time, what string) {
+	t.Helper()
+	if !got.Truncate(time.Millisecond).Equal(want.Truncate(time.Millisecond)) {
+		t.Errorf("%s = %v, want %v", what, got, want)
+	}
+}
+
+// assertElements reports whether got and want hold different elements,
+// ignoring order. Nil and empty slices are considered equal.
+func assertElements[T comparable](t *testing.T, got, want []T, what string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s = %v, want %v", what, got, want)
+		return
+	}
+	for _, element := range want {
+		if !slices.Contains(got, element) {
+			t.Errorf("%s = %v, want %v", what, got, want)
+			return
+		}
+	}
+}
+
+// assertOrder reports whether ids differ from want, element by element
+func assertOrder(t *testing.T, ids, want []primitive.ObjectID, what string) {
+	t.Helper()
+	if !slices.Equal(ids, want) {
+		t.Errorf("%s = %s, want %s", what, hexes(ids), hexes(want))
+	}
+}
+
+// hexes formats ObjectIDs for failure messages
+func hexes(ids []primitive.ObjectID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.Hex()
+	}
+	return out
+}
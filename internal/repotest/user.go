@@ -0,0 +1,310 @@
+package repotest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestUserRepository checks that a model.UserRepository behaves like the
+// MongoDB implementation. newRepo is called once per subtest and must return
+// an empty repository.
+func TestUserRepository(t *testing.T, newRepo func(t *testing.T) model.UserRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, repo model.UserRepository, id, name, email string, minute int) *model.User {
+		t.Helper()
+		u := model.NewUser(id, name, email, "")
+		u.CreatedAt, u.UpdatedAt = at(minute), at(minute)
+		requireNoError(t, repo.Create(ctx, u), "Create "+id)
+		return u
+	}
+
+	t.Run("FindMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.FindByID(ctx, "missing")
+		requireErrorIs(t, err, user.ErrUserNotFound, "FindByID")
+		_, err = repo.FindByEmail(ctx, "missing@example.com")
+		requireErrorIs(t, err, user.ErrUserNotFound, "FindByEmail")
+	})
+
+	t.Run("CreateAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		created := create(t, repo, "u1", "Ada", "ada@example.com", 0)
+
+		for name, find := range map[string]func() (*model.User, error){
+			"FindByID":    func() (*model.User, error) { return repo.FindByID(ctx, "u1") },
+			"FindByEmail": func() (*model.User, error) { return repo.FindByEmail(ctx, "ada@example.com") },
+		} {
+			found, err := find()
+			requireNoError(t, err, name)
+			assertEqual(t, found.ID, created.ID, name+" ID")
+			assertEqual(t, found.Name, created.Name, name+" Name")
+			assertEqual(t, found.Email, created.Email, name+" Email")
+			assertTime(t, found.CreatedAt, created.CreatedAt, name+" CreatedAt")
+			assertElements(t, found.Roles, model.DefaultRoles, name+" Roles")
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "u1", "Ada", "ada@example.com", 0)
+
+		err := repo.Create(ctx, model.NewUser("u1", "Other", "other@example.com", ""))
+		requireErrorIs(t, err, user.ErrUserExists, "Create duplicate")
+
+		found, err := repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.Name, "Ada", "Name")
+	})
+
+	t.Run("DuplicateEmail", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "u1", "Ada", "ada@example.com", 0)
+		other := create(t, repo, "u2", "Alan", "alan@example.com", 1)
+
+		err := repo.Create(ctx, model.NewUser("u3", "Impostor", "ada@example.com", ""))
+		requireErrorIs(t, err, user.ErrEmailTaken, "Create duplicate email")
+		_, err = repo.FindByID(ctx, "u3")
+		requireErrorIs(t, err, user.ErrUserNotFound, "FindByID")
+
+		other.Email = "ada@example.com"
+		requireErrorIs(t, repo.Update(ctx, other), user.ErrEmailTaken, "Update to duplicate email")
+		found, err := repo.FindByID(ctx, "u2")
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.Email, "alan@example.com", "Email")
+
+		// Users without an email do not collide
+		create(t, repo, "u4", "Anonymous", "", 2)
+		create(t, repo, "u5", "Anonymous", "", 3)
+	})
+
+	t.Run("ReturnsCopies", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "u1", "Ada", "ada@example.com", 0)
+
+		found, err := repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		found.Name = "Changed"
+		found.Likes = append(found.Likes, primitive.NewObjectID())
+
+		again, err := repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, again.Name, "Ada", "Name")
+		assertEqual(t, len(again.Likes), 0, "len(Likes)")
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		u := create(t, repo, "u1", "Ada", "ada@example.com", 0)
+
+		u.Name = "Ada Lovelace"
+		u.PhotoURL = "https://example.com/ada.png"
+		requireNoError(t, repo.Update(ctx, u), "Update")
+		if !u.UpdatedAt.After(at(0)) {
+			t.Errorf("UpdatedAt = %v, want it bumped", u.UpdatedAt)
+		}
+
+		found, err := repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.Name, "Ada Lovelace", "Name")
+		assertEqual(t, found.PhotoURL, "https://example.com/ada.png", "PhotoURL")
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		repo := newRepo(t)
+
+		requireNoError(t, repo.Update(ctx, model.NewUser("missing", "Nobody", "nobody@example.com", "")), "Update")
+		_, err := repo.FindByID(ctx, "missing")
+		requireErrorIs(t, err, user.ErrUserNotFound, "FindByID")
+	})
+
+	t.Run("Bookmarks", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "u1", "Ada", "ada@example.com", 0)
+		first, second := primitive.NewObjectID(), primitive.NewObjectID()
+
+		// Adding is idempotent, like $addToSet
+		requireNoError(t, repo.AddBookmark(ctx, "u1", first), "AddBookmark")
+		requireNoError(t, repo.AddBookmark(ctx, "u1", first), "AddBookmark again")
+		requireNoError(t, repo.AddBookmark(ctx, "u1", second), "AddBookmark second")
+		found, err := repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertElements(t, found.Bookmarks, []primitive.ObjectID{first, second}, "Bookmarks")
+
+		requireNoError(t, repo.RemoveBookmark(ctx, "u1", first), "RemoveBookmark")
+		requireNoError(t, repo.RemoveBookmark(ctx, "u1", first), "RemoveBookmark again")
+		found, err = repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertElements(t, found.Bookmarks, []primitive.ObjectID{second}, "Bookmarks")
+	})
+
+	t.Run("Likes", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "u1", "Ada", "ada@example.com", 0)
+		blogID := primitive.NewObjectID()
+
+		requireNoError(t, repo.AddLike(ctx, "u1", blogID), "AddLike")
+		requireNoError(t, repo.AddLike(ctx, "u1", blogID), "AddLike again")
+		found, err := repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertElements(t, found.Likes, []primitive.ObjectID{blogID}, "Likes")
+
+		requireNoError(t, repo.RemoveLike(ctx, "u1", blogID), "RemoveLike")
+		found, err = repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertElements(t, found.Likes, nil, "Likes")
+	})
+
+	t.Run("RemoveBlog", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "u1", "Ada", "ada@example.com", 0)
+		create(t, repo, "u2", "Alan", "alan@example.com", 1)
+		deleted, kept := primitive.NewObjectID(), primitive.NewObjectID()
+		requireNoError(t, repo.AddLike(ctx, "u1", deleted), "AddLike")
+		requireNoError(t, repo.AddLike(ctx, "u1", kept), "AddLike kept")
+		requireNoError(t, repo.AddBookmark(ctx, "u1", deleted), "AddBookmark")
+		requireNoError(t, repo.AddBookmark(ctx, "u2", deleted), "AddBookmark u2")
+		requireNoError(t, repo.AddBookmark(ctx, "u2", kept), "AddBookmark u2 kept")
+
+		requireNoError(t, repo.RemoveBlog(ctx, deleted), "RemoveBlog")
+		first, err := repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID u1")
+		assertElements(t, first.Likes, []primitive.ObjectID{kept}, "u1 Likes")
+		assertElements(t, first.Bookmarks, nil, "u1 Bookmarks")
+		second, err := repo.FindByID(ctx, "u2")
+		requireNoError(t, err, "FindByID u2")
+		assertElements(t, second.Likes, nil, "u2 Likes")
+		assertElements(t, second.Bookmarks, []primitive.ObjectID{kept}, "u2 Bookmarks")
+	})
+
+	t.Run("ModifyMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		blogID := primitive.NewObjectID()
+
+		requireNoError(t, repo.AddBookmark(ctx, "missing", blogID), "AddBookmark")
+		requireNoError(t, repo.AddLike(ctx, "missing", blogID), "AddLike")
+		requireNoError(t, repo.SetRoles(ctx, "missing", []model.Role{model.RoleAdmin}), "SetRoles")
+		requireNoError(t, repo.SetDisabled(ctx, "missing", true), "SetDisabled")
+		_, err := repo.FindByID(ctx, "missing")
+		requireErrorIs(t, err, user.ErrUserNotFound, "FindByID")
+	})
+
+	t.Run("SetRoles", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "u1", "Ada", "ada@example.com", 0)
+
+		requireNoError(t, repo.SetRoles(ctx, "u1", []model.Role{model.RoleReader, model.RoleAdmin}), "SetRoles")
+		found, err := repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertElements(t, found.Roles, []model.Role{model.RoleReader, model.RoleAdmin}, "Roles")
+		assertEqual(t, found.IsAdmin, true, "IsAdmin")
+
+		requireNoError(t, repo.SetRoles(ctx, "u1", []model.Role{model.RoleAuthor}), "SetRoles")
+		found, err = repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertElements(t, found.Roles, []model.Role{model.RoleAuthor}, "Roles")
+		assertEqual(t, found.IsAdmin, false, "IsAdmin")
+	})
+
+	t.Run("SetDisabled", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "u1", "Ada", "ada@example.com", 0)
+
+		requireNoError(t, repo.SetDisabled(ctx, "u1", true), "SetDisabled")
+		found, err := repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.Disabled, true, "Disabled")
+
+		requireNoError(t, repo.SetDisabled(ctx, "u1", false), "SetDisabled")
+		found, err = repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertEqual(t, found.Disabled, false, "Disabled")
+	})
+
+	t.Run("List", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "ada", "Ada Lovelace", "ada@example.com", 0)
+		create(t, repo, "alan", "Alan Turing", "alan@example.org", 1)
+		create(t, repo, "grace", "Grace Hopper", "grace@navy.example", 2)
+
+		// A user saved before roles existed, with only the legacy admin flag
+		legacy := model.NewUser("edsger", "Edsger Dijkstra", "edsger@example.com", "")
+		legacy.Roles = nil
+		legacy.IsAdmin = true
+		legacy.CreatedAt = at(3)
+		requireNoError(t, repo.Create(ctx, legacy), "Create legacy")
+
+		requireNoError(t, repo.SetRoles(ctx, "alan", []model.Role{model.RoleModerator}), "SetRoles")
+		requireNoError(t, repo.SetDisabled(ctx, "grace", true), "SetDisabled")
+
+		disabled, enabled := true, false
+		cases := []struct {
+			name   string
+			filter model.UserListFilter
+			want   []string
+			total  int64
+		}{
+			{"All", model.UserListFilter{}, []string{"edsger", "grace", "alan", "ada"}, 4},
+			{"QueryName", model.UserListFilter{Query: "LOVE"}, []string{"ada"}, 1},
+			{"QueryEmail", model.UserListFilter{Query: "example.org"}, []string{"alan"}, 1},
+			{"QueryLiteral", model.UserListFilter{Query: "a.a"}, []string{}, 0},
+			{"RoleAssigned", model.UserListFilter{Role: model.RoleModerator}, []string{"alan"}, 1},
+			{"RoleDefault", model.UserListFilter{Role: model.RoleAuthor}, []string{"edsger", "grace", "ada"}, 3},
+			{"RoleLegacyAdmin", model.UserListFilter{Role: model.RoleAdmin}, []string{"edsger"}, 1},
+			{"Disabled", model.UserListFilter{Disabled: &disabled}, []string{"grace"}, 1},
+			{"Enabled", model.UserListFilter{Disabled: &enabled}, []string{"edsger", "alan", "ada"}, 3},
+			{"Page", model.UserListFilter{Limit: 2, Offset: 1}, []string{"grace", "alan"}, 4},
+			{"PastEnd", model.UserListFilter{Limit: 2, Offset: 10}, []string{}, 4},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				users, total, err := repo.List(ctx, &tc.filter)
+				requireNoError(t, err, "List")
+				ids := make([]string, 0, len(users))
+				for _, u := range users {
+					ids = append(ids, u.ID)
+				}
+				if !slices.Equal(ids, tc.want) {
+					t.Errorf("List IDs = %v, want %v", ids, tc.want)
+				}
+				assertEqual(t, total, tc.total, "total")
+			})
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "u1", "Ada", "ada@example.com", 0)
+		blogIDs := make([]primitive.ObjectID, 20)
+		for i := range blogIDs {
+			blogIDs[i] = primitive.NewObjectID()
+		}
+
+		done := make(chan error, len(blogIDs)*2)
+		for _, id := range blogIDs {
+			for range 2 {
+				go func() { done <- repo.AddLike(ctx, "u1", id) }()
+			}
+		}
+		timeout := time.After(10 * time.Second)
+		for range len(blogIDs) * 2 {
+			select {
+			case err := <-done:
+				requireNoError(t, err, "AddLike")
+			case <-timeout:
+				t.Fatal("concurrent AddLike calls did not finish")
+			}
+		}
+
+		found, err := repo.FindByID(ctx, "u1")
+		requireNoError(t, err, "FindByID")
+		assertElements(t, found.Likes, blogIDs, "Likes")
+	})
+}
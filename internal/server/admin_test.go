@@ -0,0 +1,139 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/server/servertest"
+)
+
+func TestAdminGuards(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("admin", model.RoleAdmin)
+	h.CreateUser("editor", model.RoleEditor)
+	h.CreateUser("reader", model.RoleReader)
+
+	routes := []struct {
+		method, path string
+	}{
+		{http.MethodGet, "/api/v1/admin/users"},
+		{http.MethodGet, "/api/v1/admin/users/reader/activity"},
+		{http.MethodPut, "/api/v1/admin/users/reader/roles"},
+		{http.MethodPost, "/api/v1/admin/users/reader/roles/editor"},
+		{http.MethodDelete, "/api/v1/admin/users/reader/roles/reader"},
+		{http.MethodPost, "/api/v1/admin/users/reader/disable"},
+		{http.MethodPost, "/api/v1/admin/users/reader/enable"},
+		{http.MethodPost, "/api/v1/admin/users/reader/revoke-tokens"},
+	}
+	for _, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			h.Request(route.method, route.path).Do().
+				AssertProblem(http.StatusUnauthorized, "missing_authorization")
+			for _, uid := range []string{"reader", "editor"} {
+				h.Request(route.method, route.path).As(uid).Do().
+					AssertProblem(http.StatusForbidden, "permission_denied")
+			}
+		})
+	}
+
+	h.Request(http.MethodGet, "/api/v1/admin/users").As("admin").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("total", 3).
+		AssertJSONLen("users", 3)
+	h.Request(http.MethodGet, "/api/v1/admin/users?role=editor").As("admin").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("total", 1).
+		AssertJSON("users.0.id", "editor")
+}
+
+func TestAdminManagesRoles(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("admin", model.RoleAdmin)
+	h.CreateUser("reader", model.RoleReader)
+
+	// Readers cannot write blogs until an admin promotes them
+	blog := map[string]string{"title": "My First Post", "content": "Hello"}
+	h.Request(http.MethodPost, "/api/v1/blogs").As("reader").JSON(blog).Do().
+		AssertProblem(http.StatusForbidden, "permission_denied")
+
+	h.Request(http.MethodPost, "/api/v1/admin/users/reader/roles/author").As("admin").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("roles", []string{"reader", "author"})
+	if claims := h.Auth.Claims("reader"); claims["roles"] == nil {
+		t.Errorf("role claims not mirrored: %v", claims)
+	}
+
+	h.Request(http.MethodPost, "/api/v1/blogs").As("reader").JSON(blog).Do().
+		AssertStatus(http.StatusCreated).
+		AssertJSON("author_id", "reader")
+
+	// Admins may not lock themselves out
+	h.Request(http.MethodPut, "/api/v1/admin/users/admin/roles").As("admin").JSON(map[string][]string{"roles": {"reader"}}).Do().
+		AssertProblem(http.StatusForbidden, "self_modification")
+	h.Request(http.MethodPost, "/api/v1/admin/users/reader/roles/overlord").As("admin").Do().
+		AssertProblem(http.StatusBadRequest, "invalid_role")
+}
+
+func TestAdminDisablesUser(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("admin", model.RoleAdmin)
+	h.CreateUser("reader", model.RoleReader)
+	token := h.Auth.Token("reader")
+
+	h.Request(http.MethodGet, "/api/v1/user/profile").Token(token).Do().AssertStatus(http.StatusOK)
+
+	h.Request(http.MethodPost, "/api/v1/admin/users/admin/disable").As("admin").Do().
+		AssertProblem(http.StatusForbidden, "self_modification")
+	h.Request(http.MethodPost, "/api/v1/admin/users/reader/disable").As("admin").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("disabled", true)
+
+	// Disabling ends existing sessions and refuses new ones
+	h.Request(http.MethodGet, "/api/v1/user/profile").Token(token).Do().
+		AssertProblem(http.StatusForbidden, "account_disabled")
+
+	h.Request(http.MethodPost, "/api/v1/admin/users/reader/enable").As("admin").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("disabled", false)
+	h.Request(http.MethodGet, "/api/v1/user/profile").Token(token).Do().
+		AssertProblem(http.StatusUnauthorized, "token_revoked")
+	h.Request(http.MethodGet, "/api/v1/user/profile").As("reader").Do().
+		AssertStatus(http.StatusOK)
+}
+
+func TestAdminRolesComeFromUserRecord(t *testing.T) {
+	h := servertest.New(t)
+	ctx := context.Background()
+	h.CreateUser("root", model.RoleAdmin)
+	h.CreateUser("ops", model.RoleAdmin)
+	h.CreateUser("legacy", model.RoleReader)
+	for _, uid := range []string{"ops", "legacy"} {
+		if err := h.Auth.SetCustomUserClaims(ctx, uid, map[string]interface{}{"admin": true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ops, legacy := h.Auth.Token("ops"), h.Auth.Token("legacy")
+
+	// The boolean admin claim grants nothing the user record does not
+	h.Request(http.MethodGet, "/api/v1/admin/users").Token(ops).Do().AssertStatus(http.StatusOK)
+	h.Request(http.MethodGet, "/api/v1/admin/users").Token(legacy).Do().
+		AssertProblem(http.StatusForbidden, "permission_denied")
+
+	// Demoting an admin applies to their next request, whatever their token says
+	h.Request(http.MethodDelete, "/api/v1/admin/users/ops/roles/admin").As("root").Do().
+		AssertStatus(http.StatusOK)
+	if claims := h.Auth.Claims("ops"); claims["admin"] != false {
+		t.Errorf("admin claim not cleared: %v", claims)
+	}
+	h.Request(http.MethodGet, "/api/v1/admin/users").Token(ops).Do().
+		AssertProblem(http.StatusForbidden, "permission_denied")
+
+	// Roles changed directly in the database apply just the same
+	if err := h.Users.SetRoles(ctx, "root", []model.Role{model.RoleReader}); err != nil {
+		t.Fatal(err)
+	}
+	h.Request(http.MethodGet, "/api/v1/admin/users").As("root").Do().
+		AssertProblem(http.StatusForbidden, "permission_denied")
+}
@@ -0,0 +1,155 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/server/servertest"
+)
+
+func TestUpdateBlogVersion(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	blog := h.CreateBlog("author", "Versioned")
+	path := "/api/v1/blogs/" + blog.ID.Hex()
+
+	resp := h.Request(http.MethodGet, path).Do().AssertStatus(http.StatusOK)
+	if got := resp.Recorder.Header().Get("ETag"); got != `"1"` {
+		t.Errorf("ETag = %s, want \"1\"", got)
+	}
+
+	h.Request(http.MethodPut, path).As("author").JSON(map[string]interface{}{"content": "First edit", "version": 1}).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("version", 2)
+
+	// A second client still editing version 1 must reload first
+	h.Request(http.MethodPut, path).As("author").JSON(map[string]interface{}{"content": "Lost edit", "version": 1}).Do().
+		AssertProblem(http.StatusConflict, "version_conflict")
+	h.Request(http.MethodPut, path).As("author").Header("If-Match", `"1"`).JSON(map[string]string{"content": "Lost edit"}).Do().
+		AssertProblem(http.StatusConflict, "version_conflict")
+	h.Request(http.MethodPut, path).As("author").Header("If-Match", "latest").JSON(map[string]string{"content": "Lost edit"}).Do().
+		AssertProblem(http.StatusBadRequest, "invalid_if_match")
+
+	resp = h.Request(http.MethodPut, path).As("author").Header("If-Match", `"2"`).JSON(map[string]string{"content": "Second edit"}).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("version", 3).
+		AssertJSON("content", "Second edit")
+	if got := resp.Recorder.Header().Get("ETag"); got != `"3"` {
+		t.Errorf("ETag = %s, want \"3\"", got)
+	}
+
+	h.Request(http.MethodPost, path+"/revisions/1/restore").As("author").Header("If-Match", `"2"`).Do().
+		AssertProblem(http.StatusConflict, "version_conflict")
+	h.Request(http.MethodPost, path+"/revisions/1/restore").As("author").Header("If-Match", `"3"`).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("version", 4).
+		AssertJSON("content", "Content of Versioned")
+}
+
+func TestUpdateBlogStaleCache(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	blog := h.CreateBlog("author", "Cached")
+	path := "/api/v1/blogs/" + blog.ID.Hex()
+
+	// Cache version 1, then save version 2 behind the cache's back, as
+	// another instance would
+	h.Request(http.MethodGet, path).Do().AssertJSON("version", 1)
+	blog.Version = 2
+	if err := h.Blogs.Update(context.Background(), blog, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Edits start from the stored version, not the cached one
+	h.Request(http.MethodPut, path).As("author").JSON(map[string]string{"content": "Edited"}).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("version", 3)
+	h.Request(http.MethodGet, path).Do().
+		AssertJSON("version", 3).
+		AssertJSON("content", "Edited")
+}
+
+func TestUpdateBlogByDisabledAuthor(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	blog := h.CreateBlog("author", "Abandoned")
+	path := "/api/v1/blogs/" + blog.ID.Hex()
+
+	// Editing needs no permission beyond authorship, yet a disabled author
+	// is turned away before the handler runs, whatever their token says
+	if err := h.Users.SetDisabled(t.Context(), "author", true); err != nil {
+		t.Fatal(err)
+	}
+	h.Request(http.MethodPut, path).As("author").JSON(map[string]string{"content": "Vandalized"}).Do().
+		AssertProblem(http.StatusForbidden, "account_disabled")
+	h.Request(http.MethodGet, path).Do().
+		AssertJSON("version", 1).
+		AssertJSON("content", "Content of Abandoned")
+}
+
+func TestDeleteBlogRemovesCommentsAndReactions(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("admin", model.RoleAdmin)
+	h.CreateUser("author")
+	h.CreateUser("reader", model.RoleReader)
+	deleted := h.CreateBlog("author", "Short Lived")
+	kept := h.CreateBlog("author", "Here To Stay")
+
+	for _, blog := range []*model.Blog{deleted, kept} {
+		id := blog.ID.Hex()
+		h.Request(http.MethodPost, "/api/v1/comments").As("reader").JSON(map[string]string{"blog_id": id, "content": "Nice"}).Do().
+			AssertStatus(http.StatusCreated)
+		h.Request(http.MethodPost, "/api/v1/blogs/"+id+"/like").As("reader").Do().AssertStatus(http.StatusOK)
+		h.Request(http.MethodPost, "/api/v1/blogs/"+id+"/bookmark").As("reader").Do().AssertStatus(http.StatusOK)
+	}
+	h.Request(http.MethodGet, "/api/v1/admin/users/reader/activity").As("admin").Do().
+		AssertJSON("comment_count", 2).
+		AssertJSON("like_count", 2).
+		AssertJSON("bookmark_count", 2)
+
+	h.Request(http.MethodDelete, "/api/v1/blogs/"+deleted.ID.Hex()).As("author").Do().
+		AssertStatus(http.StatusNoContent)
+
+	h.Request(http.MethodGet, "/api/v1/user/profile").As("reader").Do().
+		AssertJSON("likes_count", 1).
+		AssertJSON("bookmarks_count", 1)
+	h.Request(http.MethodGet, "/api/v1/admin/users/reader/activity").As("admin").Do().
+		AssertJSON("comment_count", 1).
+		AssertJSON("like_count", 1).
+		AssertJSON("bookmark_count", 1)
+	comments, _, err := h.Comments.FindRoots(context.Background(), deleted.ID, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 0 {
+		t.Errorf("%d comments left on the deleted blog", len(comments))
+	}
+}
+
+func TestGetBlogBySlug(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	blog := h.CreateBlog("author", "Йошкар-Ола")
+	if blog.Slug != "йошкар-ола" {
+		t.Fatalf("Slug = %q, want йошкар-ола", blog.Slug)
+	}
+
+	h.Request(http.MethodGet, "/api/v1/blogs/by-slug/"+url.PathEscape(blog.Slug)).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("id", blog.ID.Hex())
+
+	// A decomposed or uppercased slug redirects to the canonical one
+	for _, slug := range []string{"\u0438\u0306ошкар-ола", "\u0418\u0306ошкар-Ола"} {
+		resp := h.Request(http.MethodGet, "/api/v1/blogs/by-slug/"+url.PathEscape(slug)).Do().
+			AssertStatus(http.StatusMovedPermanently)
+		if got, want := resp.Recorder.Header().Get("Location"), "/api/v1/blogs/by-slug/"+url.PathEscape(blog.Slug); got != want {
+			t.Errorf("Location = %s, want %s", got, want)
+		}
+	}
+
+	h.Request(http.MethodGet, "/api/v1/blogs/by-slug/missing").Do().
+		AssertProblem(http.StatusNotFound, "blog_not_found")
+}
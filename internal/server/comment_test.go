@@ -0,0 +1,135 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/server/servertest"
+)
+
+func TestCommentTreeRepliesLimit(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	h.CreateUser("reader", model.RoleReader)
+	blog := h.CreateBlog("author", "Busy Thread")
+
+	comment := func(content, parentID string) string {
+		input := map[string]string{"blog_id": blog.ID.Hex(), "content": content, "parent_id": parentID}
+		resp := h.Request(http.MethodPost, "/api/v1/comments").As("reader").JSON(input).Do().
+			AssertStatus(http.StatusCreated)
+		return resp.JSON("id").(string)
+	}
+	busy := comment("Busy", "")
+	quiet := comment("Quiet", "")
+	first := comment("First reply", busy)
+	second := comment("Second reply", busy)
+	comment("Third reply", busy)
+	only := comment("Only reply", quiet)
+
+	path := "/api/v1/blogs/" + blog.ID.Hex() + "/comments?replies_limit=2"
+	h.Request(http.MethodGet, path).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("total", 2).
+		AssertJSON("comments.0.id", busy).
+		AssertJSON("comments.0.reply_count", 3).
+		AssertJSON("comments.0.has_more_replies", true).
+		AssertJSONLen("comments.0.replies", 2).
+		AssertJSON("comments.0.replies.0.id", first).
+		AssertJSON("comments.0.replies.1.id", second).
+		AssertJSON("comments.1.reply_count", 1).
+		AssertJSON("comments.1.has_more_replies", false).
+		AssertJSON("comments.1.replies.0.id", only)
+}
+
+func TestCommentTreeDepthCap(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	h.CreateUser("reader", model.RoleReader)
+	blog := h.CreateBlog("author", "Deep Thread")
+
+	// Seven nested comments, two more than the configured maximum depth of 5
+	var ids []string
+	parentID := ""
+	for range 7 {
+		input := map[string]string{"blog_id": blog.ID.Hex(), "content": "Deeper", "parent_id": parentID}
+		resp := h.Request(http.MethodPost, "/api/v1/comments").As("reader").JSON(input).Do().
+			AssertStatus(http.StatusCreated)
+		parentID = resp.JSON("id").(string)
+		ids = append(ids, parentID)
+	}
+
+	// Asking for more levels than allowed still stops at the fifth, which
+	// only reports its hidden reply
+	deepest := "comments.0.replies.0.replies.0.replies.0.replies.0"
+	h.Request(http.MethodGet, "/api/v1/blogs/"+blog.ID.Hex()+"/comments?depth=10").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON(deepest+".id", ids[4]).
+		AssertJSON(deepest+".reply_count", 1).
+		AssertJSON(deepest+".has_more_replies", true).
+		AssertJSONLen(deepest+".replies", 0)
+
+	// The rest of the thread is loaded by rooting the tree at the last node
+	h.Request(http.MethodGet, "/api/v1/blogs/"+blog.ID.Hex()+"/comments?depth=2&parent_id="+ids[4]).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("total", 1).
+		AssertJSON("comments.0.id", ids[5]).
+		AssertJSON("comments.0.replies.0.id", ids[6]).
+		AssertJSON("comments.0.replies.0.has_more_replies", false)
+}
+
+func TestCommentParentMismatch(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	h.CreateUser("reader", model.RoleReader)
+	first := h.CreateBlog("author", "First")
+	second := h.CreateBlog("author", "Second")
+
+	resp := h.Request(http.MethodPost, "/api/v1/comments").As("reader").
+		JSON(map[string]string{"blog_id": first.ID.Hex(), "content": "On the first blog"}).Do().
+		AssertStatus(http.StatusCreated)
+	parentID := resp.JSON("id").(string)
+
+	// A reply must be on the same blog as its parent
+	h.Request(http.MethodPost, "/api/v1/comments").As("reader").
+		JSON(map[string]string{"blog_id": second.ID.Hex(), "content": "Misplaced", "parent_id": parentID}).Do().
+		AssertProblem(http.StatusBadRequest, "parent_mismatch")
+	h.Request(http.MethodGet, "/api/v1/blogs/"+second.ID.Hex()+"/comments?parent_id="+parentID).Do().
+		AssertProblem(http.StatusBadRequest, "parent_mismatch")
+	h.Request(http.MethodGet, "/api/v1/blogs/"+second.ID.Hex()+"/comments").Do().
+		AssertJSON("total", 0)
+}
+
+func TestCommentRepliesPaging(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	h.CreateUser("reader", model.RoleReader)
+	blog := h.CreateBlog("author", "Paged Thread")
+
+	comment := func(content, parentID string) string {
+		input := map[string]string{"blog_id": blog.ID.Hex(), "content": content, "parent_id": parentID}
+		resp := h.Request(http.MethodPost, "/api/v1/comments").As("reader").JSON(input).Do().
+			AssertStatus(http.StatusCreated)
+		return resp.JSON("id").(string)
+	}
+	root := comment("Root", "")
+	var replies []string
+	for _, content := range []string{"First", "Second", "Third", "Fourth", "Fifth"} {
+		replies = append(replies, comment(content, root))
+	}
+	nested := comment("Nested", replies[3])
+
+	// Replies past the ones shown inline are paged like root comments
+	path := "/api/v1/blogs/" + blog.ID.Hex() + "/comments?parent_id=" + root
+	h.Request(http.MethodGet, path+"&limit=2&offset=3").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("total", 5).
+		AssertJSONLen("comments", 2).
+		AssertJSON("comments.0.id", replies[3]).
+		AssertJSON("comments.0.reply_count", 1).
+		AssertJSON("comments.0.replies.0.id", nested).
+		AssertJSON("comments.1.id", replies[4])
+	h.Request(http.MethodGet, path+"&limit=2&offset=5").Do().
+		AssertJSON("total", 5).
+		AssertJSONLen("comments", 0)
+}
@@ -0,0 +1,139 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/server/servertest"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestToggleLike(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	h.CreateUser("reader", model.RoleReader)
+	blog := h.CreateBlog("author", "Liked Blog")
+	path := "/api/v1/blogs/" + blog.ID.Hex() + "/like"
+	h.Request(http.MethodGet, "/api/v1/blogs").Do().
+		AssertJSON("blogs.0.like_count", 0)
+
+	h.Request(http.MethodPost, path).As("reader").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("blog_id", blog.ID.Hex()).
+		AssertJSON("liked", true).
+		AssertJSON("like_count", 1)
+	// Reactions leave cached listings alone; they catch up once they expire
+	h.Request(http.MethodGet, "/api/v1/blogs").Do().
+		AssertJSON("blogs.0.like_count", 0)
+	h.Request(http.MethodGet, "/api/v1/user/profile").As("reader").Do().
+		AssertJSON("likes_count", 1)
+	h.Request(http.MethodGet, "/api/v1/blogs/"+blog.ID.Hex()).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("like_count", 1)
+
+	// A second like from someone else counts separately
+	h.Request(http.MethodPost, path).As("author").Do().
+		AssertJSON("liked", true).
+		AssertJSON("like_count", 2)
+
+	// Toggling again takes the like back
+	h.Request(http.MethodPost, path).As("reader").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("liked", false).
+		AssertJSON("like_count", 1)
+	h.Request(http.MethodGet, "/api/v1/user/profile").As("reader").Do().
+		AssertJSON("likes_count", 0)
+}
+
+func TestToggleLikeRejects(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("reader", model.RoleReader)
+
+	h.Request(http.MethodPost, "/api/v1/blogs/"+primitive.NewObjectID().Hex()+"/like").Do().
+		AssertProblem(http.StatusUnauthorized, "missing_authorization")
+	h.Request(http.MethodPost, "/api/v1/blogs/not-an-id/like").As("reader").Do().
+		AssertProblem(http.StatusBadRequest, "invalid_blog_id")
+	h.Request(http.MethodPost, "/api/v1/blogs/"+primitive.NewObjectID().Hex()+"/like").As("reader").Do().
+		AssertProblem(http.StatusNotFound, "blog_not_found")
+}
+
+func TestToggleBookmark(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	h.CreateUser("reader", model.RoleReader)
+	first := h.CreateBlog("author", "First")
+	second := h.CreateBlog("author", "Second")
+
+	for _, blog := range []*model.Blog{first, second} {
+		h.Request(http.MethodPost, "/api/v1/blogs/"+blog.ID.Hex()+"/bookmark").As("reader").Do().
+			AssertStatus(http.StatusOK).
+			AssertJSON("bookmarked", true).
+			AssertJSON("bookmark_count", 1).
+			AssertJSON("liked", false)
+	}
+	h.Request(http.MethodGet, "/api/v1/user/bookmarks").As("reader").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("user", "reader@example.com").
+		AssertJSON("bookmarks", []string{first.ID.Hex(), second.ID.Hex()})
+
+	h.Request(http.MethodPost, "/api/v1/blogs/"+first.ID.Hex()+"/bookmark").As("reader").Do().
+		AssertJSON("bookmarked", false).
+		AssertJSON("bookmark_count", 0)
+	h.Request(http.MethodGet, "/api/v1/user/bookmarks").As("reader").Do().
+		AssertJSON("bookmarks", []string{second.ID.Hex()})
+}
+
+func TestReactRequiresPermission(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	blog := h.CreateBlog("author", "Guarded")
+
+	// A disabled account may not react even with a token issued before it
+	// was disabled
+	h.CreateUser("banned")
+	token := h.Auth.Token("banned")
+	if err := h.Users.SetDisabled(t.Context(), "banned", true); err != nil {
+		t.Fatal(err)
+	}
+	h.Request(http.MethodPost, "/api/v1/blogs/"+blog.ID.Hex()+"/like").Token(token).Do().
+		AssertProblem(http.StatusForbidden, "account_disabled")
+}
+
+func TestHiddenBlogReactions(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("author")
+	h.CreateUser("reader", model.RoleReader)
+	h.CreateUser("editor", model.RoleEditor)
+
+	resp := h.Request(http.MethodPost, "/api/v1/blogs").As("author").
+		JSON(map[string]interface{}{"title": "Unfinished", "content": "Not yet", "status": "draft"}).Do().
+		AssertStatus(http.StatusCreated)
+	blogID, _ := resp.JSON("id").(string)
+	path := "/api/v1/blogs/" + blogID
+
+	// Others cannot react to, comment on or read comments of a draft, and
+	// cannot tell it exists
+	for _, action := range []string{"/like", "/bookmark"} {
+		h.Request(http.MethodPost, path+action).As("reader").Do().
+			AssertProblem(http.StatusNotFound, "blog_not_found")
+	}
+	h.Request(http.MethodPost, "/api/v1/comments").As("reader").JSON(map[string]string{"blog_id": blogID, "content": "First!"}).Do().
+		AssertProblem(http.StatusNotFound, "blog_not_found")
+	h.Request(http.MethodGet, path+"/comments").Do().
+		AssertProblem(http.StatusNotFound, "blog_not_found")
+	h.Request(http.MethodGet, path+"/comments").As("reader").Do().
+		AssertProblem(http.StatusNotFound, "blog_not_found")
+
+	// The author and viewers allowed to see unpublished blogs can
+	h.Request(http.MethodPost, "/api/v1/comments").As("author").JSON(map[string]string{"blog_id": blogID, "content": "Note to self"}).Do().
+		AssertStatus(http.StatusCreated)
+	h.Request(http.MethodGet, path+"/comments").As("author").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSONLen("comments", 1)
+	h.Request(http.MethodGet, path+"/comments").As("editor").Do().
+		AssertStatus(http.StatusOK)
+	h.Request(http.MethodPost, path+"/like").As("author").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("liked", true)
+}
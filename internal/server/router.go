@@ -0,0 +1,227 @@
+// Package server assembles the HTTP API: the middleware chain, the route
+// groups and their rate limit policies. cmd/server wires it to MongoDB,
+// Redis and the auth provider; servertest wires it to in-memory fakes.
+package server
+
+import (
+	"net/http"
+
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/blog"
+	"github.com/dksensei/letsnormalizeit/internal/comment"
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/health"
+	"github.com/dksensei/letsnormalizeit/internal/metrics"
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/ratelimit"
+	"github.com/dksensei/letsnormalizeit/internal/user"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// Dependencies are the services the router dispatches requests to
+type Dependencies struct {
+	AuthService    model.AuthService
+	UserService    *user.Service
+	BlogService    *blog.Service
+	CommentService *comment.Service
+	RateLimiter    ratelimit.Limiter
+	HealthChecks   *health.Registry
+}
+
+// BuildRouter creates the router serving the whole API. It fails on invalid
+// trusted proxy or rate limit configuration.
+func BuildRouter(cfg *config.Config, deps *Dependencies) (*gin.Engine, error) {
+	authService := deps.AuthService
+	userService := deps.UserService
+	rateLimiter := deps.RateLimiter
+
+	// Initialize handlers
+	userHandler := user.NewHandler(userService)
+	blogHandler := blog.NewHandler(deps.BlogService)
+	commentHandler := comment.NewHandler(deps.CommentService)
+	healthHandler := health.NewHandler(deps.HealthChecks)
+
+	// Initialize the rate limit policies of each route group
+	anonymousLimit, err := ratelimit.NewPolicy("anonymous", cfg.RateLimit.Anonymous)
+	if err != nil {
+		return nil, err
+	}
+	authenticatedLimit, err := ratelimit.NewPolicy("authenticated", cfg.RateLimit.Authenticated)
+	if err != nil {
+		return nil, err
+	}
+	authLimit, err := ratelimit.NewPolicy("auth", cfg.RateLimit.Auth)
+	if err != nil {
+		return nil, err
+	}
+	adminLimit, err := ratelimit.NewPolicy("admin", cfg.RateLimit.Admin)
+	if err != nil {
+		return nil, err
+	}
+	preAuthLimit, err := ratelimit.NewPolicy("preauth", cfg.RateLimit.PreAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup Gin router
+	router := gin.New() // Use New() instead of Default() to customize middleware
+
+	// Only believe forwarded client IPs from trusted proxies
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, err
+	}
+	router.TrustedPlatform = cfg.Server.TrustedPlatform
+
+	// Use our custom recovery middleware
+	router.Use(middleware.Recovery())
+
+	// Record a span for each request
+	router.Use(middleware.Tracing(&cfg.Tracing))
+
+	// Assign request IDs and request-scoped loggers
+	router.Use(middleware.RequestID())
+
+	// Count requests and observe their latency
+	router.Use(middleware.Metrics())
+
+	// Write structured access logs through the zap logger
+	router.Use(middleware.AccessLog(&cfg.Logger))
+
+	// Configure CORS
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowOrigins = []string{cfg.Server.AllowOrigins}
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", middleware.RequestIDHeader, "traceparent", "tracestate"}
+	corsConfig.ExposeHeaders = []string{middleware.RequestIDHeader}
+	corsConfig.AllowCredentials = true
+	router.Use(cors.New(corsConfig))
+
+	// Health checks for orchestrators and load balancers
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+
+	// Expose Prometheus metrics
+	if cfg.Metrics.Enabled {
+		router.GET(cfg.Metrics.Path, gin.WrapH(metrics.Handler()))
+	}
+
+	// User authentication routes (requires authentication middleware)
+	userAuth := router.Group("/api/v1/user")
+	userAuth.Use(middleware.RateLimit(rateLimiter, authLimit), middleware.AuthMiddleware(authService))
+	{
+		userAuth.POST("/login", userHandler.Login)
+		userAuth.POST("/login-or-register", userHandler.LoginWithAutoRegister)
+		userAuth.POST("/register", userHandler.RegisterUser)
+	}
+
+	// Public routes
+	public := router.Group("/api/v1")
+	public.Use(middleware.OptionalAuth(authService), middleware.RateLimitByUser(rateLimiter, anonymousLimit, authenticatedLimit))
+	{
+		public.GET("/blogs", middleware.LoadRoles(userService), blogHandler.ListBlogs)
+		public.GET("/blogs/popular", blogHandler.ListPopularBlogs)
+		public.GET("/blogs/by-slug/:slug", middleware.LoadRoles(userService), blogHandler.GetBlogBySlug)
+		public.GET("/blogs/:id", middleware.LoadRoles(userService), blogHandler.GetBlog)
+
+		public.GET("/blogs/:id/comments", middleware.LoadRoles(userService), commentHandler.ListComments)
+	}
+
+	// Protected routes (require authentication). Requests are limited by
+	// client IP before their token is verified and by user afterwards.
+	protected := router.Group("/api/v1")
+	protected.Use(middleware.RateLimit(rateLimiter, preAuthLimit), middleware.AuthMiddleware(authService), middleware.RateLimit(rateLimiter, authenticatedLimit), middleware.LoadRoles(userService))
+	{
+		protected.POST("/blogs", middleware.RequirePermission(userService, model.PermissionCreateBlog), blogHandler.CreateBlog)
+		protected.PUT("/blogs/:id", blogHandler.UpdateBlog)
+		protected.DELETE("/blogs/:id", blogHandler.DeleteBlog)
+		protected.GET("/blogs/:id/revisions", blogHandler.ListRevisions)
+		protected.GET("/blogs/:id/revisions/diff", blogHandler.DiffRevisions)
+		protected.POST("/blogs/:id/revisions/:version/restore", blogHandler.RestoreRevision)
+
+		protected.POST("/blogs/:id/like", middleware.RequirePermission(userService, model.PermissionReact), userHandler.ToggleLike)
+		protected.POST("/blogs/:id/bookmark", middleware.RequirePermission(userService, model.PermissionReact), userHandler.ToggleBookmark)
+		protected.POST("/comments", middleware.RequirePermission(userService, model.PermissionCreateComment), commentHandler.CreateComment)
+
+		protected.GET("/user/bookmarks", func(c *gin.Context) {
+			uid, _ := c.Get("uid")
+
+			// Get user with bookmarks
+			userData, err := userService.GetUserByID(c.Request.Context(), uid.(string))
+			if err != nil {
+				apperror.Respond(c, err)
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"user":      userData.Email,
+				"bookmarks": userData.Bookmarks,
+			})
+		})
+
+		protected.GET("/user/profile", func(c *gin.Context) {
+			uid, _ := c.Get("uid")
+
+			userData, err := userService.GetUserByID(c.Request.Context(), uid.(string))
+			if err != nil {
+				apperror.Respond(c, err)
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"id":              userData.ID,
+				"name":            userData.Name,
+				"email":           userData.Email,
+				"photo_url":       userData.PhotoURL,
+				"created_at":      userData.CreatedAt,
+				"bookmarks_count": len(userData.Bookmarks),
+				"likes_count":     len(userData.Likes),
+				"roles":           userData.EffectiveRoles(),
+			})
+		})
+
+		protected.PUT("/user/profile", func(c *gin.Context) {
+			uid, _ := c.Get("uid")
+
+			var input struct {
+				Name string `json:"name"`
+			}
+
+			if err := c.ShouldBindJSON(&input); err != nil {
+				apperror.Respond(c, apperror.InvalidRequest(err))
+				return
+			}
+
+			userData, err := userService.UpdateUserProfile(c.Request.Context(), uid.(string), input.Name)
+			if err != nil {
+				apperror.Respond(c, err)
+				return
+			}
+
+			c.JSON(http.StatusOK, gin.H{
+				"id":         userData.ID,
+				"name":       userData.Name,
+				"email":      userData.Email,
+				"photo_url":  userData.PhotoURL,
+				"updated_at": userData.UpdatedAt,
+			})
+		})
+	}
+
+	// Admin routes
+	admin := router.Group("/api/v1/admin")
+	admin.Use(middleware.RateLimit(rateLimiter, preAuthLimit), middleware.AuthMiddleware(authService), middleware.RateLimit(rateLimiter, adminLimit), middleware.RequirePermission(userService, model.PermissionManageUsers))
+	{
+		admin.GET("/users", userHandler.ListUsers)
+		admin.GET("/users/:id/activity", userHandler.GetUserActivity)
+		admin.PUT("/users/:id/roles", userHandler.SetRoles)
+		admin.POST("/users/:id/roles/:role", userHandler.GrantRole)
+		admin.DELETE("/users/:id/roles/:role", userHandler.RevokeRole)
+		admin.POST("/users/:id/disable", userHandler.DisableUser)
+		admin.POST("/users/:id/enable", userHandler.EnableUser)
+		admin.POST("/users/:id/revoke-tokens", userHandler.RevokeTokens)
+	}
+
+	return router, nil
+}
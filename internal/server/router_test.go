@@ -0,0 +1,80 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/dksensei/letsnormalizeit/internal/server/servertest"
+)
+
+func TestHealth(t *testing.T) {
+	h := servertest.New(t)
+
+	h.Request(http.MethodGet, "/healthz").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("status", "ok")
+	h.Request(http.MethodGet, "/readyz").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("status", "ok")
+
+	h.HealthChecks.Drain()
+	h.Request(http.MethodGet, "/readyz").Do().
+		AssertStatus(http.StatusServiceUnavailable).
+		AssertJSON("status", "draining")
+}
+
+func TestRequestID(t *testing.T) {
+	h := servertest.New(t)
+
+	resp := h.Request(http.MethodGet, "/healthz").Header(middleware.RequestIDHeader, "req-123").Do()
+	if got := resp.Recorder.Header().Get(middleware.RequestIDHeader); got != "req-123" {
+		t.Errorf("%s = %q, want req-123", middleware.RequestIDHeader, got)
+	}
+
+	resp = h.Request(http.MethodGet, "/healthz").Do()
+	if resp.Recorder.Header().Get(middleware.RequestIDHeader) == "" {
+		t.Errorf("%s not assigned", middleware.RequestIDHeader)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	h := servertest.New(t, func(cfg *config.Config) {
+		cfg.RateLimit.Auth.Requests = 2
+	})
+
+	for range 2 {
+		h.Request(http.MethodPost, "/api/v1/user/login").As("alice").Do().AssertStatus(http.StatusOK)
+	}
+	h.Request(http.MethodPost, "/api/v1/user/login").As("alice").Do().
+		AssertProblem(http.StatusTooManyRequests, "rate_limited")
+
+	// Other route groups have their own quota
+	h.Request(http.MethodGet, "/api/v1/user/profile").As("alice").Do().AssertStatus(http.StatusOK)
+}
+
+func TestRateLimitBeforeAuth(t *testing.T) {
+	h := servertest.New(t, func(cfg *config.Config) {
+		cfg.RateLimit.PreAuth.Requests = 2
+	})
+
+	// Invalid tokens are counted by client IP before they are rejected
+	h.Request(http.MethodGet, "/api/v1/user/profile").Token("forged").Do().
+		AssertProblem(http.StatusUnauthorized, "invalid_token")
+	h.Request(http.MethodGet, "/api/v1/admin/users").Token("forged").Do().
+		AssertProblem(http.StatusUnauthorized, "invalid_token")
+	h.Request(http.MethodGet, "/api/v1/user/profile").Token("forged").Do().
+		AssertProblem(http.StatusTooManyRequests, "rate_limited")
+	h.Request(http.MethodGet, "/api/v1/user/profile").As("alice").Do().
+		AssertProblem(http.StatusTooManyRequests, "rate_limited")
+
+	// Public routes are not affected
+	h.Request(http.MethodGet, "/api/v1/blogs").Do().AssertStatus(http.StatusOK)
+}
+
+func TestUnknownRoute(t *testing.T) {
+	h := servertest.New(t)
+
+	h.Request(http.MethodGet, "/api/v1/nope").Do().AssertStatus(http.StatusNotFound)
+}
@@ -0,0 +1,192 @@
+package servertest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	firebaseauth "firebase.google.com/go/v4/auth"
+	"github.com/dksensei/letsnormalizeit/internal/auth"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+)
+
+// AuthService is a fake model.AuthService. It accepts the tokens handed out by
+// Token and keeps accounts, custom claims, disabled flags and revocations in
+// memory. Verified tokens carry the account's current custom claims, as if
+// the client refreshed its ID token before every request.
+type AuthService struct {
+	mu     sync.Mutex
+	users  map[string]*firebaseauth.UserRecord
+	tokens map[string]*issuedToken
+	issued int
+}
+
+// issuedToken is a token handed out by Token
+type issuedToken struct {
+	uid     string
+	revoked bool
+}
+
+// Ensure AuthService implements model.AuthService
+var _ model.AuthService = (*AuthService)(nil)
+
+// NewAuthService creates a fake auth service without any accounts
+func NewAuthService() *AuthService {
+	return &AuthService{
+		users:  make(map[string]*firebaseauth.UserRecord),
+		tokens: make(map[string]*issuedToken),
+	}
+}
+
+// Token issues a new ID token for uid, creating the account with a name and
+// email derived from uid if it does not exist yet
+func (s *AuthService) Token(uid string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[uid]; !ok {
+		s.users[uid] = &firebaseauth.UserRecord{
+			UserInfo: &firebaseauth.UserInfo{
+				UID:         uid,
+				DisplayName: uid,
+				Email:       uid + "@example.com",
+			},
+			UserMetadata: &firebaseauth.UserMetadata{CreationTimestamp: time.Now().UnixMilli()},
+		}
+	}
+
+	s.issued++
+	token := fmt.Sprintf("test-token-%d-%s", s.issued, uid)
+	s.tokens[token] = &issuedToken{uid: uid}
+	return token
+}
+
+// VerifyToken verifies a token issued by Token
+func (s *AuthService) VerifyToken(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issued, ok := s.tokens[idToken]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown test token", auth.ErrInvalidToken)
+	}
+	uid := issued.uid
+	user := s.users[uid]
+	if user.Disabled {
+		return nil, auth.ErrUserDisabled
+	}
+	if issued.revoked {
+		return nil, auth.ErrTokenRevoked
+	}
+
+	claims, err := roundTrip(user.CustomClaims)
+	if err != nil {
+		return nil, err
+	}
+	claims["email"] = user.Email
+
+	now := time.Now()
+	return &firebaseauth.Token{
+		Subject:  uid,
+		UID:      uid,
+		IssuedAt: now.Unix(),
+		AuthTime: now.Unix(),
+		Expires:  now.Add(time.Hour).Unix(),
+		Claims:   claims,
+	}, nil
+}
+
+// VerifyTokenAndCheckRevoked verifies a token issued by Token; VerifyToken
+// already rejects revoked tokens and disabled accounts
+func (s *AuthService) VerifyTokenAndCheckRevoked(ctx context.Context, idToken string) (*firebaseauth.Token, error) {
+	return s.VerifyToken(ctx, idToken)
+}
+
+// GetUser gets an account by UID
+func (s *AuthService) GetUser(ctx context.Context, uid string) (*firebaseauth.UserRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[uid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", auth.ErrUserNotFound, uid)
+	}
+	clone := *user
+	info := *user.UserInfo
+	clone.UserInfo = &info
+	return &clone, nil
+}
+
+// SetCustomUserClaims replaces the custom claims of an account
+func (s *AuthService) SetCustomUserClaims(ctx context.Context, uid string, claims map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[uid]
+	if !ok {
+		return fmt.Errorf("%w: %s", auth.ErrUserNotFound, uid)
+	}
+	user.CustomClaims = claims
+	return nil
+}
+
+// SetUserDisabled disables or enables an account; tokens of disabled accounts are rejected
+func (s *AuthService) SetUserDisabled(ctx context.Context, uid string, disabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[uid]
+	if !ok {
+		return fmt.Errorf("%w: %s", auth.ErrUserNotFound, uid)
+	}
+	user.Disabled = disabled
+	return nil
+}
+
+// RevokeRefreshTokens invalidates every token issued to an account so far
+func (s *AuthService) RevokeRefreshTokens(ctx context.Context, uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[uid]; !ok {
+		return fmt.Errorf("%w: %s", auth.ErrUserNotFound, uid)
+	}
+	for _, issued := range s.tokens {
+		if issued.uid == uid {
+			issued.revoked = true
+		}
+	}
+	return nil
+}
+
+// Claims returns a copy of an account's custom claims
+func (s *AuthService) Claims(uid string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[uid]
+	if !ok {
+		return nil
+	}
+	claims, _ := roundTrip(user.CustomClaims)
+	return claims
+}
+
+// roundTrip copies claims through JSON, the way they reach a real ID token,
+// so e.g. a []string of roles comes back as []interface{}
+func roundTrip(claims map[string]interface{}) (map[string]interface{}, error) {
+	decoded := make(map[string]interface{})
+	if len(claims) == 0 {
+		return decoded, nil
+	}
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
@@ -0,0 +1,384 @@
+// Package servertest boots the full API router for end-to-end tests, without
+// MongoDB, Redis or an auth provider. Repositories, the cache and the rate
+// limiter are in memory and tokens come from a fake auth service:
+//
+//	h := servertest.New(t)
+//	h.Request(http.MethodPost, "/api/v1/user/login-or-register").
+//		As("alice").
+//		JSON(map[string]string{"name": "Alice", "email": "alice@example.com"}).
+//		Do().
+//		AssertStatus(http.StatusOK).
+//		AssertJSON("id", "alice")
+package servertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/blog"
+	"github.com/dksensei/letsnormalizeit/internal/cache"
+	"github.com/dksensei/letsnormalizeit/internal/comment"
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/health"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/ratelimit"
+	"github.com/dksensei/letsnormalizeit/internal/server"
+	"github.com/dksensei/letsnormalizeit/internal/user"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// initOnce quiets the global logger and gin for every harness
+var initOnce sync.Once
+
+// Harness is the API router wired to in-memory dependencies. The fields
+// expose those dependencies so tests can seed and inspect state directly.
+type Harness struct {
+	t *testing.T
+
+	Router *gin.Engine
+	Config *config.Config
+	Auth   *AuthService
+
+	Users     *user.MemoryRepository
+	Blogs     *blog.MemoryRepository
+	Revisions *blog.MemoryRevisionRepository
+	Slugs     *blog.MemorySlugRepository
+	Comments  *comment.MemoryRepository
+
+	UserService    *user.Service
+	BlogService    *blog.Service
+	CommentService *comment.Service
+	HealthChecks   *health.Registry
+}
+
+// Config returns the configuration harnesses start from: close to the
+// defaults of config.Load, but with Redis disabled and in-memory rate limits
+// generous enough for any test
+func Config() *config.Config {
+	unlimited := config.RateLimitPolicyConfig{Requests: 1000000, Window: time.Minute, Identity: "uid,ip"}
+	return &config.Config{
+		Server: config.ServerConfig{
+			AllowOrigins:       "*",
+			HealthCheckTimeout: time.Second,
+		},
+		Redis: config.RedisConfig{
+			BlogTTL:    15 * time.Minute,
+			ListTTL:    2 * time.Minute,
+			PopularTTL: 5 * time.Minute,
+			CommentTTL: 5 * time.Minute,
+			RenderTTL:  24 * time.Hour,
+		},
+		Comments: config.CommentConfig{MaxDepth: 5, RepliesPerLevel: 3},
+		RateLimit: config.RateLimitConfig{
+			Backend:       config.RateLimitBackendMemory,
+			Anonymous:     unlimited,
+			Authenticated: unlimited,
+			Auth:          unlimited,
+			Admin:         unlimited,
+			PreAuth:       unlimited,
+		},
+		Metrics: config.MetricsConfig{Enabled: true, Path: "/metrics"},
+		Logger:  config.LoggerConfig{AccessSampleRate: 0},
+	}
+}
+
+// New builds a harness from Config, after letting configure adjust it
+func New(t *testing.T, configure ...func(cfg *config.Config)) *Harness {
+	t.Helper()
+
+	initOnce.Do(func() {
+		gin.SetMode(gin.TestMode)
+		utils.InitWithConfig(map[string]interface{}{
+			"level":              "fatal",
+			"encoding":           "console",
+			"output_paths":       []string{"stderr"},
+			"error_output_paths": []string{"stderr"},
+		})
+	})
+
+	cfg := Config()
+	for _, apply := range configure {
+		apply(cfg)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	h := &Harness{
+		t:         t,
+		Config:    cfg,
+		Auth:      NewAuthService(),
+		Users:     user.NewMemoryRepository(),
+		Blogs:     blog.NewMemoryRepository(),
+		Revisions: blog.NewMemoryRevisionRepository(),
+		Slugs:     blog.NewMemorySlugRepository(),
+		Comments:  comment.NewMemoryRepository(),
+	}
+
+	store := cache.NewMemoryStore()
+	store.Cleanup(ctx, time.Minute)
+	appCache := cache.New(store, &cfg.Redis)
+
+	limiter := ratelimit.NewMemoryLimiter()
+	limiter.Cleanup(ctx, time.Minute)
+
+	h.BlogService = blog.NewService(h.Blogs, h.Revisions, h.Slugs, appCache)
+	h.CommentService = comment.NewService(h.Comments, h.BlogService, appCache, &cfg.Comments)
+	h.UserService = user.NewService(h.Users, h.Auth, h.BlogService, h.CommentService)
+	h.BlogService.OnDelete(h.CommentService.DeleteBlogComments)
+	h.BlogService.OnDelete(h.UserService.RemoveBlogReactions)
+	h.HealthChecks = health.NewRegistry(cfg.Server.HealthCheckTimeout)
+
+	router, err := server.BuildRouter(cfg, &server.Dependencies{
+		AuthService:    h.Auth,
+		UserService:    h.UserService,
+		BlogService:    h.BlogService,
+		CommentService: h.CommentService,
+		RateLimiter:    limiter,
+		HealthChecks:   h.HealthChecks,
+	})
+	if err != nil {
+		t.Fatalf("build router: %v", err)
+	}
+	h.Router = router
+	return h
+}
+
+// CreateUser registers a user with the fake auth service and stores it,
+// with the given roles or the default ones
+func (h *Harness) CreateUser(uid string, roles ...model.Role) *model.User {
+	h.t.Helper()
+
+	h.Auth.Token(uid)
+	u := model.NewUser(uid, uid, uid+"@example.com", "")
+	if len(roles) > 0 {
+		u.Roles = roles
+		u.IsAdmin = model.HasRole(roles, model.RoleAdmin)
+	}
+	if err := h.Users.Create(context.Background(), u); err != nil {
+		h.t.Fatalf("create user %s: %v", uid, err)
+	}
+	return u
+}
+
+// CreateBlog publishes a blog by authorID through the blog service
+func (h *Harness) CreateBlog(authorID, title string) *model.Blog {
+	h.t.Helper()
+
+	b, err := h.BlogService.CreateBlog(context.Background(), authorID, &model.BlogInput{
+		Title:   title,
+		Content: "Content of " + title,
+		Tags:    []string{"test"},
+	})
+	if err != nil {
+		h.t.Fatalf("create blog %q: %v", title, err)
+	}
+	return b
+}
+
+// Request is an API request being built
+type Request struct {
+	h      *Harness
+	method string
+	path   string
+	header http.Header
+	body   io.Reader
+}
+
+// Request starts building a request to path
+func (h *Harness) Request(method, path string) *Request {
+	return &Request{h: h, method: method, path: path, header: make(http.Header)}
+}
+
+// As authenticates the request with a fresh token for uid
+func (r *Request) As(uid string) *Request {
+	return r.Token(r.h.Auth.Token(uid))
+}
+
+// Token authenticates the request with a bearer token
+func (r *Request) Token(token string) *Request {
+	r.header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+// Header sets a request header
+func (r *Request) Header(name, value string) *Request {
+	r.header.Set(name, value)
+	return r
+}
+
+// JSON sends body encoded as JSON
+func (r *Request) JSON(body interface{}) *Request {
+	r.h.t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		r.h.t.Fatalf("encode request body: %v", err)
+	}
+	r.body = bytes.NewReader(data)
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Do serves the request and records the response
+func (r *Request) Do() *Response {
+	r.h.t.Helper()
+
+	req := httptest.NewRequest(r.method, r.path, r.body)
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+
+	recorder := httptest.NewRecorder()
+	r.h.Router.ServeHTTP(recorder, req)
+	return &Response{t: r.h.t, request: r.method + " " + r.path, Recorder: recorder}
+}
+
+// Response is a recorded API response with assertion helpers. Assertions
+// fail the test immediately, showing the request and the response body.
+type Response struct {
+	t       *testing.T
+	request string
+	decoded interface{}
+
+	Recorder *httptest.ResponseRecorder
+}
+
+// Status returns the response status code
+func (r *Response) Status() int {
+	return r.Recorder.Code
+}
+
+// AssertStatus checks the status code
+func (r *Response) AssertStatus(want int) *Response {
+	r.t.Helper()
+
+	if r.Recorder.Code != want {
+		r.t.Fatalf("%s: status = %d, want %d; body: %s", r.request, r.Recorder.Code, want, r.Recorder.Body)
+	}
+	return r
+}
+
+// AssertProblem checks that the response is problem details with the given
+// status and error code
+func (r *Response) AssertProblem(status int, code string) *Response {
+	r.t.Helper()
+
+	r.AssertStatus(status)
+	if contentType := r.Recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, apperror.ProblemContentType) {
+		r.t.Fatalf("%s: Content-Type = %q, want %s", r.request, contentType, apperror.ProblemContentType)
+	}
+	return r.AssertJSON("code", code)
+}
+
+// Decode decodes the JSON body into v
+func (r *Response) Decode(v interface{}) *Response {
+	r.t.Helper()
+
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), v); err != nil {
+		r.t.Fatalf("%s: decode body: %v; body: %s", r.request, err, r.Recorder.Body)
+	}
+	return r
+}
+
+// JSON returns the value at a dot separated path of the JSON body, e.g.
+// "users.0.id"; an empty path is the whole body. Numbers are float64, as
+// decoded by encoding/json.
+func (r *Response) JSON(path string) interface{} {
+	r.t.Helper()
+
+	if r.decoded == nil {
+		r.Decode(&r.decoded)
+	}
+	value, ok := lookup(r.decoded, path)
+	if !ok {
+		r.t.Fatalf("%s: body has no %q; body: %s", r.request, path, r.Recorder.Body)
+	}
+	return value
+}
+
+// AssertJSON checks the value at a path of the JSON body. want is compared
+// after a JSON round trip, so e.g. an int matches the decoded float64.
+func (r *Response) AssertJSON(path string, want interface{}) *Response {
+	r.t.Helper()
+
+	got := r.JSON(path)
+	normalized, err := normalize(want)
+	if err != nil {
+		r.t.Fatalf("encode expected value: %v", err)
+	}
+	if !reflect.DeepEqual(got, normalized) {
+		r.t.Fatalf("%s: %s = %#v, want %#v; body: %s", r.request, path, got, normalized, r.Recorder.Body)
+	}
+	return r
+}
+
+// AssertJSONLen checks the length of the array or object at a path of the JSON body
+func (r *Response) AssertJSONLen(path string, want int) *Response {
+	r.t.Helper()
+
+	var got int
+	switch value := r.JSON(path).(type) {
+	case []interface{}:
+		got = len(value)
+	case map[string]interface{}:
+		got = len(value)
+	case nil:
+		got = 0
+	default:
+		r.t.Fatalf("%s: %s is %T, not an array or object", r.request, path, value)
+	}
+	if got != want {
+		r.t.Fatalf("%s: len(%s) = %d, want %d; body: %s", r.request, path, got, want, r.Recorder.Body)
+	}
+	return r
+}
+
+// lookup walks a dot separated path through decoded JSON
+func lookup(value interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return value, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			child, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			value = child
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			value = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// normalize converts v to the types encoding/json decodes into
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
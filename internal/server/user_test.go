@@ -0,0 +1,103 @@
+package server_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/server/servertest"
+	"github.com/dksensei/letsnormalizeit/internal/user"
+)
+
+func TestLoginOrRegister(t *testing.T) {
+	h := servertest.New(t)
+	input := map[string]string{"name": "Alice", "email": "alice@example.org"}
+
+	// The first sign in registers the user with the profile held by the
+	// auth provider
+	h.Request(http.MethodPost, "/api/v1/user/login-or-register").As("alice").JSON(input).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("id", "alice").
+		AssertJSON("name", "alice").
+		AssertJSON("email", "alice@example.com")
+	if _, err := h.Users.FindByID(t.Context(), "alice"); err != nil {
+		t.Fatalf("user not stored: %v", err)
+	}
+
+	// Later sign ins return the stored user unchanged
+	h.Request(http.MethodPut, "/api/v1/user/profile").As("alice").JSON(map[string]string{"name": "Alice"}).Do().
+		AssertStatus(http.StatusOK)
+	h.Request(http.MethodPost, "/api/v1/user/login-or-register").As("alice").JSON(input).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("name", "Alice")
+
+	h.Request(http.MethodPost, "/api/v1/user/login").As("alice").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("email", "alice@example.com")
+	h.Request(http.MethodGet, "/api/v1/user/profile").As("alice").Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("name", "Alice").
+		AssertJSON("roles", []string{"author"}).
+		AssertJSON("likes_count", 0)
+}
+
+func TestLoginOrRegisterRejects(t *testing.T) {
+	h := servertest.New(t)
+	input := map[string]string{"name": "Alice", "email": "alice@example.org"}
+
+	h.Request(http.MethodPost, "/api/v1/user/login-or-register").JSON(input).Do().
+		AssertProblem(http.StatusUnauthorized, "missing_authorization")
+	h.Request(http.MethodPost, "/api/v1/user/login-or-register").Header("Authorization", "Token abc").JSON(input).Do().
+		AssertProblem(http.StatusUnauthorized, "invalid_authorization")
+	h.Request(http.MethodPost, "/api/v1/user/login-or-register").Token("forged").JSON(input).Do().
+		AssertProblem(http.StatusUnauthorized, "invalid_token")
+	h.Request(http.MethodPost, "/api/v1/user/login-or-register").As("alice").JSON(map[string]string{"name": "Alice", "email": "not-an-email"}).Do().
+		AssertProblem(http.StatusBadRequest, "invalid_request")
+
+	// Nothing was registered by the failed attempts
+	if _, err := h.Users.FindByID(t.Context(), "alice"); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("FindByID() error = %v, want %v", err, user.ErrUserNotFound)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("alice")
+
+	h.Request(http.MethodPut, "/api/v1/user/profile").As("alice").JSON(map[string]string{"name": "Alice L."}).Do().
+		AssertStatus(http.StatusOK).
+		AssertJSON("name", "Alice L.")
+	h.Request(http.MethodGet, "/api/v1/user/profile").As("alice").Do().
+		AssertJSON("name", "Alice L.")
+}
+
+func TestRegisterDuplicateEmail(t *testing.T) {
+	h := servertest.New(t)
+	h.CreateUser("alice")
+
+	h.Request(http.MethodPost, "/api/v1/user/register").As("bob").
+		JSON(map[string]string{"name": "Bob", "email": "alice@example.com"}).Do().
+		AssertProblem(http.StatusConflict, "email_taken")
+}
+
+func TestSharedEmailAccount(t *testing.T) {
+	h := servertest.New(t)
+
+	// Another account registered first with the email the auth provider
+	// holds for alice
+	existing := model.NewUser("alice-old", "Alice", "alice@example.com", "")
+	if err := h.Users.Create(t.Context(), existing); err != nil {
+		t.Fatal(err)
+	}
+
+	// alice's record cannot be created from the auth provider's data
+	h.Request(http.MethodGet, "/api/v1/user/profile").As("alice").Do().
+		AssertProblem(http.StatusConflict, "email_taken")
+	h.Request(http.MethodPost, "/api/v1/user/login").As("alice").Do().
+		AssertProblem(http.StatusConflict, "email_taken")
+
+	if _, err := h.Users.FindByID(t.Context(), "alice"); !errors.Is(err, user.ErrUserNotFound) {
+		t.Errorf("FindByID error = %v, want %v", err, user.ErrUserNotFound)
+	}
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisHook is a go-redis hook recording a span for every command. Command
+// arguments are left out since they may hold cache contents. Missing keys
+// (redis.Nil) are not recorded as errors.
+type RedisHook struct{}
+
+// Ensure RedisHook implements redis.Hook
+var _ redis.Hook = RedisHook{}
+
+// BeforeProcess starts a command's span
+func (RedisHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	ctx, _ = startRedisSpan(ctx, cmd.Name())
+	return ctx, nil
+}
+
+// AfterProcess ends a command's span
+func (RedisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endRedisSpan(ctx, cmd.Err())
+	return nil
+}
+
+// BeforeProcessPipeline starts a pipeline's span
+func (RedisHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	ctx, span := startRedisSpan(ctx, "pipeline")
+	span.SetAttributes(attribute.Int("db.redis.num_cmd", len(cmds)))
+	return ctx, nil
+}
+
+// AfterProcessPipeline ends a pipeline's span, recording the first error of
+// its commands
+func (RedisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmdErr := cmd.Err(); cmdErr != nil && !errors.Is(cmdErr, redis.Nil) {
+			err = cmdErr
+			break
+		}
+	}
+	endRedisSpan(ctx, err)
+	return nil
+}
+
+// startRedisSpan starts the span of a command
+func startRedisSpan(ctx context.Context, command string) (context.Context, trace.Span) {
+	return Start(ctx, "redis."+command,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation", command),
+		),
+	)
+}
+
+// endRedisSpan ends the span started by BeforeProcess
+func endRedisSpan(ctx context.Context, err error) {
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	End(trace.SpanFromContext(ctx), err)
+}
@@ -0,0 +1,264 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/tracing"
+)
+
+// record installs a global tracer provider recording every span for the
+// length of the test
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// attributes returns a span's attributes by key
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestStartEnd(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+		wantEvents int
+	}{
+		{name: "Success", err: nil, wantStatus: codes.Unset, wantEvents: 0},
+		{name: "NotFound", err: apperror.NotFound("blog_not_found", "Blog not found"), wantStatus: codes.Unset, wantEvents: 1},
+		{name: "Unavailable", err: apperror.Unavailable("redis_down", "Redis is down"), wantStatus: codes.Error, wantEvents: 1},
+		{name: "Untyped", err: errors.New("boom"), wantStatus: codes.Error, wantEvents: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record(t)
+
+			ctx, parent := tracing.Start(context.Background(), "parent")
+			_, span := tracing.Start(ctx, "child")
+			tracing.End(span, tc.err)
+			parent.End()
+
+			ended := recorder.Ended()
+			if len(ended) != 2 {
+				t.Fatalf("recorded %d spans, want 2", len(ended))
+			}
+			child := ended[0]
+			if child.Name() != "child" {
+				t.Errorf("span name = %q, want %q", child.Name(), "child")
+			}
+			if child.Parent().SpanID() != parent.SpanContext().SpanID() {
+				t.Errorf("span parent = %s, want %s", child.Parent().SpanID(), parent.SpanContext().SpanID())
+			}
+			if got := child.Status().Code; got != tc.wantStatus {
+				t.Errorf("span status = %s, want %s", got, tc.wantStatus)
+			}
+			if got := len(child.Events()); got != tc.wantEvents {
+				t.Errorf("span has %d events, want %d", got, tc.wantEvents)
+			}
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		previous := otel.GetTracerProvider()
+		shutdown, err := tracing.Setup(context.Background(), &config.TracingConfig{Enabled: false})
+		if err != nil {
+			t.Fatalf("Setup() error = %v", err)
+		}
+		if err := shutdown(context.Background()); err != nil {
+			t.Errorf("shutdown() error = %v", err)
+		}
+		if otel.GetTracerProvider() != previous {
+			t.Error("Setup installed a tracer provider while tracing is disabled")
+		}
+	})
+
+	t.Run("UnknownExporter", func(t *testing.T) {
+		_, err := tracing.Setup(context.Background(), &config.TracingConfig{Enabled: true, Exporter: "jaeger"})
+		if err == nil {
+			t.Error("Setup() error = nil, want an unknown exporter error")
+		}
+	})
+
+	t.Run("File", func(t *testing.T) {
+		previousProvider := otel.GetTracerProvider()
+		previousPropagator := otel.GetTextMapPropagator()
+		t.Cleanup(func() {
+			otel.SetTracerProvider(previousProvider)
+			otel.SetTextMapPropagator(previousPropagator)
+		})
+
+		file := filepath.Join(t.TempDir(), "traces.json")
+		shutdown, err := tracing.Setup(context.Background(), &config.TracingConfig{
+			Enabled:     true,
+			Exporter:    config.TracingExporterFile,
+			ServiceName: "letsnormalizeit-test",
+			SampleRatio: 1,
+			File:        file,
+		})
+		if err != nil {
+			t.Fatalf("Setup() error = %v", err)
+		}
+
+		_, span := tracing.Start(context.Background(), "exported")
+		tracing.End(span, nil)
+		if err := shutdown(context.Background()); err != nil {
+			t.Fatalf("shutdown() error = %v", err)
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read trace file: %v", err)
+		}
+		if !strings.Contains(string(data), `"exported"`) {
+			t.Errorf("trace file does not hold the span: %s", data)
+		}
+		if fields := otel.GetTextMapPropagator().Fields(); !contains(fields, "traceparent") {
+			t.Errorf("propagator fields = %v, want traceparent", fields)
+		}
+	})
+}
+
+func contains(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRedisHook(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{name: "Success", err: nil, wantStatus: codes.Unset},
+		{name: "Missing", err: redis.Nil, wantStatus: codes.Unset},
+		{name: "Failure", err: errors.New("connection reset"), wantStatus: codes.Error},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record(t)
+			hook := tracing.RedisHook{}
+
+			parent, request := tracing.Start(context.Background(), "request")
+			cmd := redis.NewStringCmd(parent, "get", "blog:1")
+			ctx, err := hook.BeforeProcess(parent, cmd)
+			if err != nil {
+				t.Fatalf("BeforeProcess() error = %v", err)
+			}
+			cmd.SetErr(tc.err)
+			if err := hook.AfterProcess(ctx, cmd); err != nil {
+				t.Fatalf("AfterProcess() error = %v", err)
+			}
+			request.End()
+
+			ended := recorder.Ended()
+			if len(ended) != 2 {
+				t.Fatalf("recorded %d spans, want 2", len(ended))
+			}
+			span := ended[0]
+			if span.Name() != "redis.get" {
+				t.Errorf("span name = %q, want %q", span.Name(), "redis.get")
+			}
+			if span.SpanKind() != trace.SpanKindClient {
+				t.Errorf("span kind = %s, want %s", span.SpanKind(), trace.SpanKindClient)
+			}
+			if span.Parent().SpanID() != request.SpanContext().SpanID() {
+				t.Error("Redis span is not a child of the request span")
+			}
+			attrs := attributes(span)
+			if got := attrs["db.system"].AsString(); got != "redis" {
+				t.Errorf("db.system = %q, want %q", got, "redis")
+			}
+			if got := attrs["db.operation"].AsString(); got != "get" {
+				t.Errorf("db.operation = %q, want %q", got, "get")
+			}
+			for key := range attrs {
+				if strings.Contains(string(key), "statement") || strings.Contains(string(key), "args") {
+					t.Errorf("span records command arguments in %s", key)
+				}
+			}
+			if got := span.Status().Code; got != tc.wantStatus {
+				t.Errorf("span status = %s, want %s", got, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRedisHookPipeline(t *testing.T) {
+	cases := []struct {
+		name       string
+		errs       []error
+		wantStatus codes.Code
+	}{
+		{name: "Success", errs: []error{nil, nil}, wantStatus: codes.Unset},
+		{name: "Missing", errs: []error{redis.Nil, nil}, wantStatus: codes.Unset},
+		{name: "Failure", errs: []error{redis.Nil, errors.New("connection reset")}, wantStatus: codes.Error},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record(t)
+			hook := tracing.RedisHook{}
+
+			cmds := make([]redis.Cmder, len(tc.errs))
+			for i, err := range tc.errs {
+				cmd := redis.NewIntCmd(context.Background(), "incr", "views")
+				cmd.SetErr(err)
+				cmds[i] = cmd
+			}
+			ctx, err := hook.BeforeProcessPipeline(context.Background(), cmds)
+			if err != nil {
+				t.Fatalf("BeforeProcessPipeline() error = %v", err)
+			}
+			if err := hook.AfterProcessPipeline(ctx, cmds); err != nil {
+				t.Fatalf("AfterProcessPipeline() error = %v", err)
+			}
+
+			ended := recorder.Ended()
+			if len(ended) != 1 {
+				t.Fatalf("recorded %d spans, want 1", len(ended))
+			}
+			span := ended[0]
+			if span.Name() != "redis.pipeline" {
+				t.Errorf("span name = %q, want %q", span.Name(), "redis.pipeline")
+			}
+			if got := attributes(span)["db.redis.num_cmd"].AsInt64(); got != int64(len(cmds)) {
+				t.Errorf("db.redis.num_cmd = %d, want %d", got, len(cmds))
+			}
+			if got := span.Status().Code; got != tc.wantStatus {
+				t.Errorf("span status = %s, want %s", got, tc.wantStatus)
+			}
+		})
+	}
+}
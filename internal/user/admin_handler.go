@@ -0,0 +1,257 @@
+package user
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminUserResponse represents a user in the admin APIs
+type AdminUserResponse struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Email     string       `json:"email"`
+	PhotoURL  string       `json:"photo_url,omitempty"`
+	Roles     []model.Role `json:"roles"`
+	Disabled  bool         `json:"disabled"`
+	CreatedAt string       `json:"created_at"`
+	UpdatedAt string       `json:"updated_at"`
+}
+
+// ActivityBlogResponse summarizes a blog in a user's activity
+type ActivityBlogResponse struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ActivityCommentResponse summarizes a comment in a user's activity
+type ActivityCommentResponse struct {
+	ID        string `json:"id"`
+	BlogID    string `json:"blog_id"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ActivityResponse represents a user's activity
+type ActivityResponse struct {
+	User           AdminUserResponse         `json:"user"`
+	BlogCount      int64                     `json:"blog_count"`
+	CommentCount   int64                     `json:"comment_count"`
+	LikeCount      int                       `json:"like_count"`
+	BookmarkCount  int                       `json:"bookmark_count"`
+	RecentBlogs    []ActivityBlogResponse    `json:"recent_blogs"`
+	RecentComments []ActivityCommentResponse `json:"recent_comments"`
+	LastSignInAt   string                    `json:"last_sign_in_at,omitempty"`
+}
+
+// SetRolesInput represents the input for replacing a user's roles
+type SetRolesInput struct {
+	Roles []model.Role `json:"roles" binding:"required"`
+}
+
+// newAdminUserResponse converts a user model into its admin API representation
+func newAdminUserResponse(user *model.User) AdminUserResponse {
+	return AdminUserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		PhotoURL:  user.PhotoURL,
+		Roles:     user.EffectiveRoles(),
+		Disabled:  user.Disabled,
+		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// ListUsers handles listing and searching users (admin only).
+// Query parameters: q (name or email substring), role, disabled (true or
+// false), limit and offset.
+func (h *Handler) ListUsers(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "ListUsers")
+
+	filter := model.UserListFilter{
+		Query: c.Query("q"),
+		Role:  model.Role(c.Query("role")),
+	}
+
+	var err error
+	if filter.Limit, err = utils.ParseQueryInt(c, "limit"); err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "limit must be an integer"))
+		return
+	}
+	if filter.Offset, err = utils.ParseQueryInt(c, "offset"); err != nil {
+		apperror.Respond(c, apperror.Validation("invalid_query", "offset must be an integer"))
+		return
+	}
+	if value := c.Query("disabled"); value != "" {
+		disabled, err := strconv.ParseBool(value)
+		if err != nil {
+			apperror.Respond(c, apperror.Validation("invalid_query", "disabled must be true or false"))
+			return
+		}
+		filter.Disabled = &disabled
+	}
+
+	page, err := h.userService.ListUsers(c.Request.Context(), &filter)
+	if err != nil {
+		logger.Warn("Failed to list users: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	response := make([]AdminUserResponse, 0, len(page.Users))
+	for _, user := range page.Users {
+		response = append(response, newAdminUserResponse(user))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": response,
+		"total": page.Total,
+	})
+}
+
+// GetUserActivity handles viewing a user's activity (admin only)
+func (h *Handler) GetUserActivity(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "GetUserActivity")
+
+	userID := c.Param("id")
+	activity, err := h.userService.GetUserActivity(c.Request.Context(), middleware.GetViewer(c), userID)
+	if err != nil {
+		logger.With("targetUserID", userID).Warn("Failed to get user activity: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	response := ActivityResponse{
+		User:           newAdminUserResponse(activity.User),
+		BlogCount:      activity.BlogCount,
+		CommentCount:   activity.CommentCount,
+		LikeCount:      activity.LikeCount,
+		BookmarkCount:  activity.BookmarkCount,
+		RecentBlogs:    make([]ActivityBlogResponse, 0, len(activity.RecentBlogs)),
+		RecentComments: make([]ActivityCommentResponse, 0, len(activity.RecentComments)),
+	}
+	for _, blog := range activity.RecentBlogs {
+		response.RecentBlogs = append(response.RecentBlogs, ActivityBlogResponse{
+			ID:        blog.ID.Hex(),
+			Title:     blog.Title,
+			Slug:      blog.Slug,
+			Status:    string(blog.Status),
+			CreatedAt: blog.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+	for _, comment := range activity.RecentComments {
+		response.RecentComments = append(response.RecentComments, ActivityCommentResponse{
+			ID:        comment.ID.Hex(),
+			BlogID:    comment.BlogID.Hex(),
+			Content:   comment.Content,
+			CreatedAt: comment.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		})
+	}
+	if activity.LastSignInAt != nil {
+		response.LastSignInAt = activity.LastSignInAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SetRoles handles replacing the roles of a user (admin only)
+func (h *Handler) SetRoles(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "SetRoles")
+
+	var input SetRolesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		apperror.Respond(c, apperror.InvalidRequest(err))
+		return
+	}
+
+	userID := c.Param("id")
+	user, err := h.userService.SetUserRoles(c.Request.Context(), c.GetString("uid"), userID, input.Roles)
+	if err != nil {
+		logger.With("targetUserID", userID).Warn("Failed to set user roles: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+// GrantRole handles adding a role to a user (admin only)
+func (h *Handler) GrantRole(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "GrantRole")
+
+	userID := c.Param("id")
+	role := model.Role(c.Param("role"))
+	user, err := h.userService.GrantRole(c.Request.Context(), userID, role)
+	if err != nil {
+		logger.With("targetUserID", userID, "role", role).Warn("Failed to grant role: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+// RevokeRole handles removing a role from a user (admin only)
+func (h *Handler) RevokeRole(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "RevokeRole")
+
+	userID := c.Param("id")
+	role := model.Role(c.Param("role"))
+	user, err := h.userService.RevokeRole(c.Request.Context(), c.GetString("uid"), userID, role)
+	if err != nil {
+		logger.With("targetUserID", userID, "role", role).Warn("Failed to revoke role: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+// DisableUser handles disabling a user's account (admin only)
+func (h *Handler) DisableUser(c *gin.Context) {
+	h.setUserDisabled(c, "DisableUser", true)
+}
+
+// EnableUser handles re-enabling a user's account (admin only)
+func (h *Handler) EnableUser(c *gin.Context) {
+	h.setUserDisabled(c, "EnableUser", false)
+}
+
+// setUserDisabled disables or enables the user given by the id parameter
+func (h *Handler) setUserDisabled(c *gin.Context, operation string, disabled bool) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", operation)
+
+	userID := c.Param("id")
+	user, err := h.userService.SetUserDisabled(c.Request.Context(), c.GetString("uid"), userID, disabled)
+	if err != nil {
+		logger.With("targetUserID", userID).Warn("Failed to update account status: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAdminUserResponse(user))
+}
+
+// RevokeTokens handles revoking every token of a user, forcing them to sign in again (admin only)
+func (h *Handler) RevokeTokens(c *gin.Context) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", "RevokeTokens")
+
+	userID := c.Param("id")
+	if err := h.userService.RevokeUserTokens(c.Request.Context(), userID); err != nil {
+		logger.With("targetUserID", userID).Warn("Failed to revoke user tokens: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,75 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// writeException builds the error MongoDB reports for a duplicate key in an
+// index on keyPattern
+func writeException(t *testing.T, message string, keyPattern bson.D) error {
+	t.Helper()
+
+	raw, err := bson.Marshal(bson.D{
+		{Key: "index", Value: 0},
+		{Key: "code", Value: 11000},
+		{Key: "errmsg", Value: message},
+		{Key: "keyPattern", Value: keyPattern},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: message, Raw: raw}}}
+}
+
+func TestDuplicateKeyError(t *testing.T) {
+	otherErr := errors.New("connection reset")
+
+	cases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "Email",
+			err:  writeException(t, "E11000 duplicate key error collection: lni.users index: email_1", bson.D{{Key: "email", Value: 1}}),
+			want: ErrEmailTaken,
+		},
+		{
+			name: "RenamedEmailIndex",
+			err:  writeException(t, "E11000 duplicate key error collection: lni.users index: unique_email", bson.D{{Key: "email", Value: 1}}),
+			want: ErrEmailTaken,
+		},
+		{
+			name: "ID",
+			err:  writeException(t, "E11000 duplicate key error collection: lni.users index: _id_", bson.D{{Key: "_id", Value: 1}}),
+			want: ErrUserExists,
+		},
+		{
+			// Only the key pattern counts, not the message text
+			name: "EmailInMessageOnly",
+			err:  writeException(t, "E11000 duplicate key error collection: lni.users index: _id_ dup key: { _id: \"index: email_\" }", bson.D{{Key: "_id", Value: 1}}),
+			want: ErrUserExists,
+		},
+		{
+			name: "Other",
+			err:  otherErr,
+			want: otherErr,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := duplicateKeyError(tc.err); !errors.Is(got, tc.want) {
+				t.Errorf("duplicateKeyError = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if err := duplicateKeyError(nil); err != nil {
+		t.Errorf("duplicateKeyError(nil) = %v", err)
+	}
+}
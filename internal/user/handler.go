@@ -1,8 +1,12 @@
 package user
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
+	"github.com/dksensei/letsnormalizeit/internal/middleware"
 	"github.com/dksensei/letsnormalizeit/internal/model"
 	"github.com/dksensei/letsnormalizeit/internal/utils"
 	"github.com/gin-gonic/gin"
@@ -39,12 +43,7 @@ type UserResponse struct {
 
 // Login handles user login after token validation by middleware
 func (h *Handler) Login(c *gin.Context) {
-	logger := utils.NewLogContext(
-		"operation", "Login",
-		"path", c.Request.URL.Path,
-		"method", c.Request.Method,
-		"clientIP", c.ClientIP(),
-	)
+	logger := utils.FromContext(c.Request.Context()).With("operation", "Login")
 
 	logger.Info("Processing user login request")
 
@@ -52,7 +51,7 @@ func (h *Handler) Login(c *gin.Context) {
 	uid, exists := c.Get("uid")
 	if !exists {
 		logger.Error("User ID not found in context - authentication middleware may have failed")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		apperror.Respond(c, apperror.ErrUnauthenticated)
 		return
 	}
 
@@ -63,7 +62,10 @@ func (h *Handler) Login(c *gin.Context) {
 	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
 		logger.With("userID", userID).Warn("User not found in database: %v", err)
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found. Please register first."})
+		if errors.Is(err, ErrUserNotFound) {
+			err = ErrNotRegistered
+		}
+		apperror.Respond(c, err)
 		return
 	}
 
@@ -82,19 +84,14 @@ func (h *Handler) Login(c *gin.Context) {
 
 // LoginWithAutoRegister handles user login with automatic registration if user doesn't exist
 func (h *Handler) LoginWithAutoRegister(c *gin.Context) {
-	logger := utils.NewLogContext(
-		"operation", "LoginWithAutoRegister",
-		"path", c.Request.URL.Path,
-		"method", c.Request.Method,
-		"clientIP", c.ClientIP(),
-	)
+	logger := utils.FromContext(c.Request.Context()).With("operation", "LoginWithAutoRegister")
 
 	logger.Info("Processing login with auto-registration request")
 
 	var input UserRegistrationInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		logger.Error("Invalid request body: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperror.Respond(c, apperror.InvalidRequest(err))
 		return
 	}
 
@@ -104,7 +101,7 @@ func (h *Handler) LoginWithAutoRegister(c *gin.Context) {
 	uid, exists := c.Get("uid")
 	if !exists {
 		logger.Error("User ID not found in context - authentication middleware may have failed")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		apperror.Respond(c, apperror.ErrUnauthenticated)
 		return
 	}
 
@@ -113,10 +110,15 @@ func (h *Handler) LoginWithAutoRegister(c *gin.Context) {
 
 	// Try to get existing user first
 	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		logger.With("userID", userID).Error("Failed to get user: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
 	if err != nil {
 		// User doesn't exist, create a new one
 		logger.With("userID", userID).Info("User not found in database, creating new user")
-		
+
 		newUser := model.NewUser(
 			userID,
 			input.Name,
@@ -127,10 +129,10 @@ func (h *Handler) LoginWithAutoRegister(c *gin.Context) {
 		user, err = h.userService.StoreUser(c.Request.Context(), newUser)
 		if err != nil {
 			logger.With("userID", userID).Error("Failed to create new user: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			apperror.Respond(c, err)
 			return
 		}
-		
+
 		logger.With("userID", userID, "email", user.Email).Info("New user created successfully")
 	} else {
 		logger.With("userID", userID, "email", user.Email).Info("Existing user found, returning user data")
@@ -149,19 +151,14 @@ func (h *Handler) LoginWithAutoRegister(c *gin.Context) {
 
 // RegisterUser handles user registration after token validation by middleware
 func (h *Handler) RegisterUser(c *gin.Context) {
-	logger := utils.NewLogContext(
-		"operation", "RegisterUser",
-		"path", c.Request.URL.Path,
-		"method", c.Request.Method,
-		"clientIP", c.ClientIP(),
-	)
+	logger := utils.FromContext(c.Request.Context()).With("operation", "RegisterUser")
 
 	logger.Info("Processing user registration request")
 
 	var input UserRegistrationInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		logger.Error("Invalid request body: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apperror.Respond(c, apperror.InvalidRequest(err))
 		return
 	}
 
@@ -171,7 +168,7 @@ func (h *Handler) RegisterUser(c *gin.Context) {
 	uid, exists := c.Get("uid")
 	if !exists {
 		logger.Error("User ID not found in context - authentication middleware may have failed")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		apperror.Respond(c, apperror.ErrUnauthenticated)
 		return
 	}
 
@@ -190,7 +187,7 @@ func (h *Handler) RegisterUser(c *gin.Context) {
 	user, err := h.userService.StoreUser(c.Request.Context(), newUser)
 	if err != nil {
 		logger.With("userID", userID).Error("Failed to store user in database: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperror.Respond(c, err)
 		return
 	}
 
@@ -205,4 +202,38 @@ func (h *Handler) RegisterUser(c *gin.Context) {
 		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	})
-} 
\ No newline at end of file
+}
+
+// ToggleLike handles liking or unliking a blog for the authenticated user
+func (h *Handler) ToggleLike(c *gin.Context) {
+	h.toggleReaction(c, "ToggleLike", h.userService.ToggleLike)
+}
+
+// ToggleBookmark handles bookmarking or unbookmarking a blog for the authenticated user
+func (h *Handler) ToggleBookmark(c *gin.Context) {
+	h.toggleReaction(c, "ToggleBookmark", h.userService.ToggleBookmark)
+}
+
+// toggleReaction runs a like or bookmark toggle and returns the blog's new reaction state
+func (h *Handler) toggleReaction(c *gin.Context, operation string, toggle func(ctx context.Context, viewer model.Viewer, blogID string) (*model.ReactionResult, error)) {
+	logger := utils.FromContext(c.Request.Context()).With("operation", operation)
+
+	// Get the user ID from the context (set by auth middleware)
+	uid, exists := c.Get("uid")
+	if !exists {
+		logger.Error("User ID not found in context - authentication middleware may have failed")
+		apperror.Respond(c, apperror.ErrUnauthenticated)
+		return
+	}
+
+	userID := uid.(string)
+	blogID := c.Param("id")
+	result, err := toggle(c.Request.Context(), middleware.GetViewer(c), blogID)
+	if err != nil {
+		logger.With("userID", userID, "blogID", blogID).Warn("Failed to toggle reaction: %v", err)
+		apperror.Respond(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,248 @@
+package user
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository is a thread-safe in-memory implementation of
+// model.UserRepository, used by tests and local development without MongoDB.
+// Users are copied on the way in and out, so callers never share state with
+// the store.
+type MemoryRepository struct {
+	mu    sync.RWMutex
+	users map[string]*model.User
+}
+
+// Ensure MemoryRepository implements model.UserRepository
+var _ model.UserRepository = (*MemoryRepository)(nil)
+
+// NewMemoryRepository creates an empty in-memory user repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		users: make(map[string]*model.User),
+	}
+}
+
+// FindByID finds a user by ID
+func (r *MemoryRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return cloneUser(user), nil
+}
+
+// FindByEmail finds a user by email
+func (r *MemoryRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return cloneUser(user), nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+// Create creates a new user
+func (r *MemoryRepository) Create(ctx context.Context, user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[user.ID]; ok {
+		return ErrUserExists
+	}
+	if r.emailTaken(user) {
+		return ErrEmailTaken
+	}
+	r.users[user.ID] = cloneUser(user)
+	return nil
+}
+
+// Update updates an existing user
+func (r *MemoryRepository) Update(ctx context.Context, user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user.UpdatedAt = time.Now()
+
+	if _, ok := r.users[user.ID]; !ok {
+		return nil
+	}
+	if r.emailTaken(user) {
+		return ErrEmailTaken
+	}
+	r.users[user.ID] = cloneUser(user)
+	return nil
+}
+
+// emailTaken reports whether another user has the user's email. Empty
+// emails never collide, as with the MongoDB unique index.
+func (r *MemoryRepository) emailTaken(user *model.User) bool {
+	if user.Email == "" {
+		return false
+	}
+	for _, other := range r.users {
+		if other.ID != user.ID && other.Email == user.Email {
+			return true
+		}
+	}
+	return false
+}
+
+// AddBookmark adds a bookmark to a user
+func (r *MemoryRepository) AddBookmark(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+	return r.modify(userID, func(user *model.User) {
+		user.Bookmarks = addToSet(user.Bookmarks, blogID)
+	})
+}
+
+// RemoveBookmark removes a bookmark from a user
+func (r *MemoryRepository) RemoveBookmark(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+	return r.modify(userID, func(user *model.User) {
+		user.Bookmarks = pull(user.Bookmarks, blogID)
+	})
+}
+
+// AddLike adds a like to a user
+func (r *MemoryRepository) AddLike(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+	return r.modify(userID, func(user *model.User) {
+		user.Likes = addToSet(user.Likes, blogID)
+	})
+}
+
+// RemoveLike removes a like from a user
+func (r *MemoryRepository) RemoveLike(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+	return r.modify(userID, func(user *model.User) {
+		user.Likes = pull(user.Likes, blogID)
+	})
+}
+
+// RemoveBlog removes a blog from the bookmarks and likes of every user
+func (r *MemoryRepository) RemoveBlog(ctx context.Context, blogID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if slices.Contains(user.Bookmarks, blogID) || slices.Contains(user.Likes, blogID) {
+			user.Bookmarks = pull(user.Bookmarks, blogID)
+			user.Likes = pull(user.Likes, blogID)
+			user.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+// SetRoles replaces a user's roles, keeping the legacy is_admin flag in sync
+func (r *MemoryRepository) SetRoles(ctx context.Context, userID string, roles []model.Role) error {
+	return r.modify(userID, func(user *model.User) {
+		user.Roles = slices.Clone(roles)
+		user.IsAdmin = model.HasRole(roles, model.RoleAdmin)
+	})
+}
+
+// SetDisabled marks a user as disabled or enabled
+func (r *MemoryRepository) SetDisabled(ctx context.Context, userID string, disabled bool) error {
+	return r.modify(userID, func(user *model.User) {
+		user.Disabled = disabled
+	})
+}
+
+// List finds a page of users matching the filter, newest first, along with
+// the total number of matches
+func (r *MemoryRepository) List(ctx context.Context, filter *model.UserListFilter) ([]*model.User, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []*model.User
+	for _, user := range r.users {
+		if matchesFilter(user, filter) {
+			matches = append(matches, user)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].CreatedAt.Equal(matches[j].CreatedAt) {
+			return matches[i].CreatedAt.After(matches[j].CreatedAt)
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	total := int64(len(matches))
+	start := min(max(filter.Offset, 0), total)
+	end := total
+	if filter.Limit > 0 {
+		end = min(start+filter.Limit, total)
+	}
+
+	users := make([]*model.User, 0, end-start)
+	for _, user := range matches[start:end] {
+		users = append(users, cloneUser(user))
+	}
+	return users, total, nil
+}
+
+// modify applies change to a stored user and bumps its UpdatedAt. Like the
+// MongoDB repository, it silently does nothing for unknown users.
+func (r *MemoryRepository) modify(userID string, change func(user *model.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user, ok := r.users[userID]; ok {
+		change(user)
+		user.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// matchesFilter mirrors the MongoDB query built by Repository.List
+func matchesFilter(user *model.User, filter *model.UserListFilter) bool {
+	if filter.Query != "" {
+		query := strings.ToLower(filter.Query)
+		if !strings.Contains(strings.ToLower(user.Name), query) && !strings.Contains(strings.ToLower(user.Email), query) {
+			return false
+		}
+	}
+	if filter.Role != "" && !model.HasRole(user.EffectiveRoles(), filter.Role) {
+		return false
+	}
+	if filter.Disabled != nil && user.Disabled != *filter.Disabled {
+		return false
+	}
+	return true
+}
+
+// addToSet appends id unless ids already holds it
+func addToSet(ids []primitive.ObjectID, id primitive.ObjectID) []primitive.ObjectID {
+	if slices.Contains(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
+
+// pull removes every occurrence of id from ids
+func pull(ids []primitive.ObjectID, id primitive.ObjectID) []primitive.ObjectID {
+	return slices.DeleteFunc(ids, func(existing primitive.ObjectID) bool {
+		return existing == id
+	})
+}
+
+// cloneUser returns a deep copy of a user
+func cloneUser(user *model.User) *model.User {
+	clone := *user
+	clone.Bookmarks = slices.Clone(user.Bookmarks)
+	clone.Likes = slices.Clone(user.Likes)
+	clone.Roles = slices.Clone(user.Roles)
+	return &clone
+}
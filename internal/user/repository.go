@@ -3,12 +3,19 @@ package user
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/dksensei/letsnormalizeit/internal/db"
 	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const collectionName = "users"
@@ -19,6 +26,9 @@ type Repository struct {
 	collection string
 }
 
+// Ensure Repository implements model.UserRepository
+var _ model.UserRepository = (*Repository)(nil)
+
 // NewRepository creates a new user repository
 func NewRepository(mongodb *db.MongoDB) *Repository {
 	return &Repository{
@@ -28,12 +38,18 @@ func NewRepository(mongodb *db.MongoDB) *Repository {
 }
 
 // FindByID finds a user by ID
-func (r *Repository) FindByID(ctx context.Context, id string) (*model.User, error) {
+func (r *Repository) FindByID(ctx context.Context, id string) (_ *model.User, err error) {
+	ctx, span := startSpan(ctx, "FindByID")
+	defer func() { tracing.End(span, err) }()
+
 	coll := r.db.GetCollection(r.collection)
 
 	var user model.User
-	err := coll.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	err = coll.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrUserNotFound
+		}
 		return nil, err
 	}
 
@@ -41,12 +57,18 @@ func (r *Repository) FindByID(ctx context.Context, id string) (*model.User, erro
 }
 
 // FindByEmail finds a user by email
-func (r *Repository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
+func (r *Repository) FindByEmail(ctx context.Context, email string) (_ *model.User, err error) {
+	ctx, span := startSpan(ctx, "FindByEmail")
+	defer func() { tracing.End(span, err) }()
+
 	coll := r.db.GetCollection(r.collection)
 
 	var user model.User
-	err := coll.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	err = coll.FindOne(ctx, bson.M{"email": email}).Decode(&user)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrUserNotFound
+		}
 		return nil, err
 	}
 
@@ -54,7 +76,10 @@ func (r *Repository) FindByEmail(ctx context.Context, email string) (*model.User
 }
 
 // Create creates a new user
-func (r *Repository) Create(ctx context.Context, user *model.User) error {
+func (r *Repository) Create(ctx context.Context, user *model.User) (err error) {
+	ctx, span := startSpan(ctx, "Create")
+	defer func() { tracing.End(span, err) }()
+
 	coll := r.db.GetCollection(r.collection)
 
 	// Check if user already exists
@@ -63,28 +88,62 @@ func (r *Repository) Create(ctx context.Context, user *model.User) error {
 		return err
 	}
 	if count > 0 {
-		return errors.New("user already exists")
+		return ErrUserExists
 	}
 
 	_, err = coll.InsertOne(ctx, user)
-	return err
+	return duplicateKeyError(err)
 }
 
 // Update updates an existing user
-func (r *Repository) Update(ctx context.Context, user *model.User) error {
+func (r *Repository) Update(ctx context.Context, user *model.User) (err error) {
+	ctx, span := startSpan(ctx, "Update")
+	defer func() { tracing.End(span, err) }()
+
 	coll := r.db.GetCollection(r.collection)
 
 	user.UpdatedAt = time.Now()
 
-	_, err := coll.ReplaceOne(ctx, bson.M{"_id": user.ID}, user)
-	return err
+	_, err = coll.ReplaceOne(ctx, bson.M{"_id": user.ID}, user)
+	return duplicateKeyError(err)
+}
+
+// duplicateKeyError maps a unique index violation to ErrEmailTaken or
+// ErrUserExists, depending on the index. Other errors are returned unchanged.
+func duplicateKeyError(err error) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	if duplicateKeyOn(err, "email") {
+		return fmt.Errorf("%w: %v", ErrEmailTaken, err)
+	}
+	return fmt.Errorf("%w: %v", ErrUserExists, err)
+}
+
+// duplicateKeyOn reports whether a duplicate key error violates an index on
+// field, going by the key pattern the server reports with the write error
+// rather than by the index name or message text
+func duplicateKeyOn(err error, field string) bool {
+	var writeErr mongo.WriteException
+	if !errors.As(err, &writeErr) {
+		return false
+	}
+	for _, we := range writeErr.WriteErrors {
+		if _, lookupErr := we.Raw.LookupErr("keyPattern", field); lookupErr == nil {
+			return true
+		}
+	}
+	return false
 }
 
 // AddBookmark adds a bookmark to a user
-func (r *Repository) AddBookmark(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+func (r *Repository) AddBookmark(ctx context.Context, userID string, blogID primitive.ObjectID) (err error) {
+	ctx, span := startSpan(ctx, "AddBookmark")
+	defer func() { tracing.End(span, err) }()
+
 	coll := r.db.GetCollection(r.collection)
 
-	_, err := coll.UpdateOne(
+	_, err = coll.UpdateOne(
 		ctx,
 		bson.M{"_id": userID},
 		bson.M{
@@ -96,10 +155,13 @@ func (r *Repository) AddBookmark(ctx context.Context, userID string, blogID prim
 }
 
 // RemoveBookmark removes a bookmark from a user
-func (r *Repository) RemoveBookmark(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+func (r *Repository) RemoveBookmark(ctx context.Context, userID string, blogID primitive.ObjectID) (err error) {
+	ctx, span := startSpan(ctx, "RemoveBookmark")
+	defer func() { tracing.End(span, err) }()
+
 	coll := r.db.GetCollection(r.collection)
 
-	_, err := coll.UpdateOne(
+	_, err = coll.UpdateOne(
 		ctx,
 		bson.M{"_id": userID},
 		bson.M{
@@ -111,10 +173,13 @@ func (r *Repository) RemoveBookmark(ctx context.Context, userID string, blogID p
 }
 
 // AddLike adds a like to a user
-func (r *Repository) AddLike(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+func (r *Repository) AddLike(ctx context.Context, userID string, blogID primitive.ObjectID) (err error) {
+	ctx, span := startSpan(ctx, "AddLike")
+	defer func() { tracing.End(span, err) }()
+
 	coll := r.db.GetCollection(r.collection)
 
-	_, err := coll.UpdateOne(
+	_, err = coll.UpdateOne(
 		ctx,
 		bson.M{"_id": userID},
 		bson.M{
@@ -126,10 +191,13 @@ func (r *Repository) AddLike(ctx context.Context, userID string, blogID primitiv
 }
 
 // RemoveLike removes a like from a user
-func (r *Repository) RemoveLike(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+func (r *Repository) RemoveLike(ctx context.Context, userID string, blogID primitive.ObjectID) (err error) {
+	ctx, span := startSpan(ctx, "RemoveLike")
+	defer func() { tracing.End(span, err) }()
+
 	coll := r.db.GetCollection(r.collection)
 
-	_, err := coll.UpdateOne(
+	_, err = coll.UpdateOne(
 		ctx,
 		bson.M{"_id": userID},
 		bson.M{
@@ -139,3 +207,134 @@ func (r *Repository) RemoveLike(ctx context.Context, userID string, blogID primi
 	)
 	return err
 }
+
+// RemoveBlog removes a blog from the bookmarks and likes of every user
+func (r *Repository) RemoveBlog(ctx context.Context, blogID primitive.ObjectID) (err error) {
+	ctx, span := startSpan(ctx, "RemoveBlog")
+	defer func() { tracing.End(span, err) }()
+
+	coll := r.db.GetCollection(r.collection)
+
+	_, err = coll.UpdateMany(
+		ctx,
+		bson.M{"$or": bson.A{bson.M{"bookmarks": blogID}, bson.M{"likes": blogID}}},
+		bson.M{
+			"$pull": bson.M{"bookmarks": blogID, "likes": blogID},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	return err
+}
+
+// SetRoles replaces a user's roles, keeping the legacy is_admin flag in sync
+func (r *Repository) SetRoles(ctx context.Context, userID string, roles []model.Role) (err error) {
+	ctx, span := startSpan(ctx, "SetRoles")
+	defer func() { tracing.End(span, err) }()
+
+	coll := r.db.GetCollection(r.collection)
+
+	_, err = coll.UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{
+			"roles":      roles,
+			"is_admin":   model.HasRole(roles, model.RoleAdmin),
+			"updated_at": time.Now(),
+		}},
+	)
+	return err
+}
+
+// SetDisabled marks a user as disabled or enabled
+func (r *Repository) SetDisabled(ctx context.Context, userID string, disabled bool) (err error) {
+	ctx, span := startSpan(ctx, "SetDisabled")
+	defer func() { tracing.End(span, err) }()
+
+	coll := r.db.GetCollection(r.collection)
+
+	_, err = coll.UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"disabled": disabled, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// List finds a page of users matching the filter, newest first, along with
+// the total number of matches
+func (r *Repository) List(ctx context.Context, filter *model.UserListFilter) (_ []*model.User, _ int64, err error) {
+	ctx, span := startSpan(ctx, "List")
+	defer func() { tracing.End(span, err) }()
+
+	coll := r.db.GetCollection(r.collection)
+
+	var conditions bson.A
+	if filter.Query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(filter.Query), Options: "i"}
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"name": pattern},
+			bson.M{"email": pattern},
+		}})
+	}
+	if filter.Role != "" {
+		conditions = append(conditions, roleCondition(filter.Role))
+	}
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			conditions = append(conditions, bson.M{"disabled": true})
+		} else {
+			conditions = append(conditions, bson.M{"disabled": bson.M{"$ne": true}})
+		}
+	}
+
+	query := bson.M{}
+	if len(conditions) > 0 {
+		query["$and"] = conditions
+	}
+
+	total, err := coll.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(filter.Limit).
+		SetSkip(filter.Offset)
+
+	cursor, err := coll.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []*model.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// startSpan starts the span of a repository call
+func startSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "user.Repository."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mongodb"),
+			attribute.String("db.collection.name", collectionName),
+		),
+	)
+}
+
+// roleCondition matches users whose effective roles include role, taking
+// users without stored roles and the legacy is_admin flag into account
+func roleCondition(role model.Role) bson.M {
+	matches := bson.A{bson.M{"roles": role}}
+	if model.HasRole(model.DefaultRoles, role) {
+		matches = append(matches, bson.M{"roles": bson.M{"$in": bson.A{nil, bson.A{}}}})
+	}
+	if role == model.RoleAdmin {
+		matches = append(matches, bson.M{"is_admin": true})
+	}
+	return bson.M{"$or": matches}
+}
@@ -0,0 +1,22 @@
+package user_test
+
+import (
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/db/dbtest"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/repotest"
+	"github.com/dksensei/letsnormalizeit/internal/user"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repotest.TestUserRepository(t, func(t *testing.T) model.UserRepository {
+		return user.NewMemoryRepository()
+	})
+}
+
+func TestRepository(t *testing.T) {
+	repotest.TestUserRepository(t, func(t *testing.T) model.UserRepository {
+		return user.NewRepository(dbtest.NewMongoDB(t))
+	})
+}
@@ -3,32 +3,75 @@ package user
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/dksensei/letsnormalizeit/internal/apperror"
 	"github.com/dksensei/letsnormalizeit/internal/model"
 	"github.com/dksensei/letsnormalizeit/internal/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+var (
+	// ErrUserNotFound is returned when a user does not exist
+	ErrUserNotFound = apperror.NotFound("user_not_found", "user not found")
+
+	// ErrNotRegistered is returned when signing in before registering
+	ErrNotRegistered = apperror.NotFound("user_not_registered", "user not found, please register first")
+
+	// ErrUserExists is returned when creating a user that already exists
+	ErrUserExists = apperror.Conflict("user_exists", "user already exists")
+
+	// ErrEmailTaken is returned when another user already has the email
+	ErrEmailTaken = apperror.Conflict("email_taken", "email already belongs to another user")
+
+	// ErrInvalidRole is returned when assigning an unknown role
+	ErrInvalidRole = apperror.Validation("invalid_role", "invalid role")
+
+	// ErrUserDisabled is returned when resolving the roles of a disabled user
+	ErrUserDisabled = apperror.Forbidden("account_disabled", "user account is disabled")
+
+	// ErrSelfModification is returned when an admin tries to disable or demote themselves
+	ErrSelfModification = apperror.Forbidden("self_modification", "admins cannot disable or demote themselves")
+
+	// ErrInvalidInput is wrapped by all user validation errors
+	ErrInvalidInput = apperror.Validation("invalid_user_input", "invalid user input")
+)
+
+const (
+	defaultUserPageLimit = 20
+	maxUserPageLimit     = 100
+	activityRecentLimit  = 5
+)
+
 // Service handles user-related business logic
 type Service struct {
-	repo        *Repository
-	authService model.AuthService
+	repo           model.UserRepository
+	authService    model.AuthService
+	blogService    model.BlogService
+	commentService model.CommentService
 }
 
-// Ensure Service implements model.UserService
-var _ model.UserService = (*Service)(nil)
+// Ensure Service implements model.UserService and model.RoleResolver
+var (
+	_ model.UserService  = (*Service)(nil)
+	_ model.RoleResolver = (*Service)(nil)
+)
 
 // NewService creates a new user service
-func NewService(repo *Repository, authService model.AuthService) *Service {
+func NewService(repo model.UserRepository, authService model.AuthService, blogService model.BlogService, commentService model.CommentService) *Service {
 	return &Service{
-		repo:        repo,
-		authService: authService,
+		repo:           repo,
+		authService:    authService,
+		blogService:    blogService,
+		commentService: commentService,
 	}
 }
 
 // GetUserByID gets a user by ID
 func (s *Service) GetUserByID(ctx context.Context, id string) (*model.User, error) {
-	logger := utils.NewLogContext("userID", id, "operation", "GetUserByID")
+	logger := utils.FromContext(ctx).With("userID", id, "operation", "GetUserByID")
 
 	// Try to get from database first
 	logger.Debug("Attempting to find user in database")
@@ -37,12 +80,19 @@ func (s *Service) GetUserByID(ctx context.Context, id string) (*model.User, erro
 		logger.Debug("User found in database")
 		return user, nil
 	}
+	if !errors.Is(err, ErrUserNotFound) {
+		logger.Error("Failed to find user in database: %v", err)
+		return nil, err
+	}
 
 	// If not found in database, try to get from Firebase
 	logger.Debug("User not found in database, trying Firebase")
 	firebaseUser, err := s.authService.GetUser(ctx, id)
 	if err != nil {
 		logger.Error("Failed to get user from Firebase: %v", err)
+		if apperror.IsKind(err, apperror.KindNotFound) {
+			return nil, ErrUserNotFound
+		}
 		return nil, err
 	}
 
@@ -66,10 +116,14 @@ func (s *Service) GetUserByID(ctx context.Context, id string) (*model.User, erro
 
 // StoreUser stores a user in the database
 func (s *Service) StoreUser(ctx context.Context, user *model.User) (*model.User, error) {
-	logger := utils.NewLogContext("userID", user.ID, "operation", "StoreUser")
+	logger := utils.FromContext(ctx).With("userID", user.ID, "operation", "StoreUser")
 
 	// Check if user already exists
 	existingUser, err := s.repo.FindByID(ctx, user.ID)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		logger.Error("Failed to find user in database: %v", err)
+		return nil, err
+	}
 	if err == nil {
 		// User exists, update the user
 		logger.Debug("User exists, updating user")
@@ -112,68 +166,333 @@ func (s *Service) UpdateUserProfile(ctx context.Context, id, name string) (*mode
 	return user, nil
 }
 
-// ToggleBookmark toggles a bookmark for a user
-func (s *Service) ToggleBookmark(ctx context.Context, userID, blogID string) error {
-	// Check if userID exists
-	_, err := s.GetUserByID(ctx, userID)
+// GetUserRoles gets the effective roles of a user. It fails with
+// ErrUserDisabled for disabled users.
+func (s *Service) GetUserRoles(ctx context.Context, uid string) ([]model.Role, error) {
+	user, err := s.GetUserByID(ctx, uid)
 	if err != nil {
-		return errors.New("user not found")
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, ErrUserDisabled
+	}
+	return user.EffectiveRoles(), nil
+}
+
+// SetUserRoles replaces a user's roles. Like RevokeRole, it does not let
+// admins take away their own admin role.
+func (s *Service) SetUserRoles(ctx context.Context, actorID, uid string, roles []model.Role) (*model.User, error) {
+	if actorID == uid && !model.HasRole(roles, model.RoleAdmin) {
+		return nil, ErrSelfModification
+	}
+	return s.setUserRoles(ctx, uid, roles)
+}
+
+// setUserRoles replaces a user's roles. The roles are written to the user's
+// custom claims first, so a revoked admin claim does not linger in tokens
+// issued from then on, and only then stored; the server itself only trusts
+// the stored roles, so a failure leaves them as they were.
+func (s *Service) setUserRoles(ctx context.Context, uid string, roles []model.Role) (*model.User, error) {
+	logger := utils.FromContext(ctx).With("userID", uid, "operation", "SetUserRoles")
+
+	normalized := make([]model.Role, 0, len(roles))
+	for _, role := range roles {
+		if !role.Valid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRole, role)
+		}
+		if !model.HasRole(normalized, role) {
+			normalized = append(normalized, role)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("%w: at least one role is required", ErrInvalidRole)
 	}
 
-	// Convert blogID to ObjectID
-	objID, err := primitive.ObjectIDFromHex(blogID)
+	user, err := s.GetUserByID(ctx, uid)
 	if err != nil {
-		return errors.New("invalid blog ID format")
+		return nil, err
 	}
 
-	// Check if user already has the bookmark
-	user, err := s.repo.FindByID(ctx, userID)
+	if err := s.mirrorRoleClaims(ctx, uid, normalized); err != nil {
+		logger.Error("Failed to mirror roles into custom claims: %v", err)
+		return nil, err
+	}
+
+	if err := s.repo.SetRoles(ctx, uid, normalized); err != nil {
+		logger.Error("Failed to update user roles in database: %v", err)
+		if restoreErr := s.mirrorRoleClaims(ctx, uid, user.EffectiveRoles()); restoreErr != nil {
+			logger.Error("Failed to restore previous roles in custom claims: %v", restoreErr)
+		}
+		return nil, err
+	}
+	user.Roles = normalized
+	user.IsAdmin = model.HasRole(normalized, model.RoleAdmin)
+
+	logger.With("roles", normalized).Info("User roles updated successfully")
+	return user, nil
+}
+
+// GrantRole adds a role to a user
+func (s *Service) GrantRole(ctx context.Context, uid string, role model.Role) (*model.User, error) {
+	user, err := s.GetUserByID(ctx, uid)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	roles := user.EffectiveRoles()
+	if !model.HasRole(roles, role) {
+		roles = append(roles, role)
+	}
+	return s.setUserRoles(ctx, uid, roles)
+}
+
+// RevokeRole removes a role from a user. Admins cannot revoke their own admin
+// role, so there is always someone left to undo a mistake.
+func (s *Service) RevokeRole(ctx context.Context, actorID, uid string, role model.Role) (*model.User, error) {
+	if actorID == uid && role == model.RoleAdmin {
+		return nil, ErrSelfModification
 	}
 
-	// Check if the blog is already bookmarked
-	for _, bookmark := range user.Bookmarks {
-		if bookmark == objID {
-			// Remove the bookmark
-			return s.repo.RemoveBookmark(ctx, userID, objID)
+	user, err := s.GetUserByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	current := user.EffectiveRoles()
+	roles := make([]model.Role, 0, len(current))
+	for _, r := range current {
+		if r != role {
+			roles = append(roles, r)
 		}
 	}
+	if len(roles) == 0 {
+		roles = []model.Role{model.RoleReader}
+	}
+	return s.setUserRoles(ctx, uid, roles)
+}
+
+// ListUsers lists a page of users matching the filter
+func (s *Service) ListUsers(ctx context.Context, filter *model.UserListFilter) (*model.UserPage, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Role != "" && !filter.Role.Valid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRole, filter.Role)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultUserPageLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxUserPageLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, maxUserPageLimit)
+	}
+	if filter.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", ErrInvalidInput)
+	}
 
-	// Add the bookmark
-	return s.repo.AddBookmark(ctx, userID, objID)
+	users, total, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &model.UserPage{Users: users, Total: total}, nil
 }
 
-// ToggleLike toggles a like for a user
-func (s *Service) ToggleLike(ctx context.Context, userID, blogID string) error {
-	// Check if userID exists
-	_, err := s.GetUserByID(ctx, userID)
+// GetUserActivity summarizes a user's blogs, comments and reactions. Recent
+// blogs are listed as the given viewer would see them.
+func (s *Service) GetUserActivity(ctx context.Context, viewer model.Viewer, uid string) (*model.UserActivity, error) {
+	user, err := s.repo.FindByID(ctx, uid)
 	if err != nil {
-		return errors.New("user not found")
+		return nil, err
 	}
 
-	// Convert blogID to ObjectID
-	objID, err := primitive.ObjectIDFromHex(blogID)
+	activity := &model.UserActivity{
+		User:          user,
+		LikeCount:     len(user.Likes),
+		BookmarkCount: len(user.Bookmarks),
+	}
+
+	if activity.BlogCount, err = s.blogService.CountBlogsByAuthor(ctx, uid); err != nil {
+		return nil, err
+	}
+	page, err := s.blogService.ListBlogs(ctx, viewer, &model.BlogListFilter{AuthorID: uid, Limit: activityRecentLimit})
 	if err != nil {
-		return errors.New("invalid blog ID format")
+		return nil, err
 	}
+	activity.RecentBlogs = page.Blogs
 
-	// Check if user already has liked the blog
-	user, err := s.repo.FindByID(ctx, userID)
+	if activity.RecentComments, activity.CommentCount, err = s.commentService.ListUserComments(ctx, uid, activityRecentLimit); err != nil {
+		return nil, err
+	}
+
+	// Sign-in times are only known to the auth provider
+	if record, err := s.authService.GetUser(ctx, uid); err == nil && record.UserMetadata != nil && record.UserMetadata.LastLogInTimestamp > 0 {
+		lastSignIn := time.UnixMilli(record.UserMetadata.LastLogInTimestamp)
+		activity.LastSignInAt = &lastSignIn
+	}
+
+	return activity, nil
+}
+
+// SetUserDisabled disables or enables a user's account. Disabling also
+// revokes the user's tokens so existing sessions end immediately.
+func (s *Service) SetUserDisabled(ctx context.Context, actorID, uid string, disabled bool) (*model.User, error) {
+	logger := utils.FromContext(ctx).With("userID", uid, "actorID", actorID, "operation", "SetUserDisabled")
+
+	if actorID == uid && disabled {
+		return nil, ErrSelfModification
+	}
+
+	user, err := s.GetUserByID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.SetUserDisabled(ctx, uid, disabled); err != nil {
+		logger.Error("Failed to update account status with the auth provider: %v", err)
+		return nil, err
+	}
+	if disabled {
+		if err := s.authService.RevokeRefreshTokens(ctx, uid); err != nil {
+			logger.Error("Failed to revoke tokens of disabled user: %v", err)
+			return nil, err
+		}
+	}
+
+	if err := s.repo.SetDisabled(ctx, uid, disabled); err != nil {
+		logger.Error("Failed to update account status in database: %v", err)
+		return nil, err
+	}
+	user.Disabled = disabled
+
+	logger.With("disabled", disabled).Info("User account status updated successfully")
+	return user, nil
+}
+
+// RevokeUserTokens revokes every token issued to a user, forcing them to sign in again
+func (s *Service) RevokeUserTokens(ctx context.Context, uid string) error {
+	logger := utils.FromContext(ctx).With("userID", uid, "operation", "RevokeUserTokens")
+
+	if err := s.authService.RevokeRefreshTokens(ctx, uid); err != nil {
+		logger.Error("Failed to revoke user tokens: %v", err)
+		return err
+	}
+
+	logger.Info("User tokens revoked successfully")
+	return nil
+}
+
+// mirrorRoleClaims writes roles into the user's custom claims, preserving
+// any other custom claims already set
+func (s *Service) mirrorRoleClaims(ctx context.Context, uid string, roles []model.Role) error {
+	record, err := s.authService.GetUser(ctx, uid)
 	if err != nil {
 		return err
 	}
 
-	// Check if the blog is already liked
-	for _, like := range user.Likes {
-		if like == objID {
-			// Remove the like
-			return s.repo.RemoveLike(ctx, userID, objID)
+	claims := make(map[string]interface{}, len(record.CustomClaims)+2)
+	for name, value := range record.CustomClaims {
+		claims[name] = value
+	}
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	claims["roles"] = names
+	claims["admin"] = model.HasRole(roles, model.RoleAdmin)
+
+	return s.authService.SetCustomUserClaims(ctx, uid, claims)
+}
+
+// ToggleBookmark toggles a bookmark for a user. The blog's bookmarked_by set is
+// the source of truth: it is flipped atomically first and the user's
+// bookmarks follow. If the user side fails the blog is explicitly set back to
+// its previous state, which stays correct even if the user toggled again
+// concurrently.
+func (s *Service) ToggleBookmark(ctx context.Context, viewer model.Viewer, blogID string) (*model.ReactionResult, error) {
+	userID := viewer.UserID
+	logger := utils.FromContext(ctx).With("userID", userID, "blogID", blogID, "operation", "ToggleBookmark")
+
+	objID, err := s.checkReactionTarget(ctx, viewer, blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.blogService.ToggleBookmark(ctx, blogID, userID)
+	if err != nil {
+		logger.Error("Failed to update blog bookmarks: %v", err)
+		return nil, err
+	}
+
+	result := model.NewReactionResult(updated, userID)
+	if result.Bookmarked {
+		err = s.repo.AddBookmark(ctx, userID, objID)
+	} else {
+		err = s.repo.RemoveBookmark(ctx, userID, objID)
+	}
+	if err != nil {
+		logger.Error("Failed to update user bookmarks, rolling back blog: %v", err)
+		if _, rollbackErr := s.blogService.SetBookmark(ctx, blogID, userID, !result.Bookmarked); rollbackErr != nil {
+			logger.Error("Failed to roll back blog bookmark: %v", rollbackErr)
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ToggleLike toggles a like for a user. The blog's likes set is the source of
+// truth; it is flipped atomically first and the user's likes follow, with the
+// same rollback behaviour as ToggleBookmark.
+func (s *Service) ToggleLike(ctx context.Context, viewer model.Viewer, blogID string) (*model.ReactionResult, error) {
+	userID := viewer.UserID
+	logger := utils.FromContext(ctx).With("userID", userID, "blogID", blogID, "operation", "ToggleLike")
+
+	objID, err := s.checkReactionTarget(ctx, viewer, blogID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.blogService.ToggleLike(ctx, blogID, userID)
+	if err != nil {
+		logger.Error("Failed to update blog likes: %v", err)
+		return nil, err
+	}
+
+	result := model.NewReactionResult(updated, userID)
+	if result.Liked {
+		err = s.repo.AddLike(ctx, userID, objID)
+	} else {
+		err = s.repo.RemoveLike(ctx, userID, objID)
+	}
+	if err != nil {
+		logger.Error("Failed to update user likes, rolling back blog: %v", err)
+		if _, rollbackErr := s.blogService.SetLike(ctx, blogID, userID, !result.Liked); rollbackErr != nil {
+			logger.Error("Failed to roll back blog like: %v", rollbackErr)
 		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RemoveBlogReactions removes a deleted blog from every user's likes and
+// bookmarks. It is registered as a blog.DeleteHook.
+func (s *Service) RemoveBlogReactions(ctx context.Context, blogID primitive.ObjectID) error {
+	return s.repo.RemoveBlog(ctx, blogID)
+}
+
+// checkReactionTarget checks that the user exists and may read the blog,
+// returning the blog's ID. Blogs the viewer may not read are reported as not
+// found, as when reading them.
+func (s *Service) checkReactionTarget(ctx context.Context, viewer model.Viewer, blogID string) (primitive.ObjectID, error) {
+	// Check if userID exists
+	if _, err := s.GetUserByID(ctx, viewer.UserID); err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	blog, err := s.blogService.GetVisibleBlog(ctx, blogID, viewer)
+	if err != nil {
+		return primitive.NilObjectID, err
 	}
 
-	// Add the like
-	return s.repo.AddLike(ctx, userID, objID)
+	return blog.ID, nil
 }
 
 // NewUser creates a new user from Firebase user information
@@ -0,0 +1,206 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/blog"
+	"github.com/dksensei/letsnormalizeit/internal/cache"
+	"github.com/dksensei/letsnormalizeit/internal/comment"
+	"github.com/dksensei/letsnormalizeit/internal/config"
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/server/servertest"
+	"github.com/dksensei/letsnormalizeit/internal/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errWriteFailed = errors.New("write failed")
+
+// failingRepository is a user repository whose reaction writes fail, after
+// running concurrent, if set, as if another request raced with the write
+type failingRepository struct {
+	*user.MemoryRepository
+	concurrent func()
+}
+
+func (r failingRepository) fail() error {
+	if r.concurrent != nil {
+		r.concurrent()
+	}
+	return errWriteFailed
+}
+
+func (r failingRepository) AddLike(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+	return r.fail()
+}
+
+func (r failingRepository) RemoveLike(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+	return r.fail()
+}
+
+func (r failingRepository) AddBookmark(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+	return r.fail()
+}
+
+func (r failingRepository) RemoveBookmark(ctx context.Context, userID string, blogID primitive.ObjectID) error {
+	return r.fail()
+}
+
+// failingAuthService is an auth service that cannot set custom claims
+type failingAuthService struct {
+	*servertest.AuthService
+}
+
+func (s failingAuthService) SetCustomUserClaims(ctx context.Context, uid string, claims map[string]interface{}) error {
+	return errWriteFailed
+}
+
+// fixture is a user service over in-memory repositories and a fake auth
+// service. The service uses userRepo and authService, which wrap users and
+// auth unless configured otherwise.
+type fixture struct {
+	users       *user.MemoryRepository
+	blogs       *blog.MemoryRepository
+	auth        *servertest.AuthService
+	userRepo    model.UserRepository
+	authService model.AuthService
+	blog        *model.Blog
+	service     *user.Service
+}
+
+// newFixture builds a user service, with a published blog by "author" and
+// the users "author" and "reader", after letting configure swap out its
+// dependencies
+func newFixture(t *testing.T, configure func(f *fixture)) *fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	f := &fixture{
+		users: user.NewMemoryRepository(),
+		blogs: blog.NewMemoryRepository(),
+		auth:  servertest.NewAuthService(),
+	}
+	f.userRepo, f.authService = f.users, f.auth
+	if configure != nil {
+		configure(f)
+	}
+
+	appCache := cache.New(cache.NewMemoryStore(), &config.RedisConfig{})
+	blogService := blog.NewService(f.blogs, blog.NewMemoryRevisionRepository(), blog.NewMemorySlugRepository(), appCache)
+	commentService := comment.NewService(comment.NewMemoryRepository(), blogService, appCache, &config.CommentConfig{MaxDepth: 5, RepliesPerLevel: 3})
+	f.service = user.NewService(f.userRepo, f.authService, blogService, commentService)
+
+	for _, uid := range []string{"author", "reader"} {
+		f.auth.Token(uid)
+		if err := f.users.Create(ctx, model.NewUser(uid, uid, uid+"@example.com", "")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var err error
+	f.blog, err = blogService.CreateBlog(ctx, "author", &model.BlogInput{Title: "Reacted", Content: "Content"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestToggleRollsBackBlogWhenUserWriteFails(t *testing.T) {
+	ctx := context.Background()
+	viewer := model.Viewer{UserID: "reader", Roles: []model.Role{model.RoleReader}}
+
+	// The same user toggles again while the first toggle's user write is
+	// failing, so toggling back would leave the blog reacted to
+	var f *fixture
+	var race func()
+	f = newFixture(t, func(f *fixture) {
+		f.userRepo = failingRepository{f.users, func() { race() }}
+	})
+
+	race = func() {
+		if _, err := f.blogs.ToggleLike(ctx, f.blog.ID, "reader"); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := f.service.ToggleLike(ctx, viewer, f.blog.ID.Hex()); !errors.Is(err, errWriteFailed) {
+		t.Fatalf("ToggleLike error = %v, want %v", err, errWriteFailed)
+	}
+
+	race = func() {
+		if _, err := f.blogs.ToggleBookmark(ctx, f.blog.ID, "reader"); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := f.service.ToggleBookmark(ctx, viewer, f.blog.ID.Hex()); !errors.Is(err, errWriteFailed) {
+		t.Fatalf("ToggleBookmark error = %v, want %v", err, errWriteFailed)
+	}
+
+	stored, err := f.blogs.FindByID(ctx, f.blog.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := f.users.FindByID(ctx, "reader")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if liked, userLiked := slices.Contains(stored.Likes, "reader"), slices.Contains(reader.Likes, f.blog.ID); liked || userLiked {
+		t.Errorf("blog liked = %v, user liked = %v, want both false", liked, userLiked)
+	}
+	if stored.LikeCount != 0 {
+		t.Errorf("LikeCount = %d, want 0", stored.LikeCount)
+	}
+	if bookmarked, userBookmarked := slices.Contains(stored.BookmarkedBy, "reader"), slices.Contains(reader.Bookmarks, f.blog.ID); bookmarked || userBookmarked {
+		t.Errorf("blog bookmarked = %v, user bookmarked = %v, want both false", bookmarked, userBookmarked)
+	}
+	if stored.BookmarkCount != 0 {
+		t.Errorf("BookmarkCount = %d, want 0", stored.BookmarkCount)
+	}
+}
+
+func TestSetUserRolesKeepsStoredRolesWhenClaimsFail(t *testing.T) {
+	ctx := context.Background()
+
+	f := newFixture(t, func(f *fixture) {
+		f.authService = failingAuthService{f.auth}
+	})
+
+	if _, err := f.service.SetUserRoles(ctx, "admin", "author", []model.Role{model.RoleAdmin}); !errors.Is(err, errWriteFailed) {
+		t.Fatalf("SetUserRoles error = %v, want %v", err, errWriteFailed)
+	}
+
+	roles, err := f.service.GetUserRoles(ctx, "author")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(roles, model.DefaultRoles) {
+		t.Errorf("roles = %v, want %v", roles, model.DefaultRoles)
+	}
+}
+
+func TestSetUserRolesRejectsSelfDemotion(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, nil)
+
+	if _, err := f.service.SetUserRoles(ctx, "admin", "author", []model.Role{model.RoleAdmin}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := f.service.SetUserRoles(ctx, "author", "author", []model.Role{model.RoleEditor}); !errors.Is(err, user.ErrSelfModification) {
+		t.Fatalf("SetUserRoles error = %v, want %v", err, user.ErrSelfModification)
+	}
+	roles, err := f.service.GetUserRoles(ctx, "author")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(roles, []model.Role{model.RoleAdmin}) {
+		t.Errorf("roles = %v, want [admin]", roles)
+	}
+
+	// Keeping admin among the new roles is allowed
+	if _, err := f.service.SetUserRoles(ctx, "author", "author", []model.Role{model.RoleAdmin, model.RoleEditor}); err != nil {
+		t.Errorf("SetUserRoles keeping admin: %v", err)
+	}
+}
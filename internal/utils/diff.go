@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+)
+
+// MaxDiffLines is the most lines either side of the changed region of a diff
+// may have. The LCS table grows with the product of both sides, so larger
+// changes are refused instead of exhausting memory.
+const MaxDiffLines = 2000
+
+// ErrDiffTooLarge is returned when the changed region exceeds MaxDiffLines
+var ErrDiffTooLarge = errors.New("diff too large")
+
+// DiffLines computes a line-level diff from oldText to newText using the
+// longest common subsequence of lines. Common leading and trailing lines are
+// stripped first so typical edits only pay for the changed region.
+func DiffLines(oldText, newText string) ([]model.DiffLine, error) {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	a := oldLines[prefix : len(oldLines)-suffix]
+	b := newLines[prefix : len(newLines)-suffix]
+	if len(a) > MaxDiffLines || len(b) > MaxDiffLines {
+		return nil, ErrDiffTooLarge
+	}
+
+	// lcs(i, j) is the LCS length of a[i:] and b[j:]
+	width := len(b) + 1
+	table := make([]int32, (len(a)+1)*width)
+	lcs := func(i, j int) int32 { return table[i*width+j] }
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i*width+j] = lcs(i+1, j+1) + 1
+			} else {
+				table[i*width+j] = max(lcs(i+1, j), lcs(i, j+1))
+			}
+		}
+	}
+
+	diff := make([]model.DiffLine, 0, len(oldLines)+len(newLines)-prefix-suffix)
+	oldNo, newNo := 1, 1
+	equal := func(text string) {
+		diff = append(diff, model.DiffLine{Op: model.DiffEqual, Text: text, OldLine: oldNo, NewLine: newNo})
+		oldNo++
+		newNo++
+	}
+
+	for _, line := range oldLines[:prefix] {
+		equal(line)
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			equal(a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs(i+1, j) >= lcs(i, j+1)):
+			diff = append(diff, model.DiffLine{Op: model.DiffDelete, Text: a[i], OldLine: oldNo})
+			oldNo++
+			i++
+		default:
+			diff = append(diff, model.DiffLine{Op: model.DiffInsert, Text: b[j], NewLine: newNo})
+			newNo++
+			j++
+		}
+	}
+
+	for _, line := range oldLines[len(oldLines)-suffix:] {
+		equal(line)
+	}
+
+	return diff, nil
+}
+
+// splitLines splits text into lines, treating an empty text as no lines
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,122 @@
+package utils_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+)
+
+func eq(text string, oldLine, newLine int) model.DiffLine {
+	return model.DiffLine{Op: model.DiffEqual, Text: text, OldLine: oldLine, NewLine: newLine}
+}
+
+func del(text string, oldLine int) model.DiffLine {
+	return model.DiffLine{Op: model.DiffDelete, Text: text, OldLine: oldLine}
+}
+
+func ins(text string, newLine int) model.DiffLine {
+	return model.DiffLine{Op: model.DiffInsert, Text: text, NewLine: newLine}
+}
+
+func TestDiffLines(t *testing.T) {
+	cases := []struct {
+		name     string
+		old, new string
+		want     []model.DiffLine
+	}{
+		{
+			name: "BothEmpty",
+			want: []model.DiffLine{},
+		},
+		{
+			name: "Unchanged",
+			old:  "a\nb",
+			new:  "a\nb",
+			want: []model.DiffLine{eq("a", 1, 1), eq("b", 2, 2)},
+		},
+		{
+			name: "Insert",
+			old:  "a\nc",
+			new:  "a\nb\nc",
+			want: []model.DiffLine{eq("a", 1, 1), ins("b", 2), eq("c", 2, 3)},
+		},
+		{
+			name: "Delete",
+			old:  "a\nb\nc",
+			new:  "a\nc",
+			want: []model.DiffLine{eq("a", 1, 1), del("b", 2), eq("c", 3, 2)},
+		},
+		{
+			name: "Replace",
+			old:  "a\nb\nc",
+			new:  "a\nx\nc",
+			want: []model.DiffLine{eq("a", 1, 1), del("b", 2), ins("x", 2), eq("c", 3, 3)},
+		},
+		{
+			name: "InterleavedChanges",
+			old:  "a\nb\nc\nd",
+			new:  "b\nc\nx\nd",
+			want: []model.DiffLine{del("a", 1), eq("b", 2, 1), eq("c", 3, 2), ins("x", 3), eq("d", 4, 4)},
+		},
+		{
+			name: "EmptyOld",
+			new:  "a\nb",
+			want: []model.DiffLine{ins("a", 1), ins("b", 2)},
+		},
+		{
+			name: "EmptyNew",
+			old:  "a\nb",
+			want: []model.DiffLine{del("a", 1), del("b", 2)},
+		},
+		{
+			name: "TrailingNewlineAdded",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			want: []model.DiffLine{eq("a", 1, 1), eq("b", 2, 2), ins("", 3)},
+		},
+		{
+			name: "CRLFMatchesLF",
+			old:  "a\r\nb",
+			new:  "a\nb",
+			want: []model.DiffLine{eq("a", 1, 1), eq("b", 2, 2)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := utils.DiffLines(tc.old, tc.new)
+			if err != nil {
+				t.Fatalf("DiffLines: %v", err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("DiffLines = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesLimit(t *testing.T) {
+	lines := func(prefix string, n int) string {
+		var b strings.Builder
+		for i := range n {
+			fmt.Fprintf(&b, "%s%d\n", prefix, i)
+		}
+		return b.String()
+	}
+
+	// Only the changed region counts towards the limit
+	shared := lines("shared", 2*utils.MaxDiffLines)
+	if _, err := utils.DiffLines(shared+"old", shared+"new"); err != nil {
+		t.Errorf("small change in a large text: %v", err)
+	}
+
+	_, err := utils.DiffLines(lines("old", utils.MaxDiffLines+1), lines("new", utils.MaxDiffLines+1))
+	if !errors.Is(err, utils.ErrDiffTooLarge) {
+		t.Errorf("large change = %v, want ErrDiffTooLarge", err)
+	}
+}
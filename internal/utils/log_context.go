@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"context"
+
 	"go.uber.org/zap"
 )
 
@@ -50,3 +52,21 @@ func (lc *LogContext) With(fields ...interface{}) *LogContext {
 		logger: lc.logger.With(fields...),
 	}
 }
+
+// logContextKey is the context key of the request-scoped LogContext
+type logContextKey struct{}
+
+// ContextWithLogger returns a copy of ctx carrying the logger
+func ContextWithLogger(ctx context.Context, logger *LogContext) context.Context {
+	return context.WithValue(ctx, logContextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx by the request ID middleware,
+// so that everything logged for a request carries its request ID. Outside of
+// a request it returns a logger without fields.
+func FromContext(ctx context.Context) *LogContext {
+	if logger, ok := ctx.Value(logContextKey{}).(*LogContext); ok {
+		return logger
+	}
+	return NewLogContext()
+}
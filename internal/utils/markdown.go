@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// wordsPerMinute is the reading speed used to estimate reading time
+const wordsPerMinute = 200
+
+var (
+	// markdown converts GitHub-flavoured markdown to HTML with an id on every
+	// heading. Raw HTML in the source is dropped rather than passed through.
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	// htmlPolicy is the allowlist applied to rendered HTML. It builds on the
+	// user-generated-content policy, which strips scripts, styles and every
+	// event handler attribute, and only adds heading anchors and code
+	// language classes.
+	htmlPolicy = newHTMLPolicy()
+)
+
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("id").
+		Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)).
+		OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("class").
+		Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).
+		OnElements("code")
+	return policy
+}
+
+// RenderMarkdown renders markdown into sanitized HTML together with a table
+// of contents built from its headings and an estimated reading time
+func RenderMarkdown(content string) (*model.RenderedContent, error) {
+	source := []byte(content)
+	doc := markdown.Parser().Parse(text.NewReader(source))
+
+	toc := []model.TOCEntry{}
+	err := ast.Walk(doc, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		anchor, _ := heading.AttributeString("id")
+		id, _ := anchor.([]byte)
+		toc = append(toc, model.TOCEntry{
+			Level:  heading.Level,
+			Title:  nodeText(heading, source),
+			Anchor: string(id),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, source, doc); err != nil {
+		return nil, err
+	}
+
+	return &model.RenderedContent{
+		HTML:               htmlPolicy.Sanitize(buf.String()),
+		TOC:                toc,
+		ReadingTimeMinutes: ReadingTime(content),
+	}, nil
+}
+
+// ReadingTime estimates the minutes needed to read a text, at least one
+func ReadingTime(content string) int {
+	words := len(strings.Fields(content))
+	minutes := (words + wordsPerMinute - 1) / wordsPerMinute
+	if minutes < 1 {
+		return 1
+	}
+	return minutes
+}
+
+// nodeText concatenates the plain text of a node's descendants
+func nodeText(node ast.Node, source []byte) string {
+	var sb strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch n := child.(type) {
+		case *ast.Text:
+			sb.Write(n.Segment.Value(source))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(n.Value)
+		default:
+			sb.WriteString(nodeText(child, source))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dksensei/letsnormalizeit/internal/model"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		html    string
+	}{
+		{
+			name:    "ScriptBlock",
+			content: "<script>alert(1)</script>\n\nhi",
+			html:    "\n<p>hi</p>\n",
+		},
+		{
+			name:    "InlineScript",
+			content: "hello <script>alert(1)</script> there",
+			html:    "<p>hello alert(1) there</p>\n",
+		},
+		{
+			name:    "EventHandlers",
+			content: `hi <img src="x.png" onerror="alert(1)"> <a href="/x" onclick="evil()">x</a>`,
+			html:    "<p>hi  x</p>\n",
+		},
+		{
+			name:    "JavaScriptLink",
+			content: "[x](javascript:alert(1))",
+			html:    "<p>x</p>\n",
+		},
+		{
+			name:    "MixedCaseJavaScriptLink",
+			content: "[x](JaVaScRiPt:alert(1))",
+			html:    "<p>x</p>\n",
+		},
+		{
+			name:    "DataLink",
+			content: "[x](data:text/html;base64,PHNjcmlwdD4=)",
+			html:    "<p>x</p>\n",
+		},
+		{
+			name:    "DataImage",
+			content: "![x](data:image/png;base64,AAAA)",
+			html:    "<p><img alt=\"x\"></p>\n",
+		},
+		{
+			name:    "SafeLink",
+			content: "[x](https://example.com)",
+			html:    "<p><a href=\"https://example.com\" rel=\"nofollow\">x</a></p>\n",
+		},
+		{
+			name:    "RawHTMLBlock",
+			content: "<div onclick=\"x\">\n<b>bold</b>\n</div>\n\nafter",
+			html:    "\n<p>after</p>\n",
+		},
+		{
+			name:    "HeadingIDs",
+			content: "# Hello World\n\n## Über uns",
+			html:    "<h1 id=\"hello-world\">Hello World</h1>\n<h2 id=\"ber-uns\">Über uns</h2>\n",
+		},
+		{
+			name:    "CodeLanguage",
+			content: "```c++\nx\n```",
+			html:    "<pre><code class=\"language-c++\">x\n</code></pre>\n",
+		},
+		{
+			name:    "CodeLanguageWithQuotes",
+			content: "```go\" onclick=\"x\nx\n```",
+			html:    "<pre><code>x\n</code></pre>\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rendered, err := RenderMarkdown(tc.content)
+			if err != nil {
+				t.Fatalf("RenderMarkdown: %v", err)
+			}
+			if rendered.HTML != tc.html {
+				t.Errorf("HTML = %q, want %q", rendered.HTML, tc.html)
+			}
+		})
+	}
+}
+
+// TestHTMLPolicy covers the sanitizer on its own, since goldmark already
+// drops most raw HTML before it gets there
+func TestHTMLPolicy(t *testing.T) {
+	cases := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"Script", `<p>a<script>alert(1)</script>b</p>`, `<p>ab</p>`},
+		{"StyleAndIframe", `<iframe src="https://x"></iframe><style>p{}</style><p style="color:red">x</p>`, `<p>x</p>`},
+		{"EventHandler", `<a href="https://example.com" onmouseover="x">x</a>`, `<a href="https://example.com" rel="nofollow">x</a>`},
+		{"ImageEventHandler", `<img src="data:image/png;base64,AAAA" onerror="x">`, ``},
+		{"JavaScriptLink", `<a href="javascript:alert(1)">x</a>`, `x`},
+		{"DataLink", `<a href="data:text/html,x">x</a>`, `x`},
+		{"HeadingID", `<h2 id="intro" onclick="x" class="y">t</h2>`, `<h2 id="intro">t</h2>`},
+		{"CodeLanguageClass", `<code class="language-go">x</code>`, `<code class="language-go">x</code>`},
+		{"CodeOtherClass", `<code class="evil language-go">x</code>`, `<code>x</code>`},
+		{"CodeTrailingClass", `<code class="language-go evil">x</code>`, `<code>x</code>`},
+		{"LanguageClassOffCode", `<pre class="language-go"><code>x</code></pre>`, `<pre><code>x</code></pre>`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := htmlPolicy.Sanitize(tc.html); got != tc.want {
+				t.Errorf("Sanitize = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownTOC(t *testing.T) {
+	rendered, err := RenderMarkdown("# Intro\n\ntext\n\n## Setup *fast*\n\n## Intro")
+	if err != nil {
+		t.Fatalf("RenderMarkdown: %v", err)
+	}
+	want := []model.TOCEntry{
+		{Level: 1, Title: "Intro", Anchor: "intro"},
+		{Level: 2, Title: "Setup fast", Anchor: "setup-fast"},
+		{Level: 2, Title: "Intro", Anchor: "intro-1"},
+	}
+	if !slices.Equal(rendered.TOC, want) {
+		t.Errorf("TOC = %+v, want %+v", rendered.TOC, want)
+	}
+	if rendered.ReadingTimeMinutes != 1 {
+		t.Errorf("ReadingTimeMinutes = %d, want 1", rendered.ReadingTimeMinutes)
+	}
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseQueryInt(t *testing.T) {
+	cases := []struct {
+		query   string
+		want    int64
+		wantErr bool
+	}{
+		{query: "", want: 0},
+		{query: "?limit=", want: 0},
+		{query: "?limit=25", want: 25},
+		{query: "?limit=-3", want: -3},
+		{query: "?other=7", want: 0},
+		{query: "?limit=ten", wantErr: true},
+		{query: "?limit=1.5", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/"+tc.query, nil)
+
+		got, err := ParseQueryInt(c, "limit")
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseQueryInt(%q) error = %v, wantErr %v", tc.query, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseQueryInt(%q) = %d, want %d", tc.query, got, tc.want)
+		}
+	}
+}
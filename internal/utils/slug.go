@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const maxSlugLength = 80
+
+// Slugify builds a URL slug from a title. Letters and digits of any script are
+// kept and lowercased, Latin diacritics are folded (é becomes e) and every
+// other run of characters becomes a single hyphen. Slugs are capped at
+// maxSlugLength runes, never separating a letter from its combining marks.
+// Titles with nothing usable in them yield "post".
+func Slugify(title string) string {
+	var b strings.Builder
+	length := 0
+	pendingHyphen := false
+	inWord := false
+	latinBase := false
+
+	for _, r := range norm.NFKD.String(title) {
+		// Enough to fill the slug even once decomposed Hangul syllables and
+		// combining marks are composed again
+		if length >= 4*maxSlugLength {
+			break
+		}
+		switch {
+		case unicode.Is(unicode.M, r):
+			// Combining marks split off by NFKD are dropped from Latin letters
+			// (the accent of é) but kept elsewhere, where they change the
+			// letter itself (й, が, the vowel signs of हिन्दी)
+			if inWord && !latinBase {
+				b.WriteRune(r)
+				length++
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if pendingHyphen && length > 0 {
+				b.WriteRune('-')
+				length++
+			}
+			pendingHyphen = false
+			inWord = true
+			latinBase = unicode.Is(unicode.Latin, r)
+			b.WriteRune(unicode.ToLower(r))
+			length++
+		default:
+			pendingHyphen = true
+			inWord = false
+		}
+	}
+
+	slug := []rune(norm.NFC.String(b.String()))
+	if len(slug) > maxSlugLength {
+		cut := maxSlugLength
+		for cut > 0 && unicode.Is(unicode.M, slug[cut]) {
+			cut--
+		}
+		slug = slug[:cut]
+	}
+
+	trimmed := strings.TrimRight(string(slug), "-")
+	if trimmed == "" {
+		return "post"
+	}
+	return trimmed
+}
+
+// NormalizeSlug puts a slug taken from a URL into the form Slugify produces:
+// lowercased and NFC composed, so a decomposed é or й still finds its blog
+func NormalizeSlug(slug string) string {
+	return norm.NFC.String(strings.ToLower(slug))
+}
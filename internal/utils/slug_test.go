@@ -0,0 +1,98 @@
+package utils_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/dksensei/letsnormalizeit/internal/utils"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"Plain", "Hello World", "hello-world"},
+		{"LatinAccents", "Crème Brûlée à la Carte", "creme-brulee-a-la-carte"},
+		{"LatinLettersWithoutDecomposition", "Ångström ø ß", "angstrom-ø-ß"},
+		{"CompatibilityForms", "ﬁne Ｆｕｌｌ ½", "fine-full-1-2"},
+		{"Cyrillic", "Привет, мир! Йошкар-Ола", "привет-мир-йошкар-ола"},
+		{"CJK", "日本語のブログ", "日本語のブログ"},
+		{"Hangul", "한국어 블로그", "한국어-블로그"},
+		{"Devanagari", "हिन्दी भाषा", "हिन्दी-भाषा"},
+		{"RepeatedSeparators", "a -- b___c...d", "a-b-c-d"},
+		{"LeadingAndTrailingSeparators", "  --Go 1.24!--  ", "go-1-24"},
+		{"PunctuationOnly", "!!! ??? ---", "post"},
+		{"Empty", "", "post"},
+		{"MarksOnly", "́̈", "post"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := utils.Slugify(tc.title); got != tc.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tc.title, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyLength(t *testing.T) {
+	a79 := strings.Repeat("a", 79)
+	ha79 := strings.Repeat("ह", 79)
+
+	cases := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"Fits", strings.Repeat("a", 80), strings.Repeat("a", 80)},
+		{"Truncated", strings.Repeat("a", 200), strings.Repeat("a", 80)},
+		{"NoTrailingHyphen", a79 + " b", a79},
+		// й is decomposed into и and a combining breve and composed again
+		{"ComposedLetterCountsOnce", a79 + "й", a79 + "й"},
+		{"ComposedLetterCut", a79 + "aй", a79 + "a"},
+		// The vowel sign ि stays a separate rune and goes with its letter
+		{"SpacingMarkKept", ha79 + "ि", ha79 + "ि"},
+		{"MarkNotSplitOff", ha79 + "हि", ha79},
+		{"HangulComposed", strings.Repeat("한", 100), strings.Repeat("한", 80)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := utils.Slugify(tc.title)
+			if got != tc.want {
+				t.Errorf("Slugify = %q (%d runes), want %q", got, utf8.RuneCountInString(got), tc.want)
+			}
+			if n := utf8.RuneCountInString(got); n > 80 {
+				t.Errorf("slug has %d runes", n)
+			}
+		})
+	}
+}
+
+func TestNormalizeSlug(t *testing.T) {
+	cases := []struct {
+		name string
+		slug string
+		want string
+	}{
+		{"Unchanged", "hello-world", "hello-world"},
+		{"Uppercase", "Hello-World", "hello-world"},
+		// и followed by a combining breve, as some clients send й
+		{"Decomposed", "\u0438\u0306ошкар-ола", "йошкар-ола"},
+		{"DecomposedUppercase", "\u0418\u0306ошкар-Ола", "йошкар-ола"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := utils.NormalizeSlug(tc.slug); got != tc.want {
+				t.Errorf("NormalizeSlug(%q) = %q, want %q", tc.slug, got, tc.want)
+			}
+			if got := utils.NormalizeSlug(tc.slug); got != utils.Slugify(tc.slug) {
+				t.Errorf("NormalizeSlug(%q) = %q, but Slugify gives %q", tc.slug, got, utils.Slugify(tc.slug))
+			}
+		})
+	}
+}